@@ -0,0 +1,390 @@
+/* SPDX-License-Identifier: GPL-2.0 */
+/* Copyright 2021 Authors of KubeArmor */
+
+// BPF-LSM enforcer
+//
+// Rules are keyed by the mount namespace of a container and installed by
+// enforcer/bpfEnforcer.go. Matched operations are denied with -EPERM and
+// reported to enforcer_alerts. Like system_monitor.bpf.c, this object is
+// compiled once (see BPF/Makefile) and relocated against the kernel BTF.
+
+#include "vmlinux.h"
+
+#include <bpf/bpf_helpers.h>
+#include <bpf/bpf_core_read.h>
+#include <bpf/bpf_tracing.h>
+
+char LICENSE[] SEC("license") = "GPL";
+
+// == Structures == //
+
+#define TASK_COMM_LEN        16
+#define MAX_PATH_LEN         256
+#define MAX_PATH_COMPONENTS  20
+
+#define EPERM        1
+#define AF_INET      2
+#define AF_INET6     10
+#define SOCK_RAW     3
+#define PROTO_RAW    255
+#define FMODE_WRITE  0x2
+
+#ifndef container_of
+#define container_of(ptr, type, member) ((type *)((void *)(ptr) - __builtin_offsetof(type, member)))
+#endif
+
+// the same as BPFRule* in enforcer/bpfEnforcer.go
+
+#define RULE_PROCESS  1
+#define RULE_FILE     2
+#define RULE_NETWORK  3
+
+#define RULE_BLOCK      0x1
+#define RULE_READ_ONLY  0x2
+
+typedef struct rule_key {
+    u32 mnt_ns;
+    u32 type;
+    char path[MAX_PATH_LEN];
+} rule_key_t;
+
+typedef struct net_key {
+    u32 mnt_ns;
+    u32 protocol;
+} net_key_t;
+
+typedef struct alert {
+    u64 ts;
+
+    u32 pid_ns;
+    u32 mnt_ns;
+
+    u32 host_pid;
+    u32 ppid;
+    u32 pid;
+    u32 uid;
+
+    u32 type;
+    u32 flags;
+
+    u32 family;
+    u32 sock_type;
+    u32 protocol;
+
+    s32 retval;
+
+    char comm[TASK_COMM_LEN];
+    char path[MAX_PATH_LEN];
+} alert_t;
+
+typedef struct bufs {
+    char path[MAX_PATH_LEN * 2];
+    rule_key_t key;
+    alert_t alert;
+} bufs_t;
+
+// mount namespace -> (1 << RULE_*) of the rules in the namespace
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 10240);
+    __type(key, u32);
+    __type(value, u32);
+} enforcer_containers SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 65536);
+    __type(key, rule_key_t);
+    __type(value, u32);
+} enforcer_rules SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 10240);
+    __type(key, net_key_t);
+    __type(value, u32);
+} enforcer_net_rules SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(max_entries, 1);
+    __type(key, u32);
+    __type(value, bufs_t);
+} enforcer_bufs SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(u32));
+} enforcer_alerts SEC(".maps");
+
+// == Kernel Helpers == //
+
+static __always_inline u32 get_task_pid_ns_id(struct task_struct *task)
+{
+    return BPF_CORE_READ(task, nsproxy, pid_ns_for_children, ns.inum);
+}
+
+static __always_inline u32 get_task_mnt_ns_id(struct task_struct *task)
+{
+    return BPF_CORE_READ(task, nsproxy, mnt_ns, ns.inum);
+}
+
+static __always_inline u32 get_pid_nr(struct pid *pid, unsigned int level)
+{
+    struct upid upid = {};
+
+    bpf_core_read(&upid, sizeof(upid), &pid->numbers[level]);
+
+    return upid.nr;
+}
+
+static __always_inline u32 get_task_ns_ppid(struct task_struct *task)
+{
+    unsigned int level = BPF_CORE_READ(task, real_parent, nsproxy, pid_ns_for_children, level);
+
+    return get_pid_nr(BPF_CORE_READ(task, real_parent, thread_pid), level);
+}
+
+static __always_inline u32 get_task_ns_tgid(struct task_struct *task)
+{
+    unsigned int level = BPF_CORE_READ(task, nsproxy, pid_ns_for_children, level);
+
+    return get_pid_nr(BPF_CORE_READ(task, group_leader, thread_pid), level);
+}
+
+static __always_inline u32 get_rule_types(u32 mnt_ns)
+{
+    u32 *types = bpf_map_lookup_elem(&enforcer_containers, &mnt_ns);
+    if (types == NULL) {
+        return 0;
+    }
+
+    return *types;
+}
+
+static __always_inline bufs_t *get_bufs()
+{
+    u32 zero = 0;
+
+    return bpf_map_lookup_elem(&enforcer_bufs, &zero);
+}
+
+// == Paths == //
+
+static __always_inline struct mount *real_mount(struct vfsmount *mnt)
+{
+    return container_of(mnt, struct mount, mnt);
+}
+
+// writes the path of a file (seen from its mount namespace) to bufs->key.path
+// (returns 0 if the path is too long or too deep to match any rule)
+static __always_inline int get_path_str(struct path *path, bufs_t *bufs)
+{
+    struct dentry *dentry = BPF_CORE_READ(path, dentry);
+    struct vfsmount *vfsmnt = BPF_CORE_READ(path, mnt);
+    struct mount *mnt = real_mount(vfsmnt);
+    struct mount *mnt_parent = BPF_CORE_READ(mnt, mnt_parent);
+
+    u32 offset = MAX_PATH_LEN;
+    int found = 0;
+
+#pragma unroll
+    for (int i = 0; i < MAX_PATH_COMPONENTS; i++) {
+        struct dentry *mnt_root = BPF_CORE_READ(vfsmnt, mnt_root);
+        struct dentry *d_parent = BPF_CORE_READ(dentry, d_parent);
+
+        if (dentry == mnt_root || dentry == d_parent) {
+            if (dentry != mnt_root || mnt == mnt_parent) {
+                // the root of the mount namespace
+                found = 1;
+                break;
+            }
+
+            // continue from the mountpoint in the parent mount
+            dentry = BPF_CORE_READ(mnt, mnt_mountpoint);
+            mnt = mnt_parent;
+            mnt_parent = BPF_CORE_READ(mnt, mnt_parent);
+            vfsmnt = &mnt->mnt;
+
+            continue;
+        }
+
+        struct qstr d_name = BPF_CORE_READ(dentry, d_name);
+
+        // keep the trailing NUL in key.path
+        if (d_name.len == 0 || d_name.len + 1 >= offset) {
+            return 0;
+        }
+
+        offset -= d_name.len + 1;
+
+        bufs->path[offset & (MAX_PATH_LEN - 1)] = '/';
+        bpf_probe_read_kernel(&bufs->path[(offset & (MAX_PATH_LEN - 1)) + 1], d_name.len & (MAX_PATH_LEN - 1), d_name.name);
+
+        dentry = d_parent;
+    }
+
+    if (!found) {
+        return 0;
+    }
+
+    if (offset == MAX_PATH_LEN) {
+        offset -= 1;
+        bufs->path[offset & (MAX_PATH_LEN - 1)] = '/';
+    }
+
+    __builtin_memset(bufs->key.path, 0, sizeof(bufs->key.path));
+    bpf_probe_read_kernel(bufs->key.path, (MAX_PATH_LEN - offset) & (MAX_PATH_LEN - 1), &bufs->path[offset & (MAX_PATH_LEN - 1)]);
+
+    return 1;
+}
+
+// == Alerts == //
+
+static __always_inline void submit_alert(void *ctx, bufs_t *bufs, u32 mnt_ns, u32 type, u32 flags)
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+    alert_t *alert = &bufs->alert;
+
+    alert->ts = bpf_ktime_get_ns();
+
+    alert->pid_ns = get_task_pid_ns_id(task);
+    alert->mnt_ns = mnt_ns;
+
+    alert->host_pid = bpf_get_current_pid_tgid() >> 32;
+    alert->ppid = get_task_ns_ppid(task);
+    alert->pid = get_task_ns_tgid(task);
+    alert->uid = bpf_get_current_uid_gid();
+
+    alert->type = type;
+    alert->flags = flags;
+
+    alert->retval = -EPERM;
+
+    bpf_get_current_comm(&alert->comm, sizeof(alert->comm));
+
+    bpf_perf_event_output(ctx, &enforcer_alerts, BPF_F_CURRENT_CPU, alert, sizeof(alert_t));
+}
+
+// == Enforcement == //
+
+static __always_inline int enforce_path(void *ctx, u32 type, struct path *path, int write)
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+    u32 mnt_ns = get_task_mnt_ns_id(task);
+
+    // skip the namespaces without rules (e.g., the host)
+    if (!(get_rule_types(mnt_ns) & (1 << type))) {
+        return 0;
+    }
+
+    bufs_t *bufs = get_bufs();
+    if (bufs == NULL) {
+        return 0;
+    }
+
+    if (!get_path_str(path, bufs)) {
+        return 0;
+    }
+
+    bufs->key.mnt_ns = mnt_ns;
+    bufs->key.type = type;
+
+    u32 *flags = bpf_map_lookup_elem(&enforcer_rules, &bufs->key);
+    if (flags == NULL || !(*flags & RULE_BLOCK)) {
+        return 0;
+    }
+
+    // read-only files can still be opened for reading
+    if ((*flags & RULE_READ_ONLY) && !write) {
+        return 0;
+    }
+
+    bufs->alert.family = 0;
+    bufs->alert.sock_type = 0;
+    bufs->alert.protocol = 0;
+
+    __builtin_memcpy(bufs->alert.path, bufs->key.path, sizeof(bufs->alert.path));
+
+    submit_alert(ctx, bufs, mnt_ns, type, *flags);
+
+    return -EPERM;
+}
+
+SEC("lsm/bprm_check_security")
+int BPF_PROG(enforce_proc, struct linux_binprm *bprm, int ret)
+{
+    if (ret != 0) {
+        return ret;
+    }
+
+    struct file *file = BPF_CORE_READ(bprm, file);
+
+    return enforce_path(ctx, RULE_PROCESS, &file->f_path, 0);
+}
+
+SEC("lsm/file_open")
+int BPF_PROG(enforce_file, struct file *file, int ret)
+{
+    if (ret != 0) {
+        return ret;
+    }
+
+    fmode_t mode = BPF_CORE_READ(file, f_mode);
+
+    return enforce_path(ctx, RULE_FILE, &file->f_path, mode & FMODE_WRITE);
+}
+
+SEC("lsm/socket_connect")
+int BPF_PROG(enforce_net, struct socket *sock, struct sockaddr *address, int addrlen, int ret)
+{
+    if (ret != 0) {
+        return ret;
+    }
+
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+    u32 mnt_ns = get_task_mnt_ns_id(task);
+
+    if (!(get_rule_types(mnt_ns) & (1 << RULE_NETWORK))) {
+        return 0;
+    }
+
+    u16 family = BPF_CORE_READ(address, sa_family);
+    if (family != AF_INET && family != AF_INET6) {
+        return 0;
+    }
+
+    u16 sock_type = BPF_CORE_READ(sock, type);
+    u16 protocol = BPF_CORE_READ(sock, sk, sk_protocol);
+
+    net_key_t key = {};
+
+    key.mnt_ns = mnt_ns;
+    key.protocol = protocol;
+
+    u32 *flags = bpf_map_lookup_elem(&enforcer_net_rules, &key);
+    if (flags == NULL && sock_type == SOCK_RAW) {
+        key.protocol = PROTO_RAW;
+        flags = bpf_map_lookup_elem(&enforcer_net_rules, &key);
+    }
+
+    if (flags == NULL || !(*flags & RULE_BLOCK)) {
+        return 0;
+    }
+
+    bufs_t *bufs = get_bufs();
+    if (bufs == NULL) {
+        return -EPERM;
+    }
+
+    bufs->alert.family = family;
+    bufs->alert.sock_type = sock_type;
+    bufs->alert.protocol = protocol;
+
+    __builtin_memset(bufs->alert.path, 0, sizeof(bufs->alert.path));
+
+    submit_alert(ctx, bufs, mnt_ns, RULE_NETWORK, *flags);
+
+    return -EPERM;
+}
@@ -0,0 +1,1371 @@
+/* SPDX-License-Identifier: GPL-2.0 */
+/* Copyright 2021 Authors of KubeArmor */
+
+// ========================================================== //
+// KubeArmor utilizes Tracee's system call handling functions //
+// developed by Aqua Security (https://aquasec.com).          //
+// ========================================================== //
+
+// CO-RE port of system_monitor.c
+//
+// This object is compiled once (see BPF/Makefile) and relocated against the
+// BTF of the running kernel when it is loaded, so no kernel headers or
+// runtime compilation are needed. The events written to sys_events have the
+// same layout as the ones from system_monitor.c.
+
+#include "vmlinux.h"
+
+#include <bpf/bpf_helpers.h>
+#include <bpf/bpf_core_read.h>
+#include <bpf/bpf_tracing.h>
+
+char LICENSE[] SEC("license") = "GPL";
+
+// == Structures == //
+
+#define TASK_COMM_LEN      16
+#define PROC_PID_INIT_INO  0xEffffffcU
+
+#define AF_UNIX   1
+#define AF_INET   2
+#define AF_INET6  10
+
+#define SOCKADDR_UN_SIZE   110
+#define SOCKADDR_IN_SIZE   16
+#define SOCKADDR_IN6_SIZE  28
+
+#define MAX_BUFFER_SIZE   32768
+#define MAX_STRING_SIZE   4096
+#define MAX_STR_ARR_ELEM  20
+
+#define NONE_T        0UL
+#define INT_T         1UL
+#define STR_T         10UL
+#define STR_ARR_T     11UL
+#define SOCKADDR_T    12UL
+#define OPEN_FLAGS_T  13UL
+#define EXEC_FLAGS_T  14UL
+#define SOCK_DOM_T    15UL
+#define SOCK_TYPE_T   16UL
+#define MODE_T        19UL
+#define AT_FLAGS_T    20UL
+#define MOUNT_FLAGS_T 21UL
+#define UMOUNT_FLAGS_T 22UL
+#define PTRACE_REQ_T  23UL
+#define NS_FLAGS_T    24UL
+
+#define MAX_ARGS               6
+#define ENC_ARG_TYPE(n, type)  type<<(8*n)
+#define ARG_TYPE0(type)        ENC_ARG_TYPE(0, type)
+#define ARG_TYPE1(type)        ENC_ARG_TYPE(1, type)
+#define ARG_TYPE2(type)        ENC_ARG_TYPE(2, type)
+#define ARG_TYPE3(type)        ENC_ARG_TYPE(3, type)
+#define ARG_TYPE4(type)        ENC_ARG_TYPE(4, type)
+#define ARG_TYPE5(type)        ENC_ARG_TYPE(5, type)
+#define DEC_ARG_TYPE(n, type)  ((type>>(8*n))&0xFF)
+
+enum {
+    // file
+    _SYS_OPEN = 2,
+    _SYS_OPENAT = 257,
+    _SYS_CLOSE = 3,
+    _SYS_UNLINK = 87,
+    _SYS_UNLINKAT = 263,
+    _SYS_RENAME = 82,
+    _SYS_RENAMEAT = 264,
+    _SYS_RENAMEAT2 = 316,
+    _SYS_CHMOD = 90,
+    _SYS_FCHMODAT = 268,
+    _SYS_CHOWN = 92,
+    _SYS_FCHOWN = 93,
+    _SYS_LCHOWN = 94,
+    _SYS_FCHOWNAT = 260,
+
+    // mount
+    _SYS_MOUNT = 165,
+    _SYS_UMOUNT2 = 166,
+
+    // network
+    _SYS_SOCKET = 41,
+    _SYS_CONNECT = 42,
+    _SYS_ACCEPT = 43,
+    _SYS_BIND = 49,
+    _SYS_LISTEN = 50,
+
+    // process
+    _SYS_EXECVE = 59,
+    _SYS_EXECVEAT = 322,
+    _DO_EXIT = 351,
+    _SYS_PTRACE = 101,
+
+    // privilege
+    _SYS_SETUID = 105,
+    _SYS_SETGID = 106,
+    _SYS_SETNS = 308,
+    _SYS_UNSHARE = 272,
+};
+
+typedef struct __attribute__((__packed__)) sys_context {
+    u64 ts;
+
+    u32 pid_id;
+    u32 mnt_id;
+
+    u32 host_ppid;
+    u32 host_pid;
+
+    u32 ppid;
+    u32 pid;
+    u32 uid;
+
+    u32 event_id;
+    u32 argnum;
+    s64 retval;
+
+    char comm[TASK_COMM_LEN];
+} sys_context_t;
+
+typedef struct args {
+    unsigned long args[6];
+} args_t;
+
+typedef struct buffers {
+    u8 buf[MAX_BUFFER_SIZE];
+} bufs_t;
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 10240);
+    __type(key, u32);
+    __type(value, u32);
+} pid_ns_map SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 10240);
+    __type(key, u64);
+    __type(value, args_t);
+} args_map SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(max_entries, 1);
+    __type(key, u32);
+    __type(value, bufs_t);
+} bufs SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(max_entries, 1);
+    __type(key, u32);
+    __type(value, u32);
+} bufs_offset SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(u32));
+} sys_events SEC(".maps");
+
+#define VIS_PROCESS      0x1
+#define VIS_FILE         0x2
+#define VIS_NETWORK      0x4
+#define VIS_CAPABILITIES 0x8
+#define VIS_UNTRACKED    0x100
+
+typedef struct ns_key {
+    u32 pid_ns;
+    u32 mnt_ns;
+} ns_key_t;
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __uint(max_entries, 10240);
+    __type(key, ns_key_t);
+    __type(value, u32);
+} visibility_map SEC(".maps");
+
+// == Kernel Helpers == //
+
+// kernels with BTF (5.2+) always have task_struct->thread_pid (4.19+)
+
+static __always_inline u32 get_task_pid_ns_id(struct task_struct *task)
+{
+    return BPF_CORE_READ(task, nsproxy, pid_ns_for_children, ns.inum);
+}
+
+static __always_inline u32 get_task_mnt_ns_id(struct task_struct *task)
+{
+    return BPF_CORE_READ(task, nsproxy, mnt_ns, ns.inum);
+}
+
+static __always_inline u32 get_pid_nr(struct pid *pid, unsigned int level)
+{
+    struct upid upid = {};
+
+    bpf_core_read(&upid, sizeof(upid), &pid->numbers[level]);
+
+    return upid.nr;
+}
+
+static __always_inline u32 get_task_ns_ppid(struct task_struct *task)
+{
+    unsigned int level = BPF_CORE_READ(task, real_parent, nsproxy, pid_ns_for_children, level);
+
+    return get_pid_nr(BPF_CORE_READ(task, real_parent, thread_pid), level);
+}
+
+static __always_inline u32 get_task_ns_tgid(struct task_struct *task)
+{
+    unsigned int level = BPF_CORE_READ(task, nsproxy, pid_ns_for_children, level);
+
+    return get_pid_nr(BPF_CORE_READ(task, group_leader, thread_pid), level);
+}
+
+static __always_inline u32 get_task_ns_pid(struct task_struct *task)
+{
+    unsigned int level = BPF_CORE_READ(task, nsproxy, pid_ns_for_children, level);
+
+    return get_pid_nr(BPF_CORE_READ(task, thread_pid), level);
+}
+
+static __always_inline u32 get_task_ppid(struct task_struct *task)
+{
+    return BPF_CORE_READ(task, real_parent, pid);
+}
+
+// == Pid NS Management == //
+
+static __always_inline u32 add_pid_ns()
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+    u32 one = 1;
+
+#if defined(MONITOR_HOST)
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (pid_ns != PROC_PID_INIT_INO) {
+        return 0;
+    }
+
+    u32 pid = bpf_get_current_pid_tgid() >> 32;
+    if (bpf_map_lookup_elem(&pid_ns_map, &pid) != 0) {
+        return pid;
+    }
+
+    bpf_map_update_elem(&pid_ns_map, &pid, &one, BPF_ANY);
+    return pid;
+
+#else /* MONITOR_CONTAINER */
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (pid_ns == PROC_PID_INIT_INO) {
+        return 0;
+    }
+
+    if (bpf_map_lookup_elem(&pid_ns_map, &pid_ns) != 0) {
+        return pid_ns;
+    }
+
+    bpf_map_update_elem(&pid_ns_map, &pid_ns, &one, BPF_ANY);
+    return pid_ns;
+
+#endif /* MONITOR_HOST || MONITOR_CONTAINER */
+}
+
+static __always_inline u32 remove_pid_ns()
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+#if defined(MONITOR_HOST)
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (pid_ns != PROC_PID_INIT_INO) {
+        return 0;
+    }
+
+    u32 pid = bpf_get_current_pid_tgid() >> 32;
+    if (bpf_map_lookup_elem(&pid_ns_map, &pid) != 0) {
+        bpf_map_delete_elem(&pid_ns_map, &pid);
+        return 0;
+    }
+
+#else /* !MONITOR_HOST */
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (pid_ns == PROC_PID_INIT_INO) {
+        return 0;
+    }
+
+    if (get_task_ns_pid(task) == 1) {
+        bpf_map_delete_elem(&pid_ns_map, &pid_ns);
+        return 0;
+    }
+
+#endif /* !MONITOR_HOST */
+
+    return 0;
+}
+
+static __always_inline u32 skip_syscall()
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+#if defined(MONITOR_HOST)
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (pid_ns != PROC_PID_INIT_INO) {
+        return 1;
+    }
+
+    u32 pid = bpf_get_current_pid_tgid() >> 32;
+    if (bpf_map_lookup_elem(&pid_ns_map, &pid) != 0) {
+        return 0;
+    }
+
+#else /* !MONITOR_HOST */
+
+    u32 pid_ns = get_task_pid_ns_id(task);
+    if (bpf_map_lookup_elem(&pid_ns_map, &pid_ns) != 0) {
+        return 0;
+    }
+
+#endif /* !MONITOR_HOST */
+
+    return 1;
+}
+
+// == Visibility == //
+
+// the visibility of events in each container, set by KubeArmor (see monitor/visibilityMap.go)
+// containers without an entry (e.g., the ones not yet known to KubeArmor) are fully visible
+
+static __always_inline u32 get_event_visibility(u32 event_id)
+{
+    switch (event_id) {
+    case _SYS_OPEN:
+    case _SYS_OPENAT:
+    case _SYS_CLOSE:
+    case _SYS_UNLINK:
+    case _SYS_UNLINKAT:
+    case _SYS_RENAME:
+    case _SYS_RENAMEAT:
+    case _SYS_RENAMEAT2:
+    case _SYS_CHMOD:
+    case _SYS_FCHMODAT:
+    case _SYS_CHOWN:
+    case _SYS_FCHOWN:
+    case _SYS_LCHOWN:
+    case _SYS_FCHOWNAT:
+        return VIS_FILE;
+    case _SYS_SOCKET:
+    case _SYS_CONNECT:
+    case _SYS_ACCEPT:
+    case _SYS_BIND:
+    case _SYS_LISTEN:
+        return VIS_NETWORK;
+    case _SYS_MOUNT:
+    case _SYS_UMOUNT2:
+    case _SYS_PTRACE:
+    case _SYS_SETUID:
+    case _SYS_SETGID:
+    case _SYS_SETNS:
+    case _SYS_UNSHARE:
+        return VIS_PROCESS;
+    default: // executions and exits are always needed for process trees
+        return 0;
+    }
+}
+
+static __always_inline u32 skip_event(u32 event_id, s64 retval)
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+    ns_key_t key = {};
+    key.pid_ns = get_task_pid_ns_id(task);
+    key.mnt_ns = get_task_mnt_ns_id(task);
+
+    u32 *flags = bpf_map_lookup_elem(&visibility_map, &key);
+    if (flags == 0) {
+        return 0;
+    }
+
+    // no events from the containers in untracked namespaces
+    if (*flags & VIS_UNTRACKED) {
+        return 1;
+    }
+
+    // failed events are always reported (e.g., blocked by AppArmor)
+    if (retval < 0) {
+        return 0;
+    }
+
+    u32 visibility = get_event_visibility(event_id);
+    if (visibility == 0) {
+        return 0;
+    }
+
+    return (*flags & visibility) == 0;
+}
+
+// == Context Management == //
+
+static __always_inline u32 init_context(sys_context_t *context)
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+    context->ts = bpf_ktime_get_ns();
+
+    context->host_ppid = get_task_ppid(task);
+    context->host_pid = bpf_get_current_pid_tgid() >> 32;
+
+#if defined(MONITOR_HOST)
+
+    context->pid_id = 0;
+    context->mnt_id = 0;
+
+    context->ppid = get_task_ppid(task);
+    context->pid = bpf_get_current_pid_tgid() >> 32;
+
+#else /* !MONITOR_HOST */
+
+    context->pid_id = get_task_pid_ns_id(task);
+    context->mnt_id = get_task_mnt_ns_id(task);
+
+    context->ppid = get_task_ns_ppid(task);
+    context->pid = get_task_ns_tgid(task);
+
+#endif /* !MONITOR_HOST */
+
+    context->uid = bpf_get_current_uid_gid();
+
+    bpf_get_current_comm(&context->comm, sizeof(context->comm));
+
+    return 0;
+}
+
+// == Buffer Management == //
+
+static __always_inline bufs_t* get_buffer()
+{
+    u32 idx = 0;
+    return bpf_map_lookup_elem(&bufs, &idx);
+}
+
+static __always_inline void set_buffer_offset(u32 off)
+{
+    u32 idx = 0;
+    bpf_map_update_elem(&bufs_offset, &idx, &off, BPF_ANY);
+}
+
+static __always_inline u32* get_buffer_offset()
+{
+    u32 idx = 0;
+    return bpf_map_lookup_elem(&bufs_offset, &idx);
+}
+
+static __always_inline int save_context_to_buffer(bufs_t *bufs_p, void *ptr)
+{
+    if (bpf_probe_read(&(bufs_p->buf[0]), sizeof(sys_context_t), ptr) == 0) {
+        return sizeof(sys_context_t);
+    }
+
+    return 0;
+}
+
+static __always_inline int save_str_to_buffer(bufs_t *bufs_p, void *ptr)
+{
+    u32 *off = get_buffer_offset();
+    if (off == NULL) {
+        return -1;
+    }
+
+    if (*off > MAX_BUFFER_SIZE - MAX_STRING_SIZE - sizeof(int)) {
+        return 0; // not enough space - return
+    }
+
+    u8 type = STR_T;
+    bpf_probe_read(&(bufs_p->buf[*off & (MAX_BUFFER_SIZE-1)]), 1, &type);
+
+    *off += 1;
+
+    if (*off > MAX_BUFFER_SIZE - MAX_STRING_SIZE - sizeof(int)) {
+        return 0;
+    }
+
+    int sz = bpf_probe_read_str(&(bufs_p->buf[*off + sizeof(int)]), MAX_STRING_SIZE, ptr);
+    if (sz > 0) {
+        if (*off > MAX_BUFFER_SIZE - sizeof(int)) {
+            return 0;
+        }
+
+        bpf_probe_read(&(bufs_p->buf[*off]), sizeof(int), &sz);
+
+        *off += sz + sizeof(int);
+        set_buffer_offset(*off);
+
+        return sz + sizeof(int);
+    }
+
+    return 0;
+}
+
+static __always_inline int save_to_buffer(bufs_t *bufs_p, void *ptr, int size, u8 type)
+{
+    // the biggest element that can be saved with this function should be defined here
+    #define MAX_ELEMENT_SIZE SOCKADDR_UN_SIZE
+
+    if (type == 0) {
+        return 0;
+    }
+
+    u32 *off = get_buffer_offset();
+    if (off == NULL) {
+        return -1;
+    }
+
+    if (*off > MAX_BUFFER_SIZE - MAX_ELEMENT_SIZE) {
+        return 0;
+    }
+
+    if (bpf_probe_read(&(bufs_p->buf[*off]), 1, &type) != 0) {
+        return 0;
+    }
+
+    *off += 1;
+
+    if (*off > MAX_BUFFER_SIZE - MAX_ELEMENT_SIZE) {
+        return 0;
+    }
+
+    if (bpf_probe_read(&(bufs_p->buf[*off]), size, ptr) == 0) {
+        *off += size;
+        set_buffer_offset(*off);
+        return size;
+    }
+
+    return 0;
+}
+
+static __always_inline int save_argv(bufs_t *bufs_p, void *ptr)
+{
+    const char *argp = NULL;
+    bpf_probe_read(&argp, sizeof(argp), ptr);
+
+    if (argp) {
+        return save_str_to_buffer(bufs_p, (void *)(argp));
+    }
+
+    return 0;
+}
+
+static __always_inline int save_str_arr_to_buffer(bufs_t *bufs_p, const char *const *ptr)
+{
+    save_to_buffer(bufs_p, NULL, 0, STR_ARR_T);
+
+    #pragma unroll
+    for (int i = 0; i < MAX_STR_ARR_ELEM; i++) {
+        if (save_argv(bufs_p, (void *)&ptr[i]) == 0) {
+             goto out;
+        }
+    }
+
+    char ellipsis[] = "...";
+    save_str_to_buffer(bufs_p, (void *)ellipsis);
+
+out:
+    save_to_buffer(bufs_p, NULL, 0, STR_ARR_T);
+
+    return 0;
+}
+
+static __always_inline int save_args_to_buffer(u64 types, args_t *args)
+{
+    if (types == 0) {
+        return 0;
+    }
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL) {
+        return 0;
+    }
+
+    #pragma unroll
+    for (int i = 0; i < MAX_ARGS; i++) {
+        switch (DEC_ARG_TYPE(i, types)) {
+        case NONE_T:
+            break;
+        case INT_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), INT_T);
+            break;
+        case OPEN_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), OPEN_FLAGS_T);
+            break;
+        case STR_T:
+            if (args->args[i]) {
+                save_str_to_buffer(bufs_p, (void *)args->args[i]);
+            } else { // e.g., the fstype of a bind mount
+                char empty[1] = "";
+                save_str_to_buffer(bufs_p, (void *)empty);
+            }
+            break;
+        case SOCK_DOM_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), SOCK_DOM_T);
+            break;
+        case SOCK_TYPE_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), SOCK_TYPE_T);
+            break;
+        case MODE_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), MODE_T);
+            break;
+        case AT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), AT_FLAGS_T);
+            break;
+        case MOUNT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), MOUNT_FLAGS_T);
+            break;
+        case UMOUNT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), UMOUNT_FLAGS_T);
+            break;
+        case PTRACE_REQ_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), PTRACE_REQ_T);
+            break;
+        case NS_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), NS_FLAGS_T);
+            break;
+        case SOCKADDR_T:
+            if (args->args[i]) {
+                short family = 0;
+                bpf_probe_read(&family, sizeof(short), (void*)args->args[i]);
+                switch (family) {
+                case AF_UNIX:
+                    save_to_buffer(bufs_p, (void*)(args->args[i]), SOCKADDR_UN_SIZE, SOCKADDR_T);
+                    break;
+                case AF_INET:
+                    save_to_buffer(bufs_p, (void*)(args->args[i]), SOCKADDR_IN_SIZE, SOCKADDR_T);
+                    break;
+                case AF_INET6:
+                    save_to_buffer(bufs_p, (void*)(args->args[i]), SOCKADDR_IN6_SIZE, SOCKADDR_T);
+                    break;
+                default:
+                    save_to_buffer(bufs_p, (void*)&family, sizeof(short), SOCKADDR_T);
+                }
+            }
+            break;
+        }
+    }
+
+    return 0;
+}
+
+static __always_inline int events_perf_submit(struct pt_regs *ctx)
+{
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return -1;
+
+    u32 *off = get_buffer_offset();
+    if (off == NULL)
+        return -1;
+
+    void *data = bufs_p->buf;
+    int size = *off & (MAX_BUFFER_SIZE-1);
+
+    return bpf_perf_event_output(ctx, &sys_events, BPF_F_CURRENT_CPU, data, size);
+}
+
+// == Syscall Arguments == //
+
+// syscalls are attached through their wrappers (__x64_sys_*, __arm64_sys_*),
+// so the actual arguments are in the pt_regs passed as the first parameter
+
+static __always_inline struct pt_regs *get_syscall_regs(struct pt_regs *ctx)
+{
+    return (struct pt_regs *)PT_REGS_PARM1(ctx);
+}
+
+// == Syscall Hooks (Process) == //
+
+SEC("kprobe/syscall__execve")
+int syscall__execve(struct pt_regs *ctx)
+{
+    sys_context_t context = {};
+
+    struct pt_regs *regs = get_syscall_regs(ctx);
+    const char *filename = (const char *)PT_REGS_PARM1_CORE(regs);
+    const char *const *__argv = (const char *const *)PT_REGS_PARM2_CORE(regs);
+
+    if (!add_pid_ns())
+        return 0;
+
+    if (skip_event(_SYS_EXECVE, 0))
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = _SYS_EXECVE;
+    context.argnum = 2;
+    context.retval = 0;
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+
+    save_str_to_buffer(bufs_p, (void *)filename);
+    save_str_arr_to_buffer(bufs_p, __argv);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+SEC("kretprobe/trace_ret_execve")
+int trace_ret_execve(struct pt_regs *ctx)
+{
+    sys_context_t context = {};
+
+    if (skip_syscall())
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = _SYS_EXECVE;
+    context.argnum = 0;
+    context.retval = PT_REGS_RC(ctx);
+
+    // TEMP: skip if No such file or directory
+    if (context.retval == -2) {
+        return 0;
+    }
+
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+SEC("kprobe/syscall__execveat")
+int syscall__execveat(struct pt_regs *ctx)
+{
+    sys_context_t context = {};
+
+    struct pt_regs *regs = get_syscall_regs(ctx);
+    int dirfd = (int)PT_REGS_PARM1_CORE(regs);
+    const char *pathname = (const char *)PT_REGS_PARM2_CORE(regs);
+    const char *const *__argv = (const char *const *)PT_REGS_PARM3_CORE(regs);
+    int flags = (int)PT_REGS_PARM5_CORE(regs);
+
+    if (!add_pid_ns())
+        return 0;
+
+    if (skip_event(_SYS_EXECVEAT, 0))
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = _SYS_EXECVEAT;
+    context.argnum = 4;
+    context.retval = 0;
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+
+    save_to_buffer(bufs_p, (void*)&dirfd, sizeof(int), INT_T);
+    save_str_to_buffer(bufs_p, (void *)pathname);
+    save_str_arr_to_buffer(bufs_p, __argv);
+    save_to_buffer(bufs_p, (void*)&flags, sizeof(int), EXEC_FLAGS_T);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+SEC("kretprobe/trace_ret_execveat")
+int trace_ret_execveat(struct pt_regs *ctx)
+{
+    sys_context_t context = {};
+
+    if (skip_syscall())
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = _SYS_EXECVEAT;
+    context.argnum = 0;
+    context.retval = PT_REGS_RC(ctx);
+
+    // TEMP: skip if No such file or directory
+    if (context.retval == -2) {
+        return 0;
+    }
+
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+SEC("kprobe/trace_do_exit")
+int trace_do_exit(struct pt_regs *ctx)
+{
+    sys_context_t context = {};
+
+    if (skip_syscall())
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = _DO_EXIT;
+    context.argnum = 0;
+    context.retval = (long)PT_REGS_PARM1(ctx);
+
+    remove_pid_ns();
+
+    if (skip_event(_DO_EXIT, 0))
+        return 0;
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+// == Syscall Hooks (File) == //
+
+static __always_inline int save_args(u32 event_id, struct pt_regs *ctx)
+{
+    args_t args = {};
+
+    // the traced syscalls take up to five arguments
+    // (PT_REGS_PARM4 is not the 4th syscall argument on x86_64, which is in r10)
+    struct pt_regs *regs = get_syscall_regs(ctx);
+    args.args[0] = PT_REGS_PARM1_CORE(regs);
+    args.args[1] = PT_REGS_PARM2_CORE(regs);
+    args.args[2] = PT_REGS_PARM3_CORE(regs);
+#if defined(__TARGET_ARCH_x86)
+    args.args[3] = BPF_CORE_READ(regs, r10);
+    args.args[4] = BPF_CORE_READ(regs, r8);
+#else
+    args.args[3] = PT_REGS_PARM4_CORE(regs);
+    args.args[4] = PT_REGS_PARM5_CORE(regs);
+#endif
+
+    u32 tgid = bpf_get_current_pid_tgid();
+    u64 id = ((u64)event_id << 32) | tgid;
+
+    bpf_map_update_elem(&args_map, &id, &args, BPF_ANY);
+
+    return 0;
+}
+
+static __always_inline int load_args(u32 event_id, args_t *args)
+{
+    u32 tgid = bpf_get_current_pid_tgid();
+    u64 id = ((u64)event_id << 32) | tgid;
+
+    args_t *saved_args = bpf_map_lookup_elem(&args_map, &id);
+    if (saved_args == 0) {
+        return -1; // missed entry or not a container
+    }
+
+    args->args[0] = saved_args->args[0];
+    args->args[1] = saved_args->args[1];
+    args->args[2] = saved_args->args[2];
+    args->args[3] = saved_args->args[3];
+    args->args[4] = saved_args->args[4];
+    args->args[5] = saved_args->args[5];
+
+    bpf_map_delete_elem(&args_map, &id);
+
+    return 0;
+}
+
+static __always_inline int get_arg_num(u64 types)
+{
+    unsigned int i, argnum = 0;
+
+    #pragma unroll
+    for(i = 0; i < MAX_ARGS; i++) {
+        if (DEC_ARG_TYPE(i, types) != NONE_T)
+            argnum++;
+    }
+
+    return argnum;
+}
+
+static __always_inline int trace_ret_generic(u32 id, struct pt_regs *ctx, u64 types)
+{
+    sys_context_t context = {};
+    args_t args = {};
+
+    if (load_args(id, &args) != 0)
+        return 0;
+
+    if (skip_syscall())
+        return 0;
+
+    init_context(&context);
+
+    context.event_id = id;
+    context.argnum = get_arg_num(types);
+    context.retval = PT_REGS_RC(ctx);
+
+    // TEMP: skip if No such file or directory
+    if (context.retval == -2) {
+        return 0;
+    }
+
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
+    set_buffer_offset(sizeof(sys_context_t));
+
+    bufs_t *bufs_p = get_buffer();
+    if (bufs_p == NULL)
+        return 0;
+
+    save_context_to_buffer(bufs_p, (void*)&context);
+    save_args_to_buffer(types, &args);
+
+    events_perf_submit(ctx);
+
+    return 0;
+}
+
+SEC("kprobe/syscall__open")
+int syscall__open(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_OPEN, ctx);
+}
+
+SEC("kretprobe/trace_ret_open")
+int trace_ret_open(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_OPEN, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(OPEN_FLAGS_T));
+}
+
+SEC("kprobe/syscall__openat")
+int syscall__openat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_OPENAT, ctx);
+}
+
+SEC("kretprobe/trace_ret_openat")
+int trace_ret_openat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_OPENAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(OPEN_FLAGS_T));
+}
+
+SEC("kprobe/syscall__close")
+int syscall__close(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CLOSE, ctx);
+}
+
+SEC("kretprobe/trace_ret_close")
+int trace_ret_close(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CLOSE, ctx, ARG_TYPE0(INT_T));
+}
+
+SEC("kprobe/syscall__unlink")
+int syscall__unlink(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNLINK, ctx);
+}
+
+SEC("kretprobe/trace_ret_unlink")
+int trace_ret_unlink(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNLINK, ctx, ARG_TYPE0(STR_T));
+}
+
+SEC("kprobe/syscall__unlinkat")
+int syscall__unlinkat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNLINKAT, ctx);
+}
+
+SEC("kretprobe/trace_ret_unlinkat")
+int trace_ret_unlinkat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNLINKAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(AT_FLAGS_T));
+}
+
+SEC("kprobe/syscall__rename")
+int syscall__rename(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAME, ctx);
+}
+
+SEC("kretprobe/trace_ret_rename")
+int trace_ret_rename(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAME, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(STR_T));
+}
+
+SEC("kprobe/syscall__renameat")
+int syscall__renameat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAMEAT, ctx);
+}
+
+SEC("kretprobe/trace_ret_renameat")
+int trace_ret_renameat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAMEAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(STR_T));
+}
+
+SEC("kprobe/syscall__renameat2")
+int syscall__renameat2(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAMEAT2, ctx);
+}
+
+SEC("kretprobe/trace_ret_renameat2")
+int trace_ret_renameat2(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAMEAT2, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(STR_T));
+}
+
+SEC("kprobe/syscall__chmod")
+int syscall__chmod(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CHMOD, ctx);
+}
+
+SEC("kretprobe/trace_ret_chmod")
+int trace_ret_chmod(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CHMOD, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(MODE_T));
+}
+
+SEC("kprobe/syscall__fchmodat")
+int syscall__fchmodat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHMODAT, ctx);
+}
+
+SEC("kretprobe/trace_ret_fchmodat")
+int trace_ret_fchmodat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHMODAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(MODE_T));
+}
+
+SEC("kprobe/syscall__chown")
+int syscall__chown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CHOWN, ctx);
+}
+
+SEC("kretprobe/trace_ret_chown")
+int trace_ret_chown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CHOWN, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+SEC("kprobe/syscall__fchown")
+int syscall__fchown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHOWN, ctx);
+}
+
+SEC("kretprobe/trace_ret_fchown")
+int trace_ret_fchown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHOWN, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+SEC("kprobe/syscall__lchown")
+int syscall__lchown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_LCHOWN, ctx);
+}
+
+SEC("kretprobe/trace_ret_lchown")
+int trace_ret_lchown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_LCHOWN, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+SEC("kprobe/syscall__fchownat")
+int syscall__fchownat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHOWNAT, ctx);
+}
+
+SEC("kretprobe/trace_ret_fchownat")
+int trace_ret_fchownat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHOWNAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(INT_T)|ARG_TYPE4(AT_FLAGS_T));
+}
+
+// == Syscall Hooks (Network) == //
+
+SEC("kprobe/syscall__socket")
+int syscall__socket(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SOCKET, ctx);
+}
+
+SEC("kretprobe/trace_ret_socket")
+int trace_ret_socket(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SOCKET, ctx, ARG_TYPE0(SOCK_DOM_T)|ARG_TYPE1(SOCK_TYPE_T)|ARG_TYPE2(INT_T));
+}
+
+SEC("kprobe/syscall__connect")
+int syscall__connect(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CONNECT, ctx);
+}
+
+SEC("kretprobe/trace_ret_connect")
+int trace_ret_connect(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CONNECT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(SOCKADDR_T));
+}
+
+SEC("kprobe/syscall__accept")
+int syscall__accept(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_ACCEPT, ctx);
+}
+
+SEC("kretprobe/trace_ret_accept")
+int trace_ret_accept(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_ACCEPT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(SOCKADDR_T));
+}
+
+SEC("kprobe/syscall__bind")
+int syscall__bind(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_BIND, ctx);
+}
+
+SEC("kretprobe/trace_ret_bind")
+int trace_ret_bind(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_BIND, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(SOCKADDR_T));
+}
+
+SEC("kprobe/syscall__listen")
+int syscall__listen(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_LISTEN, ctx);
+}
+
+SEC("kretprobe/trace_ret_listen")
+int trace_ret_listen(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_LISTEN, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(INT_T));
+}
+
+// == Syscall Hooks (Mount) == //
+
+SEC("kprobe/syscall__mount")
+int syscall__mount(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_MOUNT, ctx);
+}
+
+SEC("kretprobe/trace_ret_mount")
+int trace_ret_mount(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_MOUNT, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(STR_T)|ARG_TYPE3(MOUNT_FLAGS_T));
+}
+
+SEC("kprobe/syscall__umount")
+int syscall__umount(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UMOUNT2, ctx);
+}
+
+SEC("kretprobe/trace_ret_umount")
+int trace_ret_umount(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UMOUNT2, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(UMOUNT_FLAGS_T));
+}
+
+// == Syscall Hooks (Privilege) == //
+
+SEC("kprobe/syscall__ptrace")
+int syscall__ptrace(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_PTRACE, ctx);
+}
+
+SEC("kretprobe/trace_ret_ptrace")
+int trace_ret_ptrace(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_PTRACE, ctx, ARG_TYPE0(PTRACE_REQ_T)|ARG_TYPE1(INT_T));
+}
+
+SEC("kprobe/syscall__setuid")
+int syscall__setuid(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETUID, ctx);
+}
+
+SEC("kretprobe/trace_ret_setuid")
+int trace_ret_setuid(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETUID, ctx, ARG_TYPE0(INT_T));
+}
+
+SEC("kprobe/syscall__setgid")
+int syscall__setgid(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETGID, ctx);
+}
+
+SEC("kretprobe/trace_ret_setgid")
+int trace_ret_setgid(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETGID, ctx, ARG_TYPE0(INT_T));
+}
+
+SEC("kprobe/syscall__setns")
+int syscall__setns(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETNS, ctx);
+}
+
+SEC("kretprobe/trace_ret_setns")
+int trace_ret_setns(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETNS, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(NS_FLAGS_T));
+}
+
+SEC("kprobe/syscall__unshare")
+int syscall__unshare(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNSHARE, ctx);
+}
+
+SEC("kretprobe/trace_ret_unshare")
+int trace_ret_unshare(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNSHARE, ctx, ARG_TYPE0(NS_FLAGS_T));
+}
@@ -44,6 +44,12 @@
 #define EXEC_FLAGS_T  14UL
 #define SOCK_DOM_T    15UL
 #define SOCK_TYPE_T   16UL
+#define MODE_T        19UL
+#define AT_FLAGS_T    20UL
+#define MOUNT_FLAGS_T 21UL
+#define UMOUNT_FLAGS_T 22UL
+#define PTRACE_REQ_T  23UL
+#define NS_FLAGS_T    24UL
 
 #define MAX_ARGS               6
 #define ENC_ARG_TYPE(n, type)  type<<(8*n)
@@ -60,6 +66,21 @@ enum {
     _SYS_OPEN = 2,
     _SYS_OPENAT = 257,
     _SYS_CLOSE = 3,
+    _SYS_UNLINK = 87,
+    _SYS_UNLINKAT = 263,
+    _SYS_RENAME = 82,
+    _SYS_RENAMEAT = 264,
+    _SYS_RENAMEAT2 = 316,
+    _SYS_CHMOD = 90,
+    _SYS_FCHMODAT = 268,
+    _SYS_CHOWN = 92,
+    _SYS_FCHOWN = 93,
+    _SYS_LCHOWN = 94,
+    _SYS_FCHOWNAT = 260,
+
+    // mount
+    _SYS_MOUNT = 165,
+    _SYS_UMOUNT2 = 166,
 
     // network
     _SYS_SOCKET = 41,
@@ -72,6 +93,13 @@ enum {
     _SYS_EXECVE = 59,
     _SYS_EXECVEAT = 322,
     _DO_EXIT = 351,
+    _SYS_PTRACE = 101,
+
+    // privilege
+    _SYS_SETUID = 105,
+    _SYS_SETGID = 106,
+    _SYS_SETNS = 308,
+    _SYS_UNSHARE = 272,
 };
 
 typedef struct __attribute__((__packed__)) sys_context {
@@ -111,6 +139,19 @@ BPF_PERCPU_ARRAY(bufs_offset, u32, 1);
 
 BPF_PERF_OUTPUT(sys_events);
 
+#define VIS_PROCESS      0x1
+#define VIS_FILE         0x2
+#define VIS_NETWORK      0x4
+#define VIS_CAPABILITIES 0x8
+#define VIS_UNTRACKED    0x100
+
+typedef struct ns_key {
+    u32 pid_ns;
+    u32 mnt_ns;
+} ns_key_t;
+
+BPF_HASH(visibility_map, ns_key_t, u32);
+
 // == Kernel Helpers == //
 
 static __always_inline u32 get_task_pid_ns_id(struct task_struct *task)
@@ -322,6 +363,79 @@ static __always_inline u32 skip_syscall()
     return 1;
 }
 
+// == Visibility == //
+
+// the visibility of events in each container, set by KubeArmor (see monitor/visibilityMap.go)
+// containers without an entry (e.g., the ones not yet known to KubeArmor) are fully visible
+
+static __always_inline u32 get_event_visibility(u32 event_id)
+{
+    switch (event_id) {
+    case _SYS_OPEN:
+    case _SYS_OPENAT:
+    case _SYS_CLOSE:
+    case _SYS_UNLINK:
+    case _SYS_UNLINKAT:
+    case _SYS_RENAME:
+    case _SYS_RENAMEAT:
+    case _SYS_RENAMEAT2:
+    case _SYS_CHMOD:
+    case _SYS_FCHMODAT:
+    case _SYS_CHOWN:
+    case _SYS_FCHOWN:
+    case _SYS_LCHOWN:
+    case _SYS_FCHOWNAT:
+        return VIS_FILE;
+    case _SYS_SOCKET:
+    case _SYS_CONNECT:
+    case _SYS_ACCEPT:
+    case _SYS_BIND:
+    case _SYS_LISTEN:
+        return VIS_NETWORK;
+    case _SYS_MOUNT:
+    case _SYS_UMOUNT2:
+    case _SYS_PTRACE:
+    case _SYS_SETUID:
+    case _SYS_SETGID:
+    case _SYS_SETNS:
+    case _SYS_UNSHARE:
+        return VIS_PROCESS;
+    default: // executions and exits are always needed for process trees
+        return 0;
+    }
+}
+
+static __always_inline u32 skip_event(u32 event_id, s64 retval)
+{
+    struct task_struct *task = (struct task_struct *)bpf_get_current_task();
+
+    ns_key_t key = {};
+    key.pid_ns = get_task_pid_ns_id(task);
+    key.mnt_ns = get_task_mnt_ns_id(task);
+
+    u32 *flags = visibility_map.lookup(&key);
+    if (flags == 0) {
+        return 0;
+    }
+
+    // no events from the containers in untracked namespaces
+    if (*flags & VIS_UNTRACKED) {
+        return 1;
+    }
+
+    // failed events are always reported (e.g., blocked by AppArmor)
+    if (retval < 0) {
+        return 0;
+    }
+
+    u32 visibility = get_event_visibility(event_id);
+    if (visibility == 0) {
+        return 0;
+    }
+
+    return (*flags & visibility) == 0;
+}
+
 // == Context Management == //
 
 static __always_inline u32 init_context(sys_context_t *context)
@@ -533,7 +647,12 @@ static __always_inline int save_args_to_buffer(u64 types, args_t *args)
             save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), OPEN_FLAGS_T);
             break;
         case STR_T:
-            save_str_to_buffer(bufs_p, (void *)args->args[i]);
+            if (args->args[i]) {
+                save_str_to_buffer(bufs_p, (void *)args->args[i]);
+            } else { // e.g., the fstype of a bind mount
+                char empty[1] = "";
+                save_str_to_buffer(bufs_p, (void *)empty);
+            }
             break;
         case SOCK_DOM_T:
             save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), SOCK_DOM_T);
@@ -541,6 +660,24 @@ static __always_inline int save_args_to_buffer(u64 types, args_t *args)
         case SOCK_TYPE_T:
             save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), SOCK_TYPE_T);
             break;
+        case MODE_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), MODE_T);
+            break;
+        case AT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), AT_FLAGS_T);
+            break;
+        case MOUNT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), MOUNT_FLAGS_T);
+            break;
+        case UMOUNT_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), UMOUNT_FLAGS_T);
+            break;
+        case PTRACE_REQ_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), PTRACE_REQ_T);
+            break;
+        case NS_FLAGS_T:
+            save_to_buffer(bufs_p, (void*)&(args->args[i]), sizeof(int), NS_FLAGS_T);
+            break;
         case SOCKADDR_T:
             if (args->args[i]) {
                 short family = 0;
@@ -594,6 +731,9 @@ int syscall__execve(struct pt_regs *ctx,
     if (!add_pid_ns())
         return 0;
 
+    if (skip_event(_SYS_EXECVE, 0))
+        return 0;
+
     init_context(&context);
 
     context.event_id = _SYS_EXECVE;
@@ -634,6 +774,11 @@ int trace_ret_execve(struct pt_regs *ctx)
         return 0;
     }
 
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
     set_buffer_offset(sizeof(sys_context_t));
 
     bufs_t *bufs_p = get_buffer();
@@ -659,6 +804,9 @@ int syscall__execveat(struct pt_regs *ctx,
     if (!add_pid_ns())
         return 0;
 
+    if (skip_event(_SYS_EXECVEAT, 0))
+        return 0;
+
     init_context(&context);
 
     context.event_id = _SYS_EXECVEAT;
@@ -701,6 +849,11 @@ int trace_ret_execveat(struct pt_regs *ctx)
         return 0;
     }
 
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
     set_buffer_offset(sizeof(sys_context_t));
 
     bufs_t *bufs_p = get_buffer();
@@ -729,6 +882,9 @@ int trace_do_exit(struct pt_regs *ctx, long code)
 
     remove_pid_ns();
 
+    if (skip_event(_DO_EXIT, 0))
+        return 0;
+
     set_buffer_offset(sizeof(sys_context_t));
 
     bufs_t *bufs_p = get_buffer();
@@ -748,7 +904,16 @@ static __always_inline int save_args(u32 event_id, struct pt_regs *ctx)
 {
     args_t args = {};
 
-#if LINUX_VERSION_CODE < KERNEL_VERSION(4, 17, 0)
+#if defined(__aarch64__) && LINUX_VERSION_CODE >= KERNEL_VERSION(4, 19, 0)
+    // __arm64_sys_* wrappers take the pt_regs of the syscall in x0
+    struct pt_regs * ctx2 = (struct pt_regs *)PT_REGS_PARM1(ctx);
+    bpf_probe_read(&args.args[0], sizeof(args.args[0]), &ctx2->regs[0]);
+    bpf_probe_read(&args.args[1], sizeof(args.args[1]), &ctx2->regs[1]);
+    bpf_probe_read(&args.args[2], sizeof(args.args[2]), &ctx2->regs[2]);
+    bpf_probe_read(&args.args[3], sizeof(args.args[3]), &ctx2->regs[3]);
+    bpf_probe_read(&args.args[4], sizeof(args.args[4]), &ctx2->regs[4]);
+    bpf_probe_read(&args.args[5], sizeof(args.args[5]), &ctx2->regs[5]);
+#elif defined(__aarch64__) || LINUX_VERSION_CODE < KERNEL_VERSION(4, 17, 0)
     args.args[0] = PT_REGS_PARM1(ctx);
     args.args[1] = PT_REGS_PARM2(ctx);
     args.args[2] = PT_REGS_PARM3(ctx);
@@ -830,6 +995,11 @@ static __always_inline int trace_ret_generic(u32 id, struct pt_regs *ctx, u64 ty
         return 0;
     }
 
+    // skip if not visible in the container
+    if (skip_event(context.event_id, context.retval)) {
+        return 0;
+    }
+
     set_buffer_offset(sizeof(sys_context_t));
 
     bufs_t *bufs_p = get_buffer();
@@ -883,6 +1053,149 @@ int trace_ret_close(struct pt_regs *ctx)
     return trace_ret_generic(_SYS_CLOSE, ctx, ARG_TYPE0(INT_T));
 }
 
+int syscall__unlink(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNLINK, ctx);
+}
+
+int trace_ret_unlink(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNLINK, ctx, ARG_TYPE0(STR_T));
+}
+
+int syscall__unlinkat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNLINKAT, ctx);
+}
+
+int trace_ret_unlinkat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNLINKAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(AT_FLAGS_T));
+}
+
+int syscall__rename(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAME, ctx);
+}
+
+int trace_ret_rename(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAME, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(STR_T));
+}
+
+int syscall__renameat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAMEAT, ctx);
+}
+
+int trace_ret_renameat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAMEAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(STR_T));
+}
+
+int syscall__renameat2(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_RENAMEAT2, ctx);
+}
+
+int trace_ret_renameat2(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_RENAMEAT2, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(STR_T));
+}
+
+int syscall__chmod(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CHMOD, ctx);
+}
+
+int trace_ret_chmod(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CHMOD, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(MODE_T));
+}
+
+int syscall__fchmodat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHMODAT, ctx);
+}
+
+int trace_ret_fchmodat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHMODAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(MODE_T));
+}
+
+int syscall__chown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_CHOWN, ctx);
+}
+
+int trace_ret_chown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_CHOWN, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+int syscall__fchown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHOWN, ctx);
+}
+
+int trace_ret_fchown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHOWN, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+int syscall__lchown(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_LCHOWN, ctx);
+}
+
+int trace_ret_lchown(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_LCHOWN, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(INT_T)|ARG_TYPE2(INT_T));
+}
+
+int syscall__fchownat(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_FCHOWNAT, ctx);
+}
+
+int trace_ret_fchownat(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_FCHOWNAT, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(INT_T)|ARG_TYPE3(INT_T)|ARG_TYPE4(AT_FLAGS_T));
+}
+
 // == Syscall Hooks (Network) == //
 
 int syscall__socket(struct pt_regs *ctx)
@@ -949,3 +1262,98 @@ int trace_ret_listen(struct pt_regs *ctx)
 {
     return trace_ret_generic(_SYS_LISTEN, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(INT_T));
 }
+
+// == Syscall Hooks (Mount) == //
+
+int syscall__mount(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_MOUNT, ctx);
+}
+
+int trace_ret_mount(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_MOUNT, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(STR_T)|ARG_TYPE2(STR_T)|ARG_TYPE3(MOUNT_FLAGS_T));
+}
+
+int syscall__umount(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UMOUNT2, ctx);
+}
+
+int trace_ret_umount(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UMOUNT2, ctx, ARG_TYPE0(STR_T)|ARG_TYPE1(UMOUNT_FLAGS_T));
+}
+
+// == Syscall Hooks (Privilege) == //
+
+int syscall__ptrace(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_PTRACE, ctx);
+}
+
+int trace_ret_ptrace(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_PTRACE, ctx, ARG_TYPE0(PTRACE_REQ_T)|ARG_TYPE1(INT_T));
+}
+
+int syscall__setuid(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETUID, ctx);
+}
+
+int trace_ret_setuid(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETUID, ctx, ARG_TYPE0(INT_T));
+}
+
+int syscall__setgid(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETGID, ctx);
+}
+
+int trace_ret_setgid(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETGID, ctx, ARG_TYPE0(INT_T));
+}
+
+int syscall__setns(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_SETNS, ctx);
+}
+
+int trace_ret_setns(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_SETNS, ctx, ARG_TYPE0(INT_T)|ARG_TYPE1(NS_FLAGS_T));
+}
+
+int syscall__unshare(struct pt_regs *ctx)
+{
+    if (skip_syscall())
+        return 0;
+
+    return save_args(_SYS_UNSHARE, ctx);
+}
+
+int trace_ret_unshare(struct pt_regs *ctx)
+{
+    return trace_ret_generic(_SYS_UNSHARE, ctx, ARG_TYPE0(NS_FLAGS_T));
+}
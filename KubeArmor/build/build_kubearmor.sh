@@ -46,7 +46,8 @@ echo "[INFO] Copied new source files"
 
 # build a new image
 echo "[INFO] Building kubearmor/kubearmor:$VERSION"
-docker build -t kubearmor/kubearmor:$VERSION  . -f $ARMOR_HOME/build/Dockerfile.kubearmor
+# SKIP_CORE=true to build without CO-RE objects (on hosts without BTF)
+docker build -t kubearmor/kubearmor:$VERSION --build-arg SKIP_CORE=${SKIP_CORE:-false} . -f $ARMOR_HOME/build/Dockerfile.kubearmor
 
 if [ $? != 0 ]; then
     echo "[FAILED] Failed to build kubearmor/kubearmor:$VERSION"
@@ -26,11 +26,15 @@ mkdir -p $ARMOR_HOME/build/KubeArmor
 # copy files to build
 cp -r $ARMOR_HOME/BPF/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/common/ $ARMOR_HOME/build/KubeArmor/
+cp -r $ARMOR_HOME/config/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/core/ $ARMOR_HOME/build/KubeArmor/
+cp -r $ARMOR_HOME/discovery/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/enforcer/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/feeder/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/log/ $ARMOR_HOME/build/KubeArmor/
+cp -r $ARMOR_HOME/metrics/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/monitor/ $ARMOR_HOME/build/KubeArmor/
+cp -r $ARMOR_HOME/policy/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/templates/ $ARMOR_HOME/build/KubeArmor/
 cp -r $ARMOR_HOME/types/ $ARMOR_HOME/build/KubeArmor/
 cp $ARMOR_HOME/go.mod $ARMOR_HOME/build/KubeArmor/
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pl "github.com/kubearmor/KubeArmor/KubeArmor/policy"
+)
+
+// replay logs recorded by '-logPath' with KubeArmorPolicies, no kernel needed
+func main() {
+	// options (string)
+	policyPtr := flag.String("policy", "", "KubeArmorPolicy files (comma-separated)")
+	tracePtr := flag.String("trace", "-", "JSON-lines log file written by -logPath, {path|-}")
+	podsPtr := flag.String("pods", "", "pod list from 'kubectl get pods -A -o yaml' to evaluate label selectors (without it, every policy selects all pods in its namespace)")
+	nodePtr := flag.String("node", "", "host name of the logs in the trace")
+	outputPtr := flag.String("output", "text", "report format, {text|json}")
+
+	// options (boolean)
+	failOnAlertsPtr := flag.Bool("failOnAlerts", false, "exit with 1 if any log would become an alert")
+
+	flag.Parse()
+
+	// == //
+
+	if *policyPtr == "" {
+		fmt.Fprintln(os.Stderr, "Need to specify policy files (-policy)")
+		os.Exit(2)
+	}
+
+	if *outputPtr != "text" && *outputPtr != "json" {
+		fmt.Fprintf(os.Stderr, "Invalid output format (%s)\n", *outputPtr)
+		os.Exit(2)
+	}
+
+	secPolicies, err := pl.LoadKubeArmorPolicies(strings.Split(*policyPtr, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load policies (%s)\n", err.Error())
+		os.Exit(2)
+	}
+
+	options := pl.DryRunOptions{NodeName: *nodePtr}
+
+	if *podsPtr != "" {
+		data, err := ioutil.ReadFile(filepath.Clean(*podsPtr))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read pods (%s)\n", err.Error())
+			os.Exit(2)
+		}
+
+		options.PodLabels, err = pl.ParsePodLabels(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse pods (%s)\n", err.Error())
+			os.Exit(2)
+		}
+	}
+
+	var trace io.Reader = os.Stdin
+
+	if *tracePtr != "-" {
+		file, err := os.Open(filepath.Clean(*tracePtr))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open the trace (%s)\n", err.Error())
+			os.Exit(2)
+		}
+		defer file.Close()
+
+		trace = file
+	}
+
+	report, err := pl.RunDryRun(secPolicies, trace, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to replay the trace (%s)\n", err.Error())
+		os.Exit(2)
+	}
+
+	// == //
+
+	if *outputPtr == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write the report (%s)\n", err.Error())
+			os.Exit(2)
+		}
+	} else {
+		pl.PrintReport(os.Stdout, report)
+	}
+
+	if *failOnAlertsPtr && len(report.Alerts) > 0 {
+		os.Exit(1)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -218,6 +219,20 @@ func GetHostName() string {
 	return ""
 }
 
+// GetArchitecture Function
+func GetArchitecture(arch string) string {
+	// the names from uname (x86_64) and Kubernetes (amd64) to the ones in GOARCH
+	switch arch {
+	case "":
+		return runtime.GOARCH
+	case "x86_64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	}
+	return arch
+}
+
 // ============= //
 // == Network == //
 // ============= //
@@ -0,0 +1,465 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	dc "github.com/kubearmor/KubeArmor/KubeArmor/discovery"
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	"sigs.k8s.io/yaml"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// ConfigMapKey is the key holding the configuration in a ConfigMap
+const ConfigMapKey = "kubearmor.yaml"
+
+// container runtimes
+var containerRuntimes = []string{"", "docker", "containerd", "cri-o"}
+
+// backpressure policies for the log pipeline
+var logBackpressures = []string{"block", "drop"}
+
+// visibility options
+var visibilityOptions = []string{"process", "file", "network", "capabilities"}
+
+// ============ //
+// == Config == //
+// ============ //
+
+// KubeArmorConfig Structure
+type KubeArmorConfig struct {
+	// cluster name
+	Cluster string `json:"cluster,omitempty"`
+
+	// ports, {port} for gRPC and {port|none} for HTTP
+	GRPC string `json:"gRPC,omitempty"`
+	HTTP string `json:"http,omitempty"`
+
+	// log file path, {path|stdout|none} (live)
+	LogPath string `json:"logPath,omitempty"`
+
+	// container runtime, {docker|containerd|cri-o}, and its socket (auto-detected if empty)
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+	CRISocket        string `json:"criSocket,omitempty"`
+
+	// options
+	EnableKubeArmorPolicy     bool `json:"enableKubeArmorPolicy"`
+	EnableKubeArmorHostPolicy bool `json:"enableKubeArmorHostPolicy"`
+
+	// seccomp profiles for containers on the nodes without AppArmor and BPF-LSM (opt-in)
+	EnableSeccompEnforcer bool `json:"enableSeccompEnforcer"`
+
+	// gRPC subscribers (live, applied to new subscribers)
+	SubscriberBufferSize int    `json:"subscriberBufferSize,omitempty"`
+	SlowSubscriberPolicy string `json:"slowSubscriberPolicy,omitempty"`
+
+	// replay buffers
+	ReplayBufferSize int    `json:"replayBufferSize"`
+	ReplayDir        string `json:"replayDir,omitempty"`
+
+	// output sinks from a separate file and inline ones (live)
+	OutputSinks string          `json:"outputSinks,omitempty"`
+	Sinks       []fd.SinkConfig `json:"sinks,omitempty"`
+
+	// namespaces not to monitor (live)
+	UntrackedNamespaces []string `json:"untrackedNamespaces"`
+
+	// visibility for pods and hosts without the kubearmor-visibility annotation (live)
+	Visibility     string `json:"visibility,omitempty"`
+	HostVisibility string `json:"hostVisibility,omitempty"`
+
+	// queue sizes in the feeder
+	MessageQueueSize int `json:"messageQueueSize,omitempty"`
+	AlertQueueSize   int `json:"alertQueueSize,omitempty"`
+	LogQueueSize     int `json:"logQueueSize,omitempty"`
+
+	// number of pages per CPU for perf buffers
+	PerfPageCount int `json:"perfPageCount,omitempty"`
+
+	// interval (in seconds) to report events lost in perf buffers
+	LostEventsReportInterval int `json:"lostEventsReportInterval,omitempty"`
+
+	// max number of processes in the ancestry of a log (0 to disable)
+	AncestryDepth int `json:"ancestryDepth"`
+
+	// max length of an exec argument (0 for no limit) and regexes redacting arguments (live)
+	MaxArgLength      int      `json:"maxArgLength"`
+	ArgRedactionRules []string `json:"argRedactionRules,omitempty"`
+
+	// log pipeline, workers and the queue size per worker, and {block|drop} when queues are full
+	LogWorkers         int    `json:"logWorkers,omitempty"`
+	LogWorkerQueueSize int    `json:"logWorkerQueueSize,omitempty"`
+	LogBackpressure    string `json:"logBackpressure,omitempty"`
+
+	// learning mode, generating KubeArmorPolicies from observed behavior (live)
+	Discovery dc.Config `json:"discovery"`
+
+	// sources of the configuration (flags only)
+	ConfigPath string `json:"-"`
+	ConfigMap  string `json:"-"`
+
+	// flags set explicitly on the command line (flags only, not overridden by the config file and the ConfigMap)
+	ExplicitFlags []string `json:"-"`
+}
+
+// DefaultConfig Function
+func DefaultConfig() KubeArmorConfig {
+	return KubeArmorConfig{
+		Cluster:                   "",
+		GRPC:                      "32767",
+		HTTP:                      "2112",
+		LogPath:                   "none",
+		EnableKubeArmorPolicy:     true,
+		EnableKubeArmorHostPolicy: false,
+		EnableSeccompEnforcer:     false,
+		SubscriberBufferSize:      fd.DefaultSubscriberBufferSize,
+		SlowSubscriberPolicy:      fd.DefaultSlowSubscriberPolicy,
+		ReplayBufferSize:          fd.DefaultReplayBufferSize,
+		UntrackedNamespaces:       []string{"kube-system", "kubearmor"},
+		Visibility:                "none",
+		HostVisibility:            "none",
+		MessageQueueSize:          1024,
+		AlertQueueSize:            4096,
+		LogQueueSize:              32768,
+		PerfPageCount:             64,
+		LostEventsReportInterval:  10,
+		AncestryDepth:             8,
+		MaxArgLength:              512,
+		LogWorkers:                4,
+		LogWorkerQueueSize:        1024,
+		LogBackpressure:           "block",
+		Discovery:                 dc.DefaultConfig(),
+	}
+}
+
+// ParseConfig Function
+func ParseConfig(data []byte, base KubeArmorConfig) (KubeArmorConfig, error) {
+	config := base
+
+	// the fields in the data override the ones in the base
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return base, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return base, err
+	}
+
+	return config, nil
+}
+
+// LoadConfig Function
+func LoadConfig(path string, base KubeArmorConfig) (KubeArmorConfig, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return base, err
+	}
+
+	return ParseConfig(data, base)
+}
+
+// Validate Function
+func (config KubeArmorConfig) Validate() error {
+	if config.GRPC == "" {
+		return fmt.Errorf("no gRPC port")
+	}
+
+	if config.LogPath == "" {
+		return fmt.Errorf("no logPath, {path|stdout|none}")
+	}
+
+	if !kl.ContainsElement(containerRuntimes, config.ContainerRuntime) {
+		return fmt.Errorf("invalid containerRuntime %q, {docker|containerd|cri-o}", config.ContainerRuntime)
+	}
+
+	if !fd.IsValidSlowSubscriberPolicy(config.SlowSubscriberPolicy) {
+		return fmt.Errorf("invalid slowSubscriberPolicy %q", config.SlowSubscriberPolicy)
+	}
+
+	if config.SubscriberBufferSize <= 0 {
+		return fmt.Errorf("invalid subscriberBufferSize %d", config.SubscriberBufferSize)
+	}
+
+	if _, err := ParseVisibility(config.Visibility); err != nil {
+		return err
+	}
+
+	if _, err := ParseVisibility(config.HostVisibility); err != nil {
+		return err
+	}
+
+	if config.MessageQueueSize <= 0 || config.AlertQueueSize <= 0 || config.LogQueueSize <= 0 {
+		return fmt.Errorf("invalid queue sizes (%d, %d, %d)", config.MessageQueueSize, config.AlertQueueSize, config.LogQueueSize)
+	}
+
+	// perf buffers need a power of two
+	if config.PerfPageCount <= 0 || config.PerfPageCount&(config.PerfPageCount-1) != 0 {
+		return fmt.Errorf("invalid perfPageCount %d (should be a power of 2)", config.PerfPageCount)
+	}
+
+	if config.LostEventsReportInterval <= 0 {
+		return fmt.Errorf("invalid lostEventsReportInterval %d", config.LostEventsReportInterval)
+	}
+
+	if config.AncestryDepth < 0 {
+		return fmt.Errorf("invalid ancestryDepth %d", config.AncestryDepth)
+	}
+
+	if config.MaxArgLength < 0 {
+		return fmt.Errorf("invalid maxArgLength %d", config.MaxArgLength)
+	}
+
+	for _, rule := range config.ArgRedactionRules {
+		if _, err := regexp.Compile(rule); err != nil {
+			return fmt.Errorf("invalid argRedactionRules %q (%s)", rule, err.Error())
+		}
+	}
+
+	if config.LogWorkers <= 0 || config.LogWorkerQueueSize <= 0 {
+		return fmt.Errorf("invalid log pipeline (%d workers, %d events per worker)", config.LogWorkers, config.LogWorkerQueueSize)
+	}
+
+	if !kl.ContainsElement(logBackpressures, config.LogBackpressure) {
+		return fmt.Errorf("invalid logBackpressure %q, {block|drop}", config.LogBackpressure)
+	}
+
+	if err := config.Discovery.Validate(); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, sink := range config.Sinks {
+		if names[sink.Name] {
+			return fmt.Errorf("duplicated sink name %q", sink.Name)
+		}
+		names[sink.Name] = true
+	}
+
+	return nil
+}
+
+// RestartRequired Function
+func RestartRequired(prev, next KubeArmorConfig) []string {
+	changed := []string{}
+
+	if prev.Cluster != next.Cluster {
+		changed = append(changed, "cluster")
+	}
+
+	if prev.GRPC != next.GRPC {
+		changed = append(changed, "gRPC")
+	}
+
+	if prev.HTTP != next.HTTP {
+		changed = append(changed, "http")
+	}
+
+	if prev.ContainerRuntime != next.ContainerRuntime {
+		changed = append(changed, "containerRuntime")
+	}
+
+	if prev.CRISocket != next.CRISocket {
+		changed = append(changed, "criSocket")
+	}
+
+	if prev.EnableKubeArmorPolicy != next.EnableKubeArmorPolicy {
+		changed = append(changed, "enableKubeArmorPolicy")
+	}
+
+	if prev.EnableKubeArmorHostPolicy != next.EnableKubeArmorHostPolicy {
+		changed = append(changed, "enableKubeArmorHostPolicy")
+	}
+
+	if prev.EnableSeccompEnforcer != next.EnableSeccompEnforcer {
+		changed = append(changed, "enableSeccompEnforcer")
+	}
+
+	if prev.ReplayBufferSize != next.ReplayBufferSize {
+		changed = append(changed, "replayBufferSize")
+	}
+
+	if prev.ReplayDir != next.ReplayDir {
+		changed = append(changed, "replayDir")
+	}
+
+	if prev.MessageQueueSize != next.MessageQueueSize || prev.AlertQueueSize != next.AlertQueueSize || prev.LogQueueSize != next.LogQueueSize {
+		changed = append(changed, "queue sizes")
+	}
+
+	if prev.PerfPageCount != next.PerfPageCount {
+		changed = append(changed, "perfPageCount")
+	}
+
+	if prev.LostEventsReportInterval != next.LostEventsReportInterval {
+		changed = append(changed, "lostEventsReportInterval")
+	}
+
+	if prev.AncestryDepth != next.AncestryDepth {
+		changed = append(changed, "ancestryDepth")
+	}
+
+	if prev.LogWorkers != next.LogWorkers || prev.LogWorkerQueueSize != next.LogWorkerQueueSize || prev.LogBackpressure != next.LogBackpressure {
+		changed = append(changed, "log pipeline")
+	}
+
+	return changed
+}
+
+// RetainStartupOptions Function
+func RetainStartupOptions(prev, next KubeArmorConfig) KubeArmorConfig {
+	next.Cluster = prev.Cluster
+	next.GRPC = prev.GRPC
+	next.HTTP = prev.HTTP
+
+	next.ContainerRuntime = prev.ContainerRuntime
+	next.CRISocket = prev.CRISocket
+
+	next.EnableKubeArmorPolicy = prev.EnableKubeArmorPolicy
+	next.EnableKubeArmorHostPolicy = prev.EnableKubeArmorHostPolicy
+	next.EnableSeccompEnforcer = prev.EnableSeccompEnforcer
+
+	next.ReplayBufferSize = prev.ReplayBufferSize
+	next.ReplayDir = prev.ReplayDir
+
+	next.MessageQueueSize = prev.MessageQueueSize
+	next.AlertQueueSize = prev.AlertQueueSize
+	next.LogQueueSize = prev.LogQueueSize
+
+	next.PerfPageCount = prev.PerfPageCount
+	next.LostEventsReportInterval = prev.LostEventsReportInterval
+
+	next.AncestryDepth = prev.AncestryDepth
+
+	next.LogWorkers = prev.LogWorkers
+	next.LogWorkerQueueSize = prev.LogWorkerQueueSize
+	next.LogBackpressure = prev.LogBackpressure
+
+	return next
+}
+
+// ApplyExplicitFlags Function (returns the names of the flags that override the given config)
+func ApplyExplicitFlags(flags, config KubeArmorConfig) (KubeArmorConfig, []string) {
+	overridden := []string{}
+
+	for _, name := range flags.ExplicitFlags {
+		prev := config
+
+		switch name {
+		case "cluster":
+			config.Cluster = flags.Cluster
+		case "gRPC":
+			config.GRPC = flags.GRPC
+		case "http":
+			config.HTTP = flags.HTTP
+		case "logPath":
+			config.LogPath = flags.LogPath
+		case "containerRuntime":
+			config.ContainerRuntime = flags.ContainerRuntime
+		case "criSocket":
+			config.CRISocket = flags.CRISocket
+		case "enableKubeArmorPolicy":
+			config.EnableKubeArmorPolicy = flags.EnableKubeArmorPolicy
+		case "enableKubeArmorHostPolicy":
+			config.EnableKubeArmorHostPolicy = flags.EnableKubeArmorHostPolicy
+		case "enableSeccompEnforcer":
+			config.EnableSeccompEnforcer = flags.EnableSeccompEnforcer
+		case "subscriberBufferSize":
+			config.SubscriberBufferSize = flags.SubscriberBufferSize
+		case "slowSubscriberPolicy":
+			config.SlowSubscriberPolicy = flags.SlowSubscriberPolicy
+		case "replayBufferSize":
+			config.ReplayBufferSize = flags.ReplayBufferSize
+		case "replayDir":
+			config.ReplayDir = flags.ReplayDir
+		case "outputSinks":
+			config.OutputSinks = flags.OutputSinks
+		default:
+			continue
+		}
+
+		if !reflect.DeepEqual(prev, config) {
+			overridden = append(overridden, name)
+		}
+	}
+
+	return config, overridden
+}
+
+// ================ //
+// == Visibility == //
+// ================ //
+
+// Visibility Structure
+type Visibility struct {
+	Process      bool
+	File         bool
+	Network      bool
+	Capabilities bool
+}
+
+// ParseVisibility Function
+func ParseVisibility(visibility string) (Visibility, error) {
+	vis := Visibility{}
+
+	for _, val := range strings.Split(visibility, ",") {
+		switch strings.TrimSpace(val) {
+		case "", "none":
+		case "process":
+			vis.Process = true
+		case "file":
+			vis.File = true
+		case "network":
+			vis.Network = true
+		case "capabilities":
+			vis.Capabilities = true
+		default:
+			return vis, fmt.Errorf("invalid visibility %q, {none|%s}", val, strings.Join(visibilityOptions, "|"))
+		}
+	}
+
+	return vis, nil
+}
+
+// ============= //
+// == Watcher == //
+// ============= //
+
+// WatchConfigFile Function
+func WatchConfigFile(path string, interval time.Duration, stopChan chan struct{}, update func(data []byte)) {
+	last, _ := ioutil.ReadFile(filepath.Clean(path))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		// compare the contents since ConfigMap volumes swap symlinks
+		data, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil || bytes.Equal(data, last) {
+			continue
+		}
+
+		last = data
+		update(data)
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	base := DefaultConfig()
+	base.ConfigPath = "/etc/kubearmor/kubearmor.yaml"
+
+	data := []byte(`
+logPath: stdout
+untrackedNamespaces: [kube-system]
+visibility: process,file
+perfPageCount: 128
+`)
+
+	config, err := ParseConfig(data, base)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse the config (%s)", err.Error())
+		return
+	}
+
+	if config.LogPath != "stdout" || config.Visibility != "process,file" || config.PerfPageCount != 128 {
+		t.Errorf("[FAIL] Failed to override options (%+v)", config)
+		return
+	}
+
+	if len(config.UntrackedNamespaces) != 1 || config.UntrackedNamespaces[0] != "kube-system" {
+		t.Errorf("[FAIL] Failed to override untracked namespaces (%v)", config.UntrackedNamespaces)
+		return
+	}
+
+	if config.GRPC != base.GRPC || config.ConfigPath != base.ConfigPath {
+		t.Errorf("[FAIL] Failed to keep options from the base (%+v)", config)
+		return
+	}
+	t.Log("[PASS] Parsed the config")
+
+	invalid := map[string]string{
+		"unknown field":   "unknownOption: true",
+		"visibility":      "visibility: process,disk",
+		"runtime":         "containerRuntime: rkt",
+		"perf page count": "perfPageCount: 100",
+		"lost events":     "lostEventsReportInterval: 0",
+		"queue size":      "logQueueSize: 0",
+		"log workers":     "logWorkers: 0",
+		"ancestry depth":  "ancestryDepth: -1",
+		"arg length":      "maxArgLength: -1",
+		"redaction rules": "argRedactionRules: ['(password']",
+		"backpressure":    "logBackpressure: sample",
+		"sink names":      "sinks: [{name: a, type: file}, {name: a, type: syslog}]",
+		"discovery":       "discovery: {targets: [{matchLabels: {group: group-1}}]}",
+	}
+
+	for name, data := range invalid {
+		if _, err := ParseConfig([]byte(data), base); err == nil {
+			t.Errorf("[FAIL] Accepted an invalid config (%s)", name)
+			return
+		}
+	}
+	t.Log("[PASS] Rejected invalid configs")
+}
+
+func TestRestartRequired(t *testing.T) {
+	prev := DefaultConfig()
+
+	next := prev
+	next.GRPC = "32768"
+	next.CRISocket = "/run/k3s/containerd/containerd.sock"
+	next.PerfPageCount = 128
+	next.LogPath = "stdout"
+
+	changed := RestartRequired(prev, next)
+	if len(changed) != 3 || changed[0] != "gRPC" || changed[1] != "criSocket" || changed[2] != "perfPageCount" {
+		t.Errorf("[FAIL] Failed to find options requiring a restart (%v)", changed)
+		return
+	}
+
+	retained := RetainStartupOptions(prev, next)
+	if retained.GRPC != prev.GRPC || retained.CRISocket != prev.CRISocket || retained.PerfPageCount != prev.PerfPageCount || retained.LogPath != "stdout" {
+		t.Errorf("[FAIL] Failed to retain startup options (%+v)", retained)
+		return
+	}
+	t.Log("[PASS] Retained startup options")
+}
+
+func TestApplyExplicitFlags(t *testing.T) {
+	flags := DefaultConfig()
+	flags.LogPath = "/tmp/kubearmor.log"
+	flags.GRPC = "32768"
+	flags.ExplicitFlags = []string{"logPath", "gRPC", "config"}
+
+	config, err := ParseConfig([]byte("logPath: stdout\nhttp: none\n"), flags)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse the config (%s)", err.Error())
+		return
+	}
+
+	config, overridden := ApplyExplicitFlags(flags, config)
+
+	if config.LogPath != "/tmp/kubearmor.log" || config.GRPC != "32768" || config.HTTP != "none" {
+		t.Errorf("[FAIL] Failed to keep the flags set explicitly (%+v)", config)
+		return
+	}
+
+	if len(overridden) != 1 || overridden[0] != "logPath" {
+		t.Errorf("[FAIL] Got unexpected overridden options (%v)", overridden)
+		return
+	}
+	t.Log("[PASS] Kept the flags set explicitly")
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-config")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "kubearmor.yaml")
+	if err := ioutil.WriteFile(path, []byte("logPath: none\n"), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to write the config file (%s)", err.Error())
+		return
+	}
+
+	stopChan := make(chan struct{})
+	updates := make(chan []byte, 1)
+
+	go WatchConfigFile(path, 10*time.Millisecond, stopChan, func(data []byte) {
+		updates <- data
+	})
+	defer close(stopChan)
+
+	// let the watcher read the initial contents
+	time.Sleep(50 * time.Millisecond)
+
+	if err := ioutil.WriteFile(path, []byte("logPath: stdout\n"), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to update the config file (%s)", err.Error())
+		return
+	}
+
+	select {
+	case data := <-updates:
+		if string(data) != "logPath: stdout\n" {
+			t.Errorf("[FAIL] Got unexpected contents (%s)", string(data))
+			return
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("[FAIL] Failed to detect changes in the config file")
+		return
+	}
+	t.Log("[PASS] Detected changes in the config file")
+}
@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	mon "github.com/kubearmor/KubeArmor/KubeArmor/monitor"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+
+	cfg "github.com/kubearmor/KubeArmor/KubeArmor/config"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// ConfigFileInterval to check the config file
+const ConfigFileInterval = 10 * time.Second
+
+// ========================== //
+// == Configuration Update == //
+// ========================== //
+
+// buildConfig Function
+func (dm *KubeArmorDaemon) buildConfig() (cfg.KubeArmorConfig, error) {
+	config := dm.BaseConfig
+
+	// defaults < config file < ConfigMap < flags set explicitly
+	if dm.ConfigFile != nil {
+		fileConfig, err := cfg.ParseConfig(dm.ConfigFile, config)
+		if err != nil {
+			return config, fmt.Errorf("invalid config file %s (%s)", config.ConfigPath, err.Error())
+		}
+		config = fileConfig
+	}
+
+	if dm.ConfigMapData != nil {
+		mapConfig, err := cfg.ParseConfig(dm.ConfigMapData, config)
+		if err != nil {
+			return config, fmt.Errorf("invalid ConfigMap %s (%s)", config.ConfigMap, err.Error())
+		}
+		config = mapConfig
+	}
+
+	// flags set explicitly on the command line take precedence
+	config, overridden := cfg.ApplyExplicitFlags(dm.BaseConfig, config)
+	if len(overridden) > 0 {
+		kg.Warnf("The flags set on the command line override the config (%s)", strings.Join(overridden, ", "))
+	}
+
+	// keep the sources from flags
+	config.ConfigPath = dm.BaseConfig.ConfigPath
+	config.ConfigMap = dm.BaseConfig.ConfigMap
+	config.ExplicitFlags = dm.BaseConfig.ExplicitFlags
+
+	return config, nil
+}
+
+// parseConfigMapName Function
+func parseConfigMapName(configMap string) (string, string, error) {
+	parts := strings.Split(configMap, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ConfigMap %q, {namespace/name}", configMap)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// InitConfig Function
+func (dm *KubeArmorDaemon) InitConfig() bool {
+	if dm.BaseConfig.ConfigPath != "" {
+		data, err := ioutil.ReadFile(filepath.Clean(dm.BaseConfig.ConfigPath))
+		if err != nil {
+			kg.Errf("Failed to read the config file (%s)", err.Error())
+			return false
+		}
+		dm.ConfigFile = data
+	}
+
+	if dm.BaseConfig.ConfigMap != "" {
+		namespaceName, configMapName, err := parseConfigMapName(dm.BaseConfig.ConfigMap)
+		if err != nil {
+			kg.Err(err.Error())
+			return false
+		}
+
+		if K8s.InitK8sClient() {
+			if data, err := K8s.GetK8sConfigMap(namespaceName, configMapName); err != nil {
+				kg.Warnf("Failed to get the ConfigMap %s (%s)", dm.BaseConfig.ConfigMap, err.Error())
+			} else if val, ok := data[cfg.ConfigMapKey]; ok {
+				dm.ConfigMapData = []byte(val)
+			}
+		}
+	}
+
+	config, err := dm.buildConfig()
+	if err != nil {
+		kg.Err(err.Error())
+		return false
+	}
+
+	dm.Config = config
+
+	// options only applied at startup
+	dm.ClusterName = config.Cluster
+	dm.gRPCPort = config.GRPC
+	dm.LogPath = config.LogPath
+	dm.EnableKubeArmorPolicy = config.EnableKubeArmorPolicy
+	dm.EnableKubeArmorHostPolicy = config.EnableKubeArmorHostPolicy
+	dm.EnableSeccompEnforcer = config.EnableSeccompEnforcer
+
+	return true
+}
+
+// GetConfig Function
+func (dm *KubeArmorDaemon) GetConfig() cfg.KubeArmorConfig {
+	dm.ConfigLock.RLock()
+	defer dm.ConfigLock.RUnlock()
+
+	return dm.Config
+}
+
+// UpdateConfig Function
+func (dm *KubeArmorDaemon) UpdateConfig() {
+	// apply updates one by one
+	dm.ConfigUpdateLock.Lock()
+	defer dm.ConfigUpdateLock.Unlock()
+
+	dm.ConfigLock.Lock()
+
+	config, err := dm.buildConfig()
+	if err != nil {
+		dm.ConfigLock.Unlock()
+		dm.Logger.Errf("Failed to update the configuration (%s)", err.Error())
+		return
+	}
+
+	prev := dm.Config
+
+	// keep the options only applied at startup
+	restart := cfg.RestartRequired(prev, config)
+	config = cfg.RetainStartupOptions(prev, config)
+
+	dm.Config = config
+	dm.ConfigLock.Unlock()
+
+	if len(restart) > 0 {
+		dm.Logger.Warnf("Need to restart KubeArmor to apply %s", strings.Join(restart, ", "))
+	}
+
+	// apply the others on the fly
+
+	if !reflect.DeepEqual(prev.UntrackedNamespaces, config.UntrackedNamespaces) && dm.SystemMonitor != nil {
+		dm.SystemMonitor.SetUntrackedNamespaces(config.UntrackedNamespaces)
+		dm.UpdateVisibilityMaps()
+		dm.Logger.Printf("Updated untracked namespaces (%s)", strings.Join(config.UntrackedNamespaces, ", "))
+	}
+
+	if (prev.MaxArgLength != config.MaxArgLength || !reflect.DeepEqual(prev.ArgRedactionRules, config.ArgRedactionRules)) && dm.SystemMonitor != nil {
+		if err := dm.SystemMonitor.SetArgsFilter(config.MaxArgLength, config.ArgRedactionRules); err != nil {
+			dm.Logger.Errf("Failed to update the filter of exec arguments (%s)", err.Error())
+		} else {
+			dm.Logger.Printf("Updated the filter of exec arguments (maxArgLength: %d, %d redaction rules)", config.MaxArgLength, len(config.ArgRedactionRules))
+		}
+	}
+
+	if prev.Visibility != config.Visibility {
+		dm.UpdateDefaultVisibility()
+		dm.Logger.Printf("Updated the default visibility (%s)", config.Visibility)
+	}
+
+	if prev.HostVisibility != config.HostVisibility {
+		dm.NodeLock.Lock()
+		dm.setNodeVisibility(&dm.Node)
+		dm.NodeLock.Unlock()
+
+		dm.UpdateHostVisibility()
+		dm.Logger.Printf("Updated the default host visibility (%s)", config.HostVisibility)
+	}
+
+	if prev.LogPath != config.LogPath {
+		if err := dm.Logger.SetOutput(config.LogPath); err != nil {
+			dm.Logger.Errf("Failed to update the log path (%s)", err.Error())
+		} else {
+			dm.LogPath = config.LogPath
+			dm.Logger.Printf("Updated the log path (%s)", config.LogPath)
+		}
+	}
+
+	if prev.OutputSinks != config.OutputSinks || !reflect.DeepEqual(prev.Sinks, config.Sinks) {
+		if err := dm.Logger.SetOutputSinks(config.OutputSinks, config.Sinks); err != nil {
+			dm.Logger.Errf("Failed to update output sinks (%s)", err.Error())
+		}
+	}
+
+	if !reflect.DeepEqual(prev.Discovery, config.Discovery) {
+		dm.Discovery.UpdateConfig(config.Discovery)
+		dm.UpdateVisibilityMaps()
+		dm.Logger.Printf("Updated the discovery targets (%d targets)", len(config.Discovery.Targets))
+	}
+
+	if prev.SubscriberBufferSize != config.SubscriberBufferSize || prev.SlowSubscriberPolicy != config.SlowSubscriberPolicy {
+		dm.Logger.SetSubscriberOptions(config.SubscriberBufferSize, config.SlowSubscriberPolicy)
+		dm.Logger.Printf("Updated options for new gRPC subscribers (%d, %s)", config.SubscriberBufferSize, config.SlowSubscriberPolicy)
+	}
+}
+
+// WatchConfigFile Function
+func (dm *KubeArmorDaemon) WatchConfigFile() {
+	cfg.WatchConfigFile(dm.BaseConfig.ConfigPath, ConfigFileInterval, StopChan, func(data []byte) {
+		dm.Logger.Printf("Detected changes in the config file (%s)", dm.BaseConfig.ConfigPath)
+
+		dm.ConfigLock.Lock()
+		dm.ConfigFile = data
+		dm.ConfigLock.Unlock()
+
+		dm.UpdateConfig()
+	})
+}
+
+// UpdateConfigMap Function
+func (dm *KubeArmorDaemon) UpdateConfigMap(event tp.K8sConfigMapEvent) {
+	var data []byte
+
+	if event.Type == "ADDED" || event.Type == "MODIFIED" {
+		if val, ok := event.Object.Data[cfg.ConfigMapKey]; ok {
+			data = []byte(val)
+		}
+	} else if event.Type != "DELETED" {
+		return
+	}
+
+	dm.ConfigLock.Lock()
+	changed := !bytes.Equal(dm.ConfigMapData, data)
+	dm.ConfigMapData = data
+	dm.ConfigLock.Unlock()
+
+	if changed {
+		dm.Logger.Printf("Detected changes in the ConfigMap (%s/%s)", event.Object.Namespace, event.Object.Name)
+		dm.UpdateConfig()
+	}
+}
+
+// WatchConfigMap Function
+func (dm *KubeArmorDaemon) WatchConfigMap() {
+	namespaceName, configMapName, err := parseConfigMapName(dm.BaseConfig.ConfigMap)
+	if err != nil {
+		return
+	}
+
+	dm.RegisterK8sWatcher("configmap")
+
+	// only the ConfigMap holding the config
+	informer := dm.NewK8sInformer("configmap", K8s.NewConfigMapListWatch(namespaceName, configMapName), &v1.ConfigMap{}, func(eventType string, obj interface{}) {
+		if configMap, ok := obj.(*v1.ConfigMap); ok {
+			dm.UpdateConfigMap(tp.K8sConfigMapEvent{Type: eventType, Object: *configMap})
+		}
+	})
+
+	informer.Run(StopChan)
+}
+
+// ================ //
+// == Visibility == //
+// ================ //
+
+// getPodVisibility Function
+func (dm *KubeArmorDaemon) getPodVisibility(pod tp.K8sPod) string {
+	if visibility, ok := pod.Annotations["kubearmor-visibility"]; ok {
+		return visibility
+	}
+
+	return dm.GetConfig().Visibility
+}
+
+// setVisibilityFlags Function
+func setVisibilityFlags(visibility string, process, file, network, capabilities *bool) {
+	*process, *file, *network, *capabilities = false, false, false, false
+
+	for _, val := range strings.Split(visibility, ",") {
+		if val == "process" {
+			*process = true
+		} else if val == "file" {
+			*file = true
+		} else if val == "network" {
+			*network = true
+		} else if val == "capabilities" {
+			*capabilities = true
+		}
+	}
+}
+
+// setNodeVisibility Function
+func (dm *KubeArmorDaemon) setNodeVisibility(node *tp.Node) {
+	visibility, ok := node.Annotations["kubearmor-visibility"]
+	if !ok {
+		visibility = dm.GetConfig().HostVisibility
+	}
+
+	setVisibilityFlags(visibility, &node.ProcessVisibilityEnabled, &node.FileVisibilityEnabled,
+		&node.NetworkVisibilityEnabled, &node.CapabilitiesVisibilityEnabled)
+}
+
+// updateHostVisibility Function (HostSecurityPoliciesLock should be held)
+func (dm *KubeArmorDaemon) updateHostVisibility() {
+	if dm.SystemMonitor == nil {
+		return
+	}
+
+	node := dm.GetNode()
+
+	secPolicies := []tp.HostSecurityPolicy{}
+
+	for _, policy := range dm.HostSecurityPolicies {
+		if kl.MatchIdentities(policy.Spec.NodeSelector.Identities, node.Identities) {
+			secPolicies = append(secPolicies, policy)
+		}
+	}
+
+	dm.SystemMonitor.UpdateHostVisibility(mon.GetHostVisibilityFlags(node, secPolicies))
+}
+
+// UpdateHostVisibility Function
+func (dm *KubeArmorDaemon) UpdateHostVisibility() {
+	dm.HostSecurityPoliciesLock.RLock()
+	defer dm.HostSecurityPoliciesLock.RUnlock()
+
+	dm.updateHostVisibility()
+}
+
+// UpdateDefaultVisibility Function
+func (dm *KubeArmorDaemon) UpdateDefaultVisibility() {
+	visibility := dm.GetConfig().Visibility
+
+	// pods without the visibility annotation
+	pods := map[string]bool{}
+
+	dm.K8sPodsLock.RLock()
+	for _, pod := range dm.K8sPods {
+		if _, ok := pod.Annotations["kubearmor-visibility"]; !ok {
+			pods[pod.Metadata["namespaceName"]+"/"+pod.Metadata["podName"]] = true
+		}
+	}
+	dm.K8sPodsLock.RUnlock()
+
+	dm.EndPointsLock.Lock()
+	defer dm.EndPointsLock.Unlock()
+
+	for idx, endPoint := range dm.EndPoints {
+		if !pods[endPoint.NamespaceName+"/"+endPoint.EndPointName] {
+			continue
+		}
+
+		setVisibilityFlags(visibility, &dm.EndPoints[idx].ProcessVisibilityEnabled, &dm.EndPoints[idx].FileVisibilityEnabled,
+			&dm.EndPoints[idx].NetworkVisibilityEnabled, &dm.EndPoints[idx].CapabilitiesVisibilityEnabled)
+
+		// update the flags of containers
+		dm.ContainersLock.Lock()
+		for _, containerID := range endPoint.Containers {
+			if container, ok := dm.Containers[containerID]; ok {
+				container.ProcessVisibilityEnabled = dm.EndPoints[idx].ProcessVisibilityEnabled
+				container.FileVisibilityEnabled = dm.EndPoints[idx].FileVisibilityEnabled
+				container.NetworkVisibilityEnabled = dm.EndPoints[idx].NetworkVisibilityEnabled
+				container.CapabilitiesVisibilityEnabled = dm.EndPoints[idx].CapabilitiesVisibilityEnabled
+
+				dm.Containers[containerID] = container
+			}
+		}
+		dm.ContainersLock.Unlock()
+
+		// update the visibility in the kernel
+		dm.UpdateVisibilityMap(dm.EndPoints[idx])
+	}
+}
+
+// UpdateVisibilityMap Function
+func (dm *KubeArmorDaemon) UpdateVisibilityMap(endPoint tp.EndPoint) {
+	if dm.SystemMonitor == nil {
+		return
+	}
+
+	flags := mon.GetVisibilityFlags(endPoint, dm.SystemMonitor.IsUntrackedNamespace(endPoint.NamespaceName),
+		dm.Discovery.IsTarget(endPoint.NamespaceName, endPoint.Labels))
+
+	dm.ContainersLock.RLock()
+	defer dm.ContainersLock.RUnlock()
+
+	for _, containerID := range endPoint.Containers {
+		if container, ok := dm.Containers[containerID]; ok {
+			dm.SystemMonitor.UpdateVisibilityMap(container.PidNS, container.MntNS, flags)
+		}
+	}
+}
+
+// UpdateVisibilityMaps Function
+func (dm *KubeArmorDaemon) UpdateVisibilityMaps() {
+	dm.EndPointsLock.RLock()
+	defer dm.EndPointsLock.RUnlock()
+
+	for _, endPoint := range dm.EndPoints {
+		dm.UpdateVisibilityMap(endPoint)
+	}
+}
@@ -0,0 +1,562 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	efc "github.com/kubearmor/KubeArmor/KubeArmor/enforcer"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Container Runtimes
+const (
+	DockerRuntime     = "docker"
+	ContainerdRuntime = "containerd"
+	CrioRuntime       = "cri-o"
+)
+
+// Container Events
+const (
+	ContainerStarted   = "start"
+	ContainerDestroyed = "destroy"
+)
+
+// RuntimeSocket Structure
+type RuntimeSocket struct {
+	Runtime string
+	Path    string
+}
+
+// RuntimeSockets in the order of auto-detection
+//
+// 1. the socket given by -criSocket (with -containerRuntime or a runtime inferred from the path)
+// 2. the sockets of the runtime given by -containerRuntime or reported by the node, in the order below
+// 3. all the sockets below, in this order
+//
+// A docker node without the docker socket falls back to containerd (dockershim on containerd).
+var RuntimeSockets = []RuntimeSocket{
+	{Runtime: DockerRuntime, Path: "/var/run/docker.sock"},
+	{Runtime: ContainerdRuntime, Path: "/var/run/containerd/containerd.sock"},
+	{Runtime: ContainerdRuntime, Path: "/var/snap/microk8s/common/run/containerd.sock"},
+	{Runtime: ContainerdRuntime, Path: "/run/k3s/containerd/containerd.sock"},
+	{Runtime: CrioRuntime, Path: "/var/run/crio/crio.sock"},
+}
+
+// ContainerRuntimePollInterval for runtimes without an event stream
+const ContainerRuntimePollInterval = time.Millisecond * 500
+
+// Reconnection backoff for container runtimes
+const (
+	ContainerRuntimeMinBackoff = time.Second
+	ContainerRuntimeMaxBackoff = time.Second * 30
+)
+
+// ErrNoEventStream is returned by runtimes that can only be polled
+var ErrNoEventStream = errors.New("no event stream")
+
+// ======================= //
+// == Container Runtime == //
+// ======================= //
+
+// ContainerEvent Structure
+type ContainerEvent struct {
+	ContainerID string
+	Action      string
+}
+
+// ContainerRuntime Interface
+type ContainerRuntime interface {
+	// Name returns the name of the runtime
+	Name() string
+
+	// ListContainers returns the ids of running containers
+	ListContainers() ([]string, error)
+
+	// GetContainerInfo returns the base information and labels of a container
+	GetContainerInfo(containerID string) (tp.Container, error)
+
+	// GetContainerNamespaces returns the pid and mount namespace inodes of a container
+	GetContainerNamespaces(containerID string) (uint32, uint32, error)
+
+	// WatchContainerEvents closes ready once subscribed, then sends events until ctx is canceled or the stream breaks (ErrNoEventStream if unsupported)
+	WatchContainerEvents(ctx context.Context, events chan<- ContainerEvent, ready chan<- struct{}) error
+
+	// Close releases the connection to the runtime
+	Close()
+}
+
+// GetRuntimeName Function
+func GetRuntimeName(runtimeVersion string) string {
+	// e.g., docker://20.10.7, containerd://1.4.4, cri-o://1.20.0
+	for _, runtime := range []string{DockerRuntime, ContainerdRuntime, CrioRuntime} {
+		if strings.HasPrefix(runtimeVersion, runtime) {
+			return runtime
+		}
+	}
+
+	return ""
+}
+
+// GetRuntimeNameFromSocket Function
+func GetRuntimeNameFromSocket(socket string) string {
+	name := filepath.Base(socket)
+
+	switch {
+	case strings.Contains(name, "docker"):
+		return DockerRuntime
+	case strings.Contains(name, "containerd"):
+		return ContainerdRuntime
+	case strings.Contains(name, "crio"):
+		return CrioRuntime
+	default:
+		return ""
+	}
+}
+
+// DetectContainerRuntime Function
+func DetectContainerRuntime(runtime, socket, runtimeVersion string, exists func(path string) bool) (string, string, error) {
+	if runtime == "" {
+		runtime = GetRuntimeName(runtimeVersion)
+	}
+
+	if socket != "" {
+		if runtime == "" {
+			runtime = GetRuntimeNameFromSocket(socket)
+		}
+
+		if runtime == "" {
+			return "", "", fmt.Errorf("unknown container runtime for %s", socket)
+		}
+
+		if !exists(socket) {
+			return "", "", fmt.Errorf("%s is not accessible", socket)
+		}
+
+		return runtime, socket, nil
+	}
+
+	candidates := []string{runtime}
+	if runtime == "" {
+		candidates = []string{DockerRuntime, ContainerdRuntime, CrioRuntime}
+	} else if runtime == DockerRuntime {
+		candidates = append(candidates, ContainerdRuntime)
+	}
+
+	for _, candidate := range candidates {
+		for _, runtimeSocket := range RuntimeSockets {
+			if runtimeSocket.Runtime == candidate && exists(runtimeSocket.Path) {
+				return runtimeSocket.Runtime, runtimeSocket.Path, nil
+			}
+		}
+	}
+
+	if runtime == "" {
+		return "", "", errors.New("no container runtime socket is accessible")
+	}
+
+	return "", "", fmt.Errorf("%s socket file is not accessible", runtime)
+}
+
+// NewContainerRuntime Function
+func NewContainerRuntime(runtime, socket string) (ContainerRuntime, error) {
+	switch runtime {
+	case DockerRuntime:
+		if dh := NewDockerHandler(socket); dh != nil {
+			return dh, nil
+		}
+	case ContainerdRuntime:
+		if ch := NewContainerdHandler(socket); ch != nil {
+			return ch, nil
+		}
+	case CrioRuntime:
+		if ch := NewCrioHandler(socket); ch != nil {
+			return ch, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", runtime)
+	}
+
+	return nil, fmt.Errorf("failed to connect to %s (%s)", runtime, socket)
+}
+
+// SendContainerEvent Function
+func SendContainerEvent(ctx context.Context, events chan<- ContainerEvent, event ContainerEvent) error {
+	select {
+	case events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReadNamespaceInodes Function
+func ReadNamespaceInodes(pid int) (uint32, uint32, error) {
+	var pidNS, mntNS uint32
+
+	if pid <= 0 {
+		return 0, 0, fmt.Errorf("invalid pid %d", pid)
+	}
+
+	pidStr := strconv.Itoa(pid)
+
+	data, err := kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pidStr + "/ns/pid"})
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(data, "pid:[%d]\n", &pidNS); err != nil {
+		return 0, 0, fmt.Errorf("failed to get PidNS (%s, %s)", pidStr, err.Error())
+	}
+
+	data, err = kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pidStr + "/ns/mnt"})
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(data, "mnt:[%d]\n", &mntNS); err != nil {
+		return 0, 0, fmt.Errorf("failed to get MntNS (%s, %s)", pidStr, err.Error())
+	}
+
+	return pidNS, mntNS, nil
+}
+
+// ======================== //
+// == Container Handling == //
+// ======================== //
+
+// InitContainerRuntime Function
+func (dm *KubeArmorDaemon) InitContainerRuntime() error {
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Clean(path))
+		return err == nil
+	}
+
+	runtime, socket, err := DetectContainerRuntime(dm.Config.ContainerRuntime, dm.Config.CRISocket, dm.GetNode().ContainerRuntimeVersion, exists)
+	if err != nil {
+		return err
+	}
+
+	dm.Runtime, err = NewContainerRuntime(runtime, socket)
+	if err != nil {
+		return err
+	}
+
+	dm.RuntimeContainers = map[string]bool{}
+
+	dm.Logger.Printf("Connected to %s (%s)", runtime, socket)
+
+	return nil
+}
+
+// CloseContainerRuntime Function
+func (dm *KubeArmorDaemon) CloseContainerRuntime() bool {
+	dm.Runtime.Close()
+	return true
+}
+
+// UpdateContainer Function
+func (dm *KubeArmorDaemon) UpdateContainer(containerID, action string) bool {
+	if action == ContainerStarted {
+		// get container information from the runtime
+		container, err := dm.Runtime.GetContainerInfo(containerID)
+		if err != nil {
+			return false
+		}
+
+		if container.ContainerID == "" {
+			return false
+		}
+
+		container.PidNS, container.MntNS, err = dm.Runtime.GetContainerNamespaces(containerID)
+		if err != nil {
+			dm.Logger.Errf("Failed to get the namespaces of a container (%s, %s)", containerID, err.Error())
+		}
+
+		dm.ContainersLock.Lock()
+		if _, ok := dm.Containers[container.ContainerID]; !ok {
+			dm.Containers[container.ContainerID] = container
+			dm.ContainersLock.Unlock()
+		} else if dm.Containers[container.ContainerID].PidNS == 0 && dm.Containers[container.ContainerID].MntNS == 0 {
+			// this entry was updated by kubernetes before the runtime detects it
+			// thus, we here use the info given by kubernetes instead of the info given by the runtime
+
+			container.NamespaceName = dm.Containers[container.ContainerID].NamespaceName
+			container.EndPointName = dm.Containers[container.ContainerID].EndPointName
+			container.ContainerName = dm.Containers[container.ContainerID].ContainerName
+
+			container.PolicyEnabled = dm.Containers[container.ContainerID].PolicyEnabled
+
+			container.ProcessVisibilityEnabled = dm.Containers[container.ContainerID].ProcessVisibilityEnabled
+			container.FileVisibilityEnabled = dm.Containers[container.ContainerID].FileVisibilityEnabled
+			container.NetworkVisibilityEnabled = dm.Containers[container.ContainerID].NetworkVisibilityEnabled
+			container.CapabilitiesVisibilityEnabled = dm.Containers[container.ContainerID].CapabilitiesVisibilityEnabled
+
+			dm.Containers[container.ContainerID] = container
+			dm.ContainersLock.Unlock()
+
+			dm.EndPointsLock.Lock()
+			for idx, endPoint := range dm.EndPoints {
+				if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
+					// update containers
+					if !kl.ContainsElement(endPoint.Containers, container.ContainerID) {
+						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers, container.ContainerID)
+					}
+
+					// update apparmor profiles
+					if !kl.ContainsElement(endPoint.AppArmorProfiles, container.AppArmorProfile) {
+						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles, container.AppArmorProfile)
+					}
+
+					break
+				}
+			}
+			dm.EndPointsLock.Unlock()
+		} else {
+			dm.ContainersLock.Unlock()
+			return false
+		}
+
+		if dm.SystemMonitor != nil {
+			// update NsMap
+			dm.SystemMonitor.AddContainerIDToNsMap(containerID, container.PidNS, container.MntNS)
+
+			// filter invisible events in the kernel
+			dm.EndPointsLock.RLock()
+			for _, endPoint := range dm.EndPoints {
+				if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
+					dm.UpdateVisibilityMap(endPoint)
+					break
+				}
+			}
+			dm.EndPointsLock.RUnlock()
+		}
+
+		if dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapContainerNamespaces) {
+			// enforce security policies in the namespaces of the container
+			dm.RuntimeEnforcer.RegisterEndpoint("ADDED", efc.Endpoint{NamespaceName: container.NamespaceName, EndPointName: container.EndPointName, Containers: []tp.Container{container}})
+
+			dm.EndPointsLock.RLock()
+			for _, endPoint := range dm.EndPoints {
+				if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
+					dm.RuntimeEnforcer.UpdateSecurityPolicies(endPoint)
+					break
+				}
+			}
+			dm.EndPointsLock.RUnlock()
+		}
+
+		dm.Logger.Printf("Detected a container (added/%s)", containerID[:12])
+
+	} else if action == ContainerDestroyed {
+		dm.ContainersLock.Lock()
+		container, ok := dm.Containers[containerID]
+		if !ok {
+			dm.ContainersLock.Unlock()
+			return false
+		}
+		delete(dm.Containers, containerID)
+		dm.ContainersLock.Unlock()
+
+		dm.EndPointsLock.Lock()
+		for idx, endPoint := range dm.EndPoints {
+			if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
+				// update containers
+				for idxC, containerID := range endPoint.Containers {
+					if containerID == container.ContainerID {
+						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers[:idxC], dm.EndPoints[idx].Containers[idxC+1:]...)
+						break
+					}
+				}
+
+				// update apparmor profiles
+				for idxA, profile := range endPoint.AppArmorProfiles {
+					if profile == container.AppArmorProfile {
+						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles[:idxA], dm.EndPoints[idx].AppArmorProfiles[idxA+1:]...)
+						break
+					}
+				}
+
+				break
+			}
+		}
+		dm.EndPointsLock.Unlock()
+
+		if dm.SystemMonitor != nil {
+			// update NsMap
+			dm.SystemMonitor.DeleteContainerIDFromNsMap(containerID)
+
+			// update visibility_map
+			dm.SystemMonitor.DeleteVisibilityMap(container.PidNS, container.MntNS)
+		}
+
+		if dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapContainerNamespaces) {
+			// release the namespaces of the container
+			dm.RuntimeEnforcer.RegisterEndpoint("DELETED", efc.Endpoint{NamespaceName: container.NamespaceName, EndPointName: container.EndPointName, Containers: []tp.Container{container}})
+		}
+
+		dm.Logger.Printf("Detected a container (removed/%s)", containerID[:12])
+	}
+
+	return true
+}
+
+// HandleContainerEvent Function
+func (dm *KubeArmorDaemon) HandleContainerEvent(event ContainerEvent) {
+	switch event.Action {
+	case ContainerStarted:
+		if dm.RuntimeContainers[event.ContainerID] {
+			return
+		}
+
+		if dm.UpdateContainer(event.ContainerID, ContainerStarted) {
+			dm.RuntimeContainers[event.ContainerID] = true
+		}
+
+	case ContainerDestroyed:
+		if !dm.RuntimeContainers[event.ContainerID] {
+			return
+		}
+
+		dm.UpdateContainer(event.ContainerID, ContainerDestroyed)
+		delete(dm.RuntimeContainers, event.ContainerID)
+	}
+}
+
+// ReconcileContainers Function
+func (dm *KubeArmorDaemon) ReconcileContainers() error {
+	containerIDs, err := dm.Runtime.ListContainers()
+	if err != nil {
+		return err
+	}
+
+	running := map[string]bool{}
+
+	for _, containerID := range containerIDs {
+		running[containerID] = true
+		dm.HandleContainerEvent(ContainerEvent{ContainerID: containerID, Action: ContainerStarted})
+	}
+
+	for containerID := range dm.RuntimeContainers {
+		if !running[containerID] {
+			dm.HandleContainerEvent(ContainerEvent{ContainerID: containerID, Action: ContainerDestroyed})
+		}
+	}
+
+	return nil
+}
+
+// WatchContainerRuntime Function
+func (dm *KubeArmorDaemon) WatchContainerRuntime(stopChan chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan ContainerEvent, 256)
+	errChan := make(chan error, 1)
+	ready := make(chan struct{})
+
+	go func() {
+		errChan <- dm.Runtime.WatchContainerEvents(ctx, events, ready)
+	}()
+
+	// polling runtimes without an event stream
+	var poll <-chan time.Time
+	var ticker *time.Ticker
+
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+
+	// subscribe first so that no event is lost between listing containers and watching events
+	select {
+	case <-stopChan:
+		return nil
+
+	case <-ready:
+
+	case err := <-errChan:
+		if err != ErrNoEventStream {
+			return err
+		}
+
+		ticker = time.NewTicker(ContainerRuntimePollInterval)
+		poll = ticker.C
+	}
+
+	// catch up with the containers started or stopped while disconnected
+	if err := dm.ReconcileContainers(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stopChan:
+			return nil
+
+		case event := <-events:
+			dm.HandleContainerEvent(event)
+
+		case err := <-errChan:
+			return err
+
+		case <-poll:
+			if err := dm.ReconcileContainers(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// MonitorContainerRuntime Function
+func (dm *KubeArmorDaemon) MonitorContainerRuntime() {
+	dm.WgDaemon.Add(1)
+	defer dm.WgDaemon.Done()
+
+	dm.Logger.Printf("Started to monitor %s events", dm.Runtime.Name())
+
+	backoff := ContainerRuntimeMinBackoff
+
+	for {
+		connected := time.Now()
+
+		err := dm.WatchContainerRuntime(StopChan)
+
+		select {
+		case <-StopChan:
+			return
+		default:
+		}
+
+		// reset the backoff if the connection lasted for a while
+		if time.Since(connected) > ContainerRuntimeMaxBackoff {
+			backoff = ContainerRuntimeMinBackoff
+		}
+
+		if err != nil {
+			dm.Logger.Warnf("Lost the connection to %s, reconnecting in %s (%s)", dm.Runtime.Name(), backoff.String(), err.Error())
+		}
+
+		select {
+		case <-StopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > ContainerRuntimeMaxBackoff {
+			backoff = ContainerRuntimeMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// fakeRuntime is a container runtime kept in memory
+
+type fakeRuntime struct {
+	lock       sync.Mutex
+	containers map[string]tp.Container
+	events     chan ContainerEvent
+	noEvents   bool
+
+	// set if containers are listed before subscribing to events
+	subscribed         bool
+	listedUnsubscribed bool
+}
+
+func newFakeRuntime(noEvents bool) *fakeRuntime {
+	return &fakeRuntime{containers: map[string]tp.Container{}, events: make(chan ContainerEvent), noEvents: noEvents}
+}
+
+func (fr *fakeRuntime) Name() string {
+	return "fake"
+}
+
+func (fr *fakeRuntime) ListContainers() ([]string, error) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	if !fr.noEvents && !fr.subscribed {
+		fr.listedUnsubscribed = true
+	}
+
+	containerIDs := []string{}
+	for containerID := range fr.containers {
+		containerIDs = append(containerIDs, containerID)
+	}
+
+	return containerIDs, nil
+}
+
+func (fr *fakeRuntime) GetContainerInfo(containerID string) (tp.Container, error) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	container, ok := fr.containers[containerID]
+	if !ok {
+		return tp.Container{}, errors.New("no such container")
+	}
+
+	return container, nil
+}
+
+func (fr *fakeRuntime) GetContainerNamespaces(containerID string) (uint32, uint32, error) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	container, ok := fr.containers[containerID]
+	if !ok {
+		return 0, 0, errors.New("no such container")
+	}
+
+	return container.PidNS, container.MntNS, nil
+}
+
+func (fr *fakeRuntime) WatchContainerEvents(ctx context.Context, events chan<- ContainerEvent, ready chan<- struct{}) error {
+	if fr.noEvents {
+		return ErrNoEventStream
+	}
+
+	fr.lock.Lock()
+	fr.subscribed = true
+	fr.lock.Unlock()
+
+	close(ready)
+
+	for {
+		select {
+		case event := <-fr.events:
+			if err := SendContainerEvent(ctx, events, event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (fr *fakeRuntime) Close() {}
+
+func (fr *fakeRuntime) start(containerID string, pidNS uint32) {
+	fr.lock.Lock()
+	fr.containers[containerID] = tp.Container{ContainerID: containerID, ContainerName: containerID[:12], NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", PidNS: pidNS, MntNS: pidNS + 1}
+	fr.lock.Unlock()
+
+	if !fr.noEvents {
+		fr.events <- ContainerEvent{ContainerID: containerID, Action: ContainerStarted}
+	}
+}
+
+func (fr *fakeRuntime) stop(containerID string) {
+	fr.lock.Lock()
+	delete(fr.containers, containerID)
+	fr.lock.Unlock()
+
+	if !fr.noEvents {
+		fr.events <- ContainerEvent{ContainerID: containerID, Action: ContainerDestroyed}
+	}
+}
+
+func newTestDaemon(runtime ContainerRuntime) *KubeArmorDaemon {
+	node := tp.Node{NodeName: "test"}
+
+	dm := &KubeArmorDaemon{}
+
+	dm.NodeLock = new(sync.RWMutex)
+
+	dm.Containers = map[string]tp.Container{}
+	dm.ContainersLock = new(sync.RWMutex)
+
+	dm.EndPoints = []tp.EndPoint{}
+	dm.EndPointsLock = new(sync.RWMutex)
+
+	dm.Logger = fd.NewPolicyMatcher(&node)
+
+	dm.Runtime = runtime
+	dm.RuntimeContainers = map[string]bool{}
+
+	return dm
+}
+
+func waitForContainers(dm *KubeArmorDaemon, count int) bool {
+	for i := 0; i < 100; i++ {
+		dm.ContainersLock.RLock()
+		n := len(dm.Containers)
+		dm.ContainersLock.RUnlock()
+
+		if n == count {
+			return true
+		}
+
+		time.Sleep(time.Millisecond * 50)
+	}
+
+	return false
+}
+
+func TestDetectContainerRuntime(t *testing.T) {
+	sockets := map[string]bool{}
+	exists := func(path string) bool {
+		return sockets[path]
+	}
+
+	testCases := []struct {
+		name           string
+		sockets        []string
+		runtime        string
+		socket         string
+		runtimeVersion string
+		expRuntime     string
+		expSocket      string
+		expErr         bool
+	}{
+		{name: "docker node", sockets: []string{"/var/run/docker.sock", "/var/run/containerd/containerd.sock"}, runtimeVersion: "docker://20.10.7", expRuntime: DockerRuntime, expSocket: "/var/run/docker.sock"},
+		{name: "dockershim on containerd", sockets: []string{"/var/run/containerd/containerd.sock"}, runtimeVersion: "docker://20.10.7", expRuntime: ContainerdRuntime, expSocket: "/var/run/containerd/containerd.sock"},
+		{name: "microk8s", sockets: []string{"/var/snap/microk8s/common/run/containerd.sock"}, runtimeVersion: "containerd://1.4.4", expRuntime: ContainerdRuntime, expSocket: "/var/snap/microk8s/common/run/containerd.sock"},
+		{name: "k3s", sockets: []string{"/run/k3s/containerd/containerd.sock"}, runtimeVersion: "containerd://1.4.4-k3s1", expRuntime: ContainerdRuntime, expSocket: "/run/k3s/containerd/containerd.sock"},
+		{name: "cri-o node", sockets: []string{"/var/run/crio/crio.sock", "/var/run/containerd/containerd.sock"}, runtimeVersion: "cri-o://1.20.0", expRuntime: CrioRuntime, expSocket: "/var/run/crio/crio.sock"},
+		{name: "no node (docker first)", sockets: []string{"/var/run/docker.sock", "/var/run/crio/crio.sock"}, expRuntime: DockerRuntime, expSocket: "/var/run/docker.sock"},
+		{name: "runtime flag", sockets: []string{"/var/run/docker.sock", "/var/run/crio/crio.sock"}, runtime: CrioRuntime, expRuntime: CrioRuntime, expSocket: "/var/run/crio/crio.sock"},
+		{name: "socket flag", sockets: []string{"/custom/containerd.sock"}, socket: "/custom/containerd.sock", runtimeVersion: "docker://20.10.7", expRuntime: DockerRuntime, expSocket: "/custom/containerd.sock"},
+		{name: "socket flag (inferred)", sockets: []string{"/custom/containerd.sock"}, socket: "/custom/containerd.sock", expRuntime: ContainerdRuntime, expSocket: "/custom/containerd.sock"},
+		{name: "missing socket", socket: "/custom/crio.sock", expErr: true},
+		{name: "unknown socket", sockets: []string{"/custom/runtime.sock"}, socket: "/custom/runtime.sock", expErr: true},
+		{name: "no socket", runtimeVersion: "cri-o://1.20.0", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		sockets = map[string]bool{}
+		for _, socket := range tc.sockets {
+			sockets[socket] = true
+		}
+
+		runtime, socket, err := DetectContainerRuntime(tc.runtime, tc.socket, tc.runtimeVersion, exists)
+		if tc.expErr {
+			if err == nil {
+				t.Errorf("[FAIL] Detected %s (%s) without an accessible socket (%s)", runtime, socket, tc.name)
+				return
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("[FAIL] Failed to detect a container runtime (%s, %s)", tc.name, err.Error())
+			return
+		}
+
+		if runtime != tc.expRuntime || socket != tc.expSocket {
+			t.Errorf("[FAIL] Detected %s (%s) instead of %s (%s) (%s)", runtime, socket, tc.expRuntime, tc.expSocket, tc.name)
+			return
+		}
+	}
+	t.Log("[PASS] Detected container runtimes")
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	runtime := newFakeRuntime(false)
+	dm := newTestDaemon(runtime)
+
+	// a container started before KubeArmor
+	runtime.containers["0123456789abcdef-0"] = tp.Container{ContainerID: "0123456789abcdef-0", ContainerName: "0123456789ab", PidNS: 10, MntNS: 11}
+
+	stopChan := make(chan struct{})
+	errChan := make(chan error, 1)
+
+	go func() {
+		errChan <- dm.WatchContainerRuntime(stopChan)
+	}()
+
+	if !waitForContainers(dm, 1) {
+		t.Errorf("[FAIL] Failed to reconcile an existing container")
+		return
+	}
+	t.Log("[PASS] Reconciled an existing container")
+
+	runtime.lock.Lock()
+	listedUnsubscribed := runtime.listedUnsubscribed
+	runtime.lock.Unlock()
+
+	if listedUnsubscribed {
+		t.Errorf("[FAIL] Listed containers before subscribing to events")
+		return
+	}
+	t.Log("[PASS] Subscribed to events before listing containers")
+
+	runtime.start("0123456789abcdef-1", 20)
+
+	if !waitForContainers(dm, 2) {
+		t.Errorf("[FAIL] Failed to add a started container")
+		return
+	}
+
+	dm.ContainersLock.RLock()
+	container := dm.Containers["0123456789abcdef-1"]
+	dm.ContainersLock.RUnlock()
+
+	if container.PidNS != 20 || container.MntNS != 21 || container.NamespaceName != "multiubuntu" {
+		t.Errorf("[FAIL] Got an unexpected container (%+v)", container)
+		return
+	}
+	t.Log("[PASS] Added a started container")
+
+	runtime.stop("0123456789abcdef-0")
+
+	if !waitForContainers(dm, 1) {
+		t.Errorf("[FAIL] Failed to remove a destroyed container")
+		return
+	}
+	t.Log("[PASS] Removed a destroyed container")
+
+	close(stopChan)
+
+	if err := <-errChan; err != nil {
+		t.Errorf("[FAIL] Failed to stop watching the runtime (%s)", err.Error())
+		return
+	}
+	t.Log("[PASS] Stopped watching the runtime")
+}
+
+func TestContainerPolling(t *testing.T) {
+	runtime := newFakeRuntime(true)
+	dm := newTestDaemon(runtime)
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+
+	go func() {
+		_ = dm.WatchContainerRuntime(stopChan)
+	}()
+
+	runtime.start("0123456789abcdef-2", 30)
+
+	if !waitForContainers(dm, 1) {
+		t.Errorf("[FAIL] Failed to poll a started container")
+		return
+	}
+	t.Log("[PASS] Polled a started container")
+
+	runtime.stop("0123456789abcdef-2")
+
+	if !waitForContainers(dm, 0) {
+		t.Errorf("[FAIL] Failed to poll a destroyed container")
+		return
+	}
+	t.Log("[PASS] Polled a destroyed container")
+}
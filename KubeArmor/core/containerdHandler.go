@@ -5,18 +5,18 @@ package core
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"path/filepath"
+	"errors"
 	"strconv"
-	"time"
+	"sync"
 
-	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
 	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 
+	ev "github.com/containerd/containerd/api/events"
 	pb "github.com/containerd/containerd/api/services/containers/v1"
+	pe "github.com/containerd/containerd/api/services/events/v1"
 	pt "github.com/containerd/containerd/api/services/tasks/v1"
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/typeurl"
 	"google.golang.org/grpc"
@@ -24,13 +24,21 @@ import (
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// ContainerdEventFilters for container lifecycle events
+var ContainerdEventFilters = []string{
+	`topic=="/tasks/start"`,
+	`topic=="/tasks/exit"`,
+	`topic=="/containers/delete"`,
+}
+
 // ======================== //
 // == Containerd Handler == //
 // ======================== //
 
-// Containerd Handler
-var Containerd *ContainerdHandler
-
 // init Function
 func init() {
 	// Spec -> google.protobuf.Any
@@ -41,8 +49,6 @@ func init() {
 
 	typeurl.Register(&specs.Spec{}, prefix, "opencontainers/runtime-spec", major, "Spec")
 	typeurl.Register(&specs.Process{}, prefix, "opencontainers/runtime-spec", major, "Process")
-
-	Containerd = NewContainerdHandler()
 }
 
 // ContainerdHandler Structure
@@ -56,32 +62,23 @@ type ContainerdHandler struct {
 	// task client
 	taskClient pt.TasksClient
 
+	// events client
+	eventsClient pe.EventsClient
+
 	// context
 	containerd context.Context
 	docker     context.Context
 
-	// active containers
-	containers map[string]context.Context
+	// container id -> namespace context
+	containers     map[string]context.Context
+	containersLock *sync.RWMutex
 }
 
 // NewContainerdHandler Function
-func NewContainerdHandler() *ContainerdHandler {
+func NewContainerdHandler(socket string) *ContainerdHandler {
 	ch := &ContainerdHandler{}
 
-	sockFile := "unix://"
-
-	for _, candidate := range []string{"/var/run/containerd/containerd.sock", "/var/snap/microk8s/common/run/containerd.sock"} {
-		if _, err := os.Stat(filepath.Clean(candidate)); err == nil {
-			sockFile = sockFile + candidate
-			break
-		}
-	}
-
-	if sockFile == "unix://" {
-		return nil
-	}
-
-	conn, err := grpc.Dial(sockFile, grpc.WithInsecure())
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithInsecure())
 	if err != nil {
 		return nil
 	}
@@ -94,18 +91,27 @@ func NewContainerdHandler() *ContainerdHandler {
 	// task client
 	ch.taskClient = pt.NewTasksClient(ch.conn)
 
+	// events client
+	ch.eventsClient = pe.NewEventsClient(ch.conn)
+
 	// docker namespace
 	ch.docker = namespaces.WithNamespace(context.Background(), "moby")
 
 	// containerd namespace
 	ch.containerd = namespaces.WithNamespace(context.Background(), "k8s.io")
 
-	// active containers
+	// container id -> namespace context
 	ch.containers = map[string]context.Context{}
+	ch.containersLock = new(sync.RWMutex)
 
 	return ch
 }
 
+// Name Function
+func (ch *ContainerdHandler) Name() string {
+	return ContainerdRuntime
+}
+
 // Close Function
 func (ch *ContainerdHandler) Close() {
 	if ch.conn != nil {
@@ -115,14 +121,49 @@ func (ch *ContainerdHandler) Close() {
 	}
 }
 
+// GetNamespaceContext Function
+func (ch *ContainerdHandler) GetNamespaceContext(namespace string) (context.Context, bool) {
+	switch namespace {
+	case "moby":
+		return ch.docker, true
+	case "k8s.io":
+		return ch.containerd, true
+	default:
+		return nil, false
+	}
+}
+
+// getContainerContext Function
+func (ch *ContainerdHandler) getContainerContext(containerID string) []context.Context {
+	ch.containersLock.RLock()
+	ctx, ok := ch.containers[containerID]
+	ch.containersLock.RUnlock()
+
+	if ok {
+		return []context.Context{ctx}
+	}
+
+	// not listed yet
+	return []context.Context{ch.containerd, ch.docker}
+}
+
 // ==================== //
 // == Container Info == //
 // ==================== //
 
 // GetContainerInfo Function
-func (ch *ContainerdHandler) GetContainerInfo(ctx context.Context, containerID string) (tp.Container, error) {
+func (ch *ContainerdHandler) GetContainerInfo(containerID string) (tp.Container, error) {
+	var res *pb.GetContainerResponse
+	var err error
+
 	req := pb.GetContainerRequest{ID: containerID}
-	res, err := ch.client.Get(ctx, &req)
+
+	for _, ctx := range ch.getContainerContext(containerID) {
+		if res, err = ch.client.Get(ctx, &req); err == nil {
+			break
+		}
+	}
+
 	if err != nil {
 		return tp.Container{}, err
 	}
@@ -155,252 +196,142 @@ func (ch *ContainerdHandler) GetContainerInfo(ctx context.Context, containerID s
 	spec := iface.(*specs.Spec)
 	container.AppArmorProfile = spec.Process.ApparmorProfile
 
-	// == //
+	return container, nil
+}
 
-	taskReq := pt.ListPidsRequest{ContainerID: container.ContainerID}
-	if taskRes, err := Containerd.taskClient.ListPids(ctx, &taskReq); err == nil {
-		if len(taskRes.Processes) == 0 {
-			return container, err
-		}
+// GetContainerNamespaces Function
+func (ch *ContainerdHandler) GetContainerNamespaces(containerID string) (uint32, uint32, error) {
+	var res *pt.ListPidsResponse
+	var err error
 
-		pid := strconv.Itoa(int(taskRes.Processes[0].Pid))
+	req := pt.ListPidsRequest{ContainerID: containerID}
 
-		if data, err := kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pid + "/ns/pid"}); err == nil {
-			if _, err := fmt.Sscanf(data, "pid:[%d]\n", &container.PidNS); err != nil {
-				kg.Errf("Failed to get PidNS (%s, %s, %s)", containerID, pid, err.Error())
-			}
+	for _, ctx := range ch.getContainerContext(containerID) {
+		if res, err = ch.taskClient.ListPids(ctx, &req); err == nil {
+			break
 		}
+	}
 
-		if data, err := kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pid + "/ns/mnt"}); err == nil {
-			if _, err := fmt.Sscanf(data, "mnt:[%d]\n", &container.MntNS); err != nil {
-				kg.Errf("Failed to get MntNS (%s, %s, %s)", containerID, pid, err.Error())
-			}
-		}
-	} else {
-		return container, err
+	if err != nil {
+		return 0, 0, err
 	}
 
-	// == //
+	if len(res.Processes) == 0 {
+		return 0, 0, errors.New("no process")
+	}
 
-	return container, nil
+	return ReadNamespaceInodes(int(res.Processes[0].Pid))
 }
 
 // ======================= //
 // == Containerd Events == //
 // ======================= //
 
-// GetContainerdContainers Function
-func (ch *ContainerdHandler) GetContainerdContainers() map[string]context.Context {
+// ListContainers Function
+func (ch *ContainerdHandler) ListContainers() ([]string, error) {
 	containers := map[string]context.Context{}
 
-	req := pb.ListContainersRequest{}
-
-	if containerList, err := ch.client.List(ch.docker, &req); err == nil {
-		for _, container := range containerList.Containers {
-			containers[container.ID] = ch.docker
-		}
-	}
+	req := pt.ListTasksRequest{}
 
-	if containerList, err := ch.client.List(ch.containerd, &req); err == nil {
-		for _, container := range containerList.Containers {
-			containers[container.ID] = ch.containerd
+	for _, ctx := range []context.Context{ch.docker, ch.containerd} {
+		taskList, err := ch.taskClient.List(ctx, &req)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	return containers
-}
 
-// GetNewContainerdContainers Function
-func (ch *ContainerdHandler) GetNewContainerdContainers(containers map[string]context.Context) map[string]context.Context {
-	newContainers := map[string]context.Context{}
-
-	for activeContainerID, context := range containers {
-		if _, ok := ch.containers[activeContainerID]; !ok {
-			newContainers[activeContainerID] = context
+		// only the containers with live tasks (paused ones are still alive)
+		for _, t := range taskList.Tasks {
+			if t.Status == task.StatusRunning || t.Status == task.StatusPaused || t.Status == task.StatusPausing {
+				containers[t.ContainerID] = ctx
+			}
 		}
 	}
 
-	return newContainers
-}
-
-// GetDeletedContainerdContainers Function
-func (ch *ContainerdHandler) GetDeletedContainerdContainers(containers map[string]context.Context) map[string]context.Context {
-	deletedContainers := map[string]context.Context{}
+	ch.containersLock.Lock()
+	ch.containers = containers
+	ch.containersLock.Unlock()
 
-	for globalContainerID := range ch.containers {
-		if _, ok := containers[globalContainerID]; !ok {
-			delete(ch.containers, globalContainerID)
-		}
+	containerIDs := []string{}
+	for containerID := range containers {
+		containerIDs = append(containerIDs, containerID)
 	}
 
-	ch.containers = containers
-
-	return deletedContainers
+	return containerIDs, nil
 }
 
-// UpdateContainerdContainer Function
-func (dm *KubeArmorDaemon) UpdateContainerdContainer(ctx context.Context, containerID, action string) bool {
-	// check if Containerd exists
-	if Containerd == nil {
-		return false
+// decodeContainerdEvent Function
+func (ch *ContainerdHandler) decodeContainerdEvent(envelope *pe.Envelope) (ContainerEvent, bool) {
+	ctx, ok := ch.GetNamespaceContext(envelope.Namespace)
+	if !ok || envelope.Event == nil {
+		return ContainerEvent{}, false
 	}
 
-	if action == "start" {
-		// get container information from containerd client
-		container, err := Containerd.GetContainerInfo(ctx, containerID)
-		if err != nil {
-			return false
+	switch envelope.Topic {
+	case "/tasks/start":
+		event := ev.TaskStart{}
+		if err := event.Unmarshal(envelope.Event.Value); err != nil {
+			kg.Errf("Failed to decode a containerd event (%s, %s)", envelope.Topic, err.Error())
+			return ContainerEvent{}, false
 		}
 
-		if container.ContainerID == "" {
-			return false
-		}
+		ch.containersLock.Lock()
+		ch.containers[event.ContainerID] = ctx
+		ch.containersLock.Unlock()
 
-		dm.ContainersLock.Lock()
-		if _, ok := dm.Containers[container.ContainerID]; !ok {
-			dm.Containers[container.ContainerID] = container
-			dm.ContainersLock.Unlock()
-		} else if dm.Containers[container.ContainerID].PidNS == 0 && dm.Containers[container.ContainerID].MntNS == 0 {
-			// this entry was updated by kubernetes before docker detects it
-			// thus, we here use the info given by kubernetes instead of the info given by docker
-
-			container.NamespaceName = dm.Containers[container.ContainerID].NamespaceName
-			container.EndPointName = dm.Containers[container.ContainerID].EndPointName
-			container.ContainerName = dm.Containers[container.ContainerID].ContainerName
-
-			container.PolicyEnabled = dm.Containers[container.ContainerID].PolicyEnabled
-
-			container.ProcessVisibilityEnabled = dm.Containers[container.ContainerID].ProcessVisibilityEnabled
-			container.FileVisibilityEnabled = dm.Containers[container.ContainerID].FileVisibilityEnabled
-			container.NetworkVisibilityEnabled = dm.Containers[container.ContainerID].NetworkVisibilityEnabled
-			container.CapabilitiesVisibilityEnabled = dm.Containers[container.ContainerID].CapabilitiesVisibilityEnabled
-
-			dm.Containers[container.ContainerID] = container
-			dm.ContainersLock.Unlock()
-
-			dm.EndPointsLock.Lock()
-			for idx, endPoint := range dm.EndPoints {
-				if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
-					// update containers
-					if !kl.ContainsElement(endPoint.Containers, container.ContainerID) {
-						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers, container.ContainerID)
-					}
-
-					// update apparmor profiles
-					if !kl.ContainsElement(endPoint.AppArmorProfiles, container.AppArmorProfile) {
-						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles, container.AppArmorProfile)
-					}
-
-					break
-				}
-			}
-			dm.EndPointsLock.Unlock()
-		} else {
-			dm.ContainersLock.Unlock()
-			return false
+		return ContainerEvent{ContainerID: event.ContainerID, Action: ContainerStarted}, true
+
+	case "/tasks/exit":
+		event := ev.TaskExit{}
+		if err := event.Unmarshal(envelope.Event.Value); err != nil {
+			kg.Errf("Failed to decode a containerd event (%s, %s)", envelope.Topic, err.Error())
+			return ContainerEvent{}, false
 		}
 
-		if dm.SystemMonitor != nil {
-			// update NsMap
-			dm.SystemMonitor.AddContainerIDToNsMap(containerID, container.PidNS, container.MntNS)
+		// exec'd processes exit with their own ids
+		if event.ID != event.ContainerID {
+			return ContainerEvent{}, false
 		}
 
-		dm.Logger.Printf("Detected a container (added/%s)", containerID[:12])
+		return ContainerEvent{ContainerID: event.ContainerID, Action: ContainerDestroyed}, true
 
-	} else if action == "destroy" {
-		dm.ContainersLock.Lock()
-		container, ok := dm.Containers[containerID]
-		if !ok {
-			dm.ContainersLock.Unlock()
-			return false
+	case "/containers/delete":
+		event := ev.ContainerDelete{}
+		if err := event.Unmarshal(envelope.Event.Value); err != nil {
+			kg.Errf("Failed to decode a containerd event (%s, %s)", envelope.Topic, err.Error())
+			return ContainerEvent{}, false
 		}
-		delete(dm.Containers, containerID)
-		dm.ContainersLock.Unlock()
-
-		dm.EndPointsLock.Lock()
-		for idx, endPoint := range dm.EndPoints {
-			if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
-				// update containers
-				for idxC, containerID := range endPoint.Containers {
-					if containerID == container.ContainerID {
-						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers[:idxC], dm.EndPoints[idx].Containers[idxC+1:]...)
-						break
-					}
-				}
-
-				// update apparmor profiles
-				for idxA, profile := range endPoint.AppArmorProfiles {
-					if profile == container.AppArmorProfile {
-						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles[:idxA], dm.EndPoints[idx].AppArmorProfiles[idxA+1:]...)
-						break
-					}
-				}
-
-				break
-			}
-		}
-		dm.EndPointsLock.Unlock()
 
-		if dm.SystemMonitor != nil {
-			// update NsMap
-			dm.SystemMonitor.DeleteContainerIDFromNsMap(containerID)
-		}
+		ch.containersLock.Lock()
+		delete(ch.containers, event.ID)
+		ch.containersLock.Unlock()
 
-		dm.Logger.Printf("Detected a container (removed/%s)", containerID[:12])
+		// in case that the exit event was missed
+		return ContainerEvent{ContainerID: event.ID, Action: ContainerDestroyed}, true
 	}
 
-	return true
+	return ContainerEvent{}, false
 }
 
-// MonitorContainerdEvents Function
-func (dm *KubeArmorDaemon) MonitorContainerdEvents() {
-	dm.WgDaemon.Add(1)
-	defer dm.WgDaemon.Done()
+// WatchContainerEvents Function
+func (ch *ContainerdHandler) WatchContainerEvents(ctx context.Context, events chan<- ContainerEvent, ready chan<- struct{}) error {
+	req := pe.SubscribeRequest{Filters: ContainerdEventFilters}
 
-	// check if Containerd exists
-	if Containerd == nil {
-		return
+	stream, err := ch.eventsClient.Subscribe(ctx, &req)
+	if err != nil {
+		return err
 	}
-
-	dm.Logger.Print("Started to monitor Containerd events")
+	close(ready)
 
 	for {
-		select {
-		case <-StopChan:
-			return
-
-		default:
-			containers := Containerd.GetContainerdContainers()
-
-			if len(containers) == len(Containerd.containers) {
-				time.Sleep(time.Millisecond * 10)
-				continue
-			}
-
-			invalidContainers := []string{}
-
-			newContainers := Containerd.GetNewContainerdContainers(containers)
-			deletedContainers := Containerd.GetDeletedContainerdContainers(containers)
-
-			if len(newContainers) > 0 {
-				for containerID, context := range newContainers {
-					if !dm.UpdateContainerdContainer(context, containerID, "start") {
-						invalidContainers = append(invalidContainers, containerID)
-					}
-				}
-			}
-
-			for _, invalidContainerID := range invalidContainers {
-				delete(Containerd.containers, invalidContainerID)
-			}
+		envelope, err := stream.Recv()
+		if err != nil {
+			return err
+		}
 
-			if len(deletedContainers) > 0 {
-				for containerID, context := range deletedContainers {
-					dm.UpdateContainerdContainer(context, containerID, "destroy")
-				}
+		if event, ok := ch.decodeContainerdEvent(envelope); ok {
+			if err := SendContainerEvent(ctx, events, event); err != nil {
+				return err
 			}
 		}
-
-		time.Sleep(time.Millisecond * 50)
 	}
 }
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+
+	"google.golang.org/grpc"
+	pb "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// =================== //
+// == CRI-O Handler == //
+// =================== //
+
+// CrioContainerInfo Structure (the verbose info of a container in CRI-O)
+type CrioContainerInfo struct {
+	Pid         int `json:"pid"`
+	RuntimeSpec struct {
+		Process struct {
+			ApparmorProfile string `json:"apparmorProfile"`
+		} `json:"process"`
+	} `json:"runtimeSpec"`
+}
+
+// CrioHandler Structure
+type CrioHandler struct {
+	// connection
+	conn *grpc.ClientConn
+
+	// runtime client
+	client pb.RuntimeServiceClient
+
+	// context
+	ctx context.Context
+}
+
+// NewCrioHandler Function
+func NewCrioHandler(socket string) *CrioHandler {
+	ch := &CrioHandler{}
+
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithInsecure())
+	if err != nil {
+		return nil
+	}
+
+	ch.conn = conn
+
+	// runtime client
+	ch.client = pb.NewRuntimeServiceClient(ch.conn)
+
+	// context
+	ch.ctx = context.Background()
+
+	return ch
+}
+
+// Name Function
+func (ch *CrioHandler) Name() string {
+	return CrioRuntime
+}
+
+// Close Function
+func (ch *CrioHandler) Close() {
+	if ch.conn != nil {
+		if err := ch.conn.Close(); err != nil {
+			kg.Err(err.Error())
+		}
+	}
+}
+
+// ==================== //
+// == Container Info == //
+// ==================== //
+
+// GetContainerInfo Function
+func (ch *CrioHandler) GetContainerInfo(containerID string) (tp.Container, error) {
+	req := pb.ContainerStatusRequest{ContainerId: containerID, Verbose: true}
+	res, err := ch.client.ContainerStatus(ch.ctx, &req)
+	if err != nil {
+		return tp.Container{}, err
+	}
+
+	if res.Status == nil {
+		return tp.Container{}, errors.New("no container status")
+	}
+
+	container := tp.Container{}
+
+	// == container base == //
+
+	container.ContainerID = res.Status.Id
+	container.ContainerName = res.Status.Id[:12]
+
+	container.NamespaceName = "Unknown"
+	container.EndPointName = "Unknown"
+
+	containerLabels := res.Status.Labels
+	if _, ok := containerLabels["io.kubernetes.pod.namespace"]; ok { // kubernetes
+		if val, ok := containerLabels["io.kubernetes.pod.namespace"]; ok {
+			container.NamespaceName = val
+		}
+		if val, ok := containerLabels["io.kubernetes.pod.name"]; ok {
+			container.EndPointName = val
+		}
+	}
+
+	// CRI-O puts the pid and the runtime spec in the verbose info
+	info := CrioContainerInfo{}
+	if err := json.Unmarshal([]byte(res.Info["info"]), &info); err != nil {
+		return tp.Container{}, err
+	}
+
+	container.AppArmorProfile = info.RuntimeSpec.Process.ApparmorProfile
+
+	return container, nil
+}
+
+// getContainerPid Function
+func (ch *CrioHandler) getContainerPid(containerID string) (int, error) {
+	req := pb.ContainerStatusRequest{ContainerId: containerID, Verbose: true}
+	res, err := ch.client.ContainerStatus(ch.ctx, &req)
+	if err != nil {
+		return 0, err
+	}
+
+	info := CrioContainerInfo{}
+	if err := json.Unmarshal([]byte(res.Info["info"]), &info); err != nil {
+		return 0, err
+	}
+
+	return info.Pid, nil
+}
+
+// GetContainerNamespaces Function
+func (ch *CrioHandler) GetContainerNamespaces(containerID string) (uint32, uint32, error) {
+	pid, err := ch.getContainerPid(containerID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return ReadNamespaceInodes(pid)
+}
+
+// ================== //
+// == CRI-O Events == //
+// ================== //
+
+// ListContainers Function
+func (ch *CrioHandler) ListContainers() ([]string, error) {
+	req := pb.ListContainersRequest{Filter: &pb.ContainerFilter{State: &pb.ContainerStateValue{State: pb.ContainerState_CONTAINER_RUNNING}}}
+
+	containerList, err := ch.client.ListContainers(ch.ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	containerIDs := []string{}
+	for _, container := range containerList.Containers {
+		containerIDs = append(containerIDs, container.Id)
+	}
+
+	return containerIDs, nil
+}
+
+// WatchContainerEvents Function
+func (ch *CrioHandler) WatchContainerEvents(ctx context.Context, events chan<- ContainerEvent, ready chan<- struct{}) error {
+	// CRI has no event stream, thus CRI-O containers are polled
+	return ErrNoEventStream
+}
@@ -7,13 +7,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
@@ -25,14 +24,6 @@ import (
 // == Docker Handler == //
 // ==================== //
 
-// Docker Handler
-var Docker *DockerHandler
-
-// init Function
-func init() {
-	Docker = NewDockerHandler()
-}
-
 // DockerVersion Structure
 type DockerVersion struct {
 	APIVersion string `json:"ApiVersion"`
@@ -45,13 +36,13 @@ type DockerHandler struct {
 }
 
 // NewDockerHandler Function
-func NewDockerHandler() *DockerHandler {
+func NewDockerHandler(socket string) *DockerHandler {
 	docker := &DockerHandler{}
 
 	// specify the docker api version that we want to use
 	// Versioned API: https://docs.docker.com/engine/api/
 
-	versionStr, err := kl.GetCommandOutputWithErr("curl", []string{"--unix-socket", "/var/run/docker.sock", "http://localhost/version"})
+	versionStr, err := kl.GetCommandOutputWithErr("curl", []string{"--unix-socket", socket, "http://localhost/version"})
 	if err != nil {
 		return nil
 	}
@@ -74,7 +65,7 @@ func NewDockerHandler() *DockerHandler {
 
 	// create a new client with the above env variable
 
-	DockerClient, err := client.NewClientWithOpts(client.FromEnv)
+	DockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithHost("unix://"+socket))
 	if err != nil {
 		return nil
 	}
@@ -83,6 +74,11 @@ func NewDockerHandler() *DockerHandler {
 	return docker
 }
 
+// Name Function
+func (dh *DockerHandler) Name() string {
+	return DockerRuntime
+}
+
 // Close Function
 func (dh *DockerHandler) Close() {
 	if dh.DockerClient != nil {
@@ -129,267 +125,82 @@ func (dh *DockerHandler) GetContainerInfo(containerID string) (tp.Container, err
 
 	container.AppArmorProfile = inspect.AppArmorProfile
 
-	// == //
-
-	pid := strconv.Itoa(inspect.State.Pid)
-
-	if data, err := kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pid + "/ns/pid"}); err == nil {
-		if _, err := fmt.Sscanf(data, "pid:[%d]\n", &container.PidNS); err != nil {
-			kg.Errf("Failed to get PidNS (%s, %s, %s)", containerID, pid, err.Error())
-		}
-	}
-
-	if data, err := kl.GetCommandOutputWithErr("readlink", []string{"/proc/" + pid + "/ns/mnt"}); err == nil {
-		if _, err := fmt.Sscanf(data, "mnt:[%d]\n", &container.MntNS); err != nil {
-			kg.Errf("Failed to get MntNS (%s, %s, %s)", containerID, pid, err.Error())
-		}
-	}
-
-	// == //
-
 	return container, nil
 }
 
-// ========================== //
-// == Docker Event Channel == //
-// ========================== //
+// GetContainerNamespaces Function
+func (dh *DockerHandler) GetContainerNamespaces(containerID string) (uint32, uint32, error) {
+	if dh.DockerClient == nil {
+		return 0, 0, errors.New("no docker client")
+	}
 
-// GetEventChannel Function
-func (dh *DockerHandler) GetEventChannel() <-chan events.Message {
-	if dh.DockerClient != nil {
-		event, _ := dh.DockerClient.Events(context.Background(), types.EventsOptions{})
-		return event
+	inspect, err := dh.DockerClient.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	return nil
+	return ReadNamespaceInodes(inspect.State.Pid)
 }
 
 // =================== //
 // == Docker Events == //
 // =================== //
 
-// GetAlreadyDeployedDockerContainers Function
-func (dm *KubeArmorDaemon) GetAlreadyDeployedDockerContainers() {
-	// check if Docker exists
-	if Docker == nil {
-		return
-	}
-
-	if containerList, err := Docker.DockerClient.ContainerList(context.Background(), types.ContainerListOptions{}); err == nil {
-		for _, dcontainer := range containerList {
-			// get container information from docker client
-			container, err := Docker.GetContainerInfo(dcontainer.ID)
-			if err != nil {
-				continue
-			}
-
-			if container.ContainerID == "" {
-				continue
-			}
-
-			if dcontainer.State == "running" {
-				dm.ContainersLock.Lock()
-				if _, ok := dm.Containers[container.ContainerID]; !ok {
-					dm.Containers[container.ContainerID] = container
-					dm.ContainersLock.Unlock()
-				} else if dm.Containers[container.ContainerID].PidNS == 0 && dm.Containers[container.ContainerID].MntNS == 0 {
-					// this entry was updated by kubernetes before docker detects it
-					// thus, we here use the info given by kubernetes instead of the info given by docker
-
-					container.NamespaceName = dm.Containers[container.ContainerID].NamespaceName
-					container.EndPointName = dm.Containers[container.ContainerID].EndPointName
-					container.ContainerName = dm.Containers[container.ContainerID].ContainerName
-
-					container.PolicyEnabled = dm.Containers[container.ContainerID].PolicyEnabled
-
-					container.ProcessVisibilityEnabled = dm.Containers[container.ContainerID].ProcessVisibilityEnabled
-					container.FileVisibilityEnabled = dm.Containers[container.ContainerID].FileVisibilityEnabled
-					container.NetworkVisibilityEnabled = dm.Containers[container.ContainerID].NetworkVisibilityEnabled
-					container.CapabilitiesVisibilityEnabled = dm.Containers[container.ContainerID].CapabilitiesVisibilityEnabled
-
-					dm.Containers[container.ContainerID] = container
-					dm.ContainersLock.Unlock()
-
-					dm.EndPointsLock.Lock()
-					for idx, endPoint := range dm.EndPoints {
-						if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
-							// update containers
-							if !kl.ContainsElement(endPoint.Containers, container.ContainerID) {
-								dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers, container.ContainerID)
-							}
-
-							// update apparmor profiles
-							if !kl.ContainsElement(endPoint.AppArmorProfiles, container.AppArmorProfile) {
-								dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles, container.AppArmorProfile)
-							}
-
-							break
-						}
-					}
-					dm.EndPointsLock.Unlock()
-				} else {
-					dm.ContainersLock.Unlock()
-					continue
-				}
-
-				if dm.SystemMonitor != nil {
-					// update NsMap
-					dm.SystemMonitor.AddContainerIDToNsMap(container.ContainerID, container.PidNS, container.MntNS)
-				}
-
-				dm.Logger.Printf("Detected a container (added/%s)", container.ContainerID[:12])
-			}
-		}
+// ListContainers Function
+func (dh *DockerHandler) ListContainers() ([]string, error) {
+	if dh.DockerClient == nil {
+		return nil, errors.New("no docker client")
 	}
-}
 
-// UpdateDockerContainer Function
-func (dm *KubeArmorDaemon) UpdateDockerContainer(containerID, action string) {
-	// check if Docker exists
-	if Docker == nil {
-		return
+	containerList, err := dh.DockerClient.ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
 	}
 
-	container := tp.Container{}
-
-	if action == "start" {
-		var err error
+	containerIDs := []string{}
 
-		// get container information from docker client
-		container, err = Docker.GetContainerInfo(containerID)
-		if err != nil {
-			return
+	for _, dcontainer := range containerList {
+		if dcontainer.State == "running" {
+			containerIDs = append(containerIDs, dcontainer.ID)
 		}
-
-		if container.ContainerID == "" {
-			return
-		}
-
-		dm.ContainersLock.Lock()
-		if _, ok := dm.Containers[containerID]; !ok {
-			dm.Containers[containerID] = container
-			dm.ContainersLock.Unlock()
-		} else if dm.Containers[containerID].PidNS == 0 && dm.Containers[containerID].MntNS == 0 {
-			// this entry was updated by kubernetes before docker detects it
-			// thus, we here use the info given by kubernetes instead of the info given by docker
-
-			container.NamespaceName = dm.Containers[containerID].NamespaceName
-			container.EndPointName = dm.Containers[containerID].EndPointName
-			container.ContainerName = dm.Containers[containerID].ContainerName
-
-			container.PolicyEnabled = dm.Containers[containerID].PolicyEnabled
-
-			container.ProcessVisibilityEnabled = dm.Containers[containerID].ProcessVisibilityEnabled
-			container.FileVisibilityEnabled = dm.Containers[containerID].FileVisibilityEnabled
-			container.NetworkVisibilityEnabled = dm.Containers[containerID].NetworkVisibilityEnabled
-			container.CapabilitiesVisibilityEnabled = dm.Containers[containerID].CapabilitiesVisibilityEnabled
-
-			dm.Containers[containerID] = container
-			dm.ContainersLock.Unlock()
-
-			dm.EndPointsLock.Lock()
-			for idx, endPoint := range dm.EndPoints {
-				if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
-					// update containers
-					if !kl.ContainsElement(endPoint.Containers, container.ContainerID) {
-						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers, container.ContainerID)
-					}
-
-					// update apparmor profiles
-					if !kl.ContainsElement(endPoint.AppArmorProfiles, container.AppArmorProfile) {
-						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles, container.AppArmorProfile)
-					}
-
-					break
-				}
-			}
-			dm.EndPointsLock.Unlock()
-		} else {
-			dm.ContainersLock.Unlock()
-			return
-		}
-
-		if dm.SystemMonitor != nil {
-			// update NsMap
-			dm.SystemMonitor.AddContainerIDToNsMap(containerID, container.PidNS, container.MntNS)
-		}
-
-		dm.Logger.Printf("Detected a container (added/%s)", containerID[:12])
-
-	} else if action == "stop" || action == "destroy" {
-		// case 1: kill -> die -> stop
-		// case 2: kill -> die -> destroy
-		// case 3: destroy
-
-		dm.ContainersLock.Lock()
-		container, ok := dm.Containers[containerID]
-		if !ok {
-			dm.ContainersLock.Unlock()
-			return
-		}
-		delete(dm.Containers, containerID)
-		dm.ContainersLock.Unlock()
-
-		dm.EndPointsLock.Lock()
-		for idx, endPoint := range dm.EndPoints {
-			if endPoint.NamespaceName == container.NamespaceName && endPoint.EndPointName == container.EndPointName {
-				// update containers
-				for idxC, containerID := range endPoint.Containers {
-					if containerID == container.ContainerID {
-						dm.EndPoints[idx].Containers = append(dm.EndPoints[idx].Containers[:idxC], dm.EndPoints[idx].Containers[idxC+1:]...)
-						break
-					}
-				}
-
-				// update apparmor profiles
-				for idxA, profile := range endPoint.AppArmorProfiles {
-					if profile == container.AppArmorProfile {
-						dm.EndPoints[idx].AppArmorProfiles = append(dm.EndPoints[idx].AppArmorProfiles[:idxA], dm.EndPoints[idx].AppArmorProfiles[idxA+1:]...)
-						break
-					}
-				}
-
-				break
-			}
-		}
-		dm.EndPointsLock.Unlock()
-
-		if dm.SystemMonitor != nil {
-			// update NsMap
-			dm.SystemMonitor.DeleteContainerIDFromNsMap(containerID)
-		}
-
-		dm.Logger.Printf("Detected a container (removed/%s)", containerID[:12])
 	}
-}
 
-// MonitorDockerEvents Function
-func (dm *KubeArmorDaemon) MonitorDockerEvents() {
-	dm.WgDaemon.Add(1)
-	defer dm.WgDaemon.Done()
+	return containerIDs, nil
+}
 
-	// check if Docker exists
-	if Docker == nil {
-		return
+// WatchContainerEvents Function
+func (dh *DockerHandler) WatchContainerEvents(ctx context.Context, events chan<- ContainerEvent, ready chan<- struct{}) error {
+	if dh.DockerClient == nil {
+		return errors.New("no docker client")
 	}
 
-	dm.Logger.Print("Started to monitor Docker events")
-
-	EventChan := Docker.GetEventChannel()
+	msgChan, errChan := dh.DockerClient.Events(ctx, types.EventsOptions{Filters: filters.NewArgs(filters.Arg("type", "container"))})
+	close(ready)
 
 	for {
 		select {
-		case <-StopChan:
-			return
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-errChan:
+			return err
 
-		case msg, valid := <-EventChan:
-			if !valid {
-				continue
+		case msg := <-msgChan:
+			// case 1: kill -> die -> stop
+			// case 2: kill -> die -> destroy
+			// case 3: destroy
+
+			var err error
+
+			switch msg.Action {
+			case "start":
+				err = SendContainerEvent(ctx, events, ContainerEvent{ContainerID: msg.ID, Action: ContainerStarted})
+			case "stop", "destroy":
+				err = SendContainerEvent(ctx, events, ContainerEvent{ContainerID: msg.ID, Action: ContainerDestroyed})
 			}
 
-			// if message type is container
-			if msg.Type == "container" {
-				dm.UpdateDockerContainer(msg.ID, msg.Action)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// K8s watcher states
+const (
+	K8sWatcherDisconnected = "disconnected"
+	K8sWatcherWaiting      = "waiting for CRD"
+	K8sWatcherConnected    = "connected"
+)
+
+// K8sWatcherRetryTimeout for a watcher that cannot reconnect
+const K8sWatcherRetryTimeout = time.Minute
+
+// K8sWatcherStaleTimeout for a watcher that has no activity
+// (informers renew their watches every 5-10 minutes)
+const K8sWatcherStaleTimeout = 2 * time.Hour
+
+// ================== //
+// == K8s Watchers == //
+// ================== //
+
+// K8sWatcherStatus Structure
+type K8sWatcherStatus struct {
+	State string
+
+	// last connection, event, or CRD check
+	LastActivity time.Time
+
+	LastEvent time.Time
+	Events    uint64
+}
+
+// updateK8sWatcher Function
+func (dm *KubeArmorDaemon) updateK8sWatcher(name, state string, event bool) {
+	dm.K8sWatchersLock.Lock()
+	defer dm.K8sWatchersLock.Unlock()
+
+	watcher, ok := dm.K8sWatchers[name]
+	if !ok {
+		watcher = &K8sWatcherStatus{}
+		dm.K8sWatchers[name] = watcher
+	}
+
+	now := time.Now()
+
+	watcher.State = state
+	watcher.LastActivity = now
+
+	if event {
+		watcher.LastEvent = now
+		watcher.Events++
+	}
+}
+
+// RegisterK8sWatcher Function
+func (dm *KubeArmorDaemon) RegisterK8sWatcher(name string) {
+	dm.updateK8sWatcher(name, K8sWatcherDisconnected, false)
+}
+
+// MarkK8sWatcherWaiting Function
+func (dm *KubeArmorDaemon) MarkK8sWatcherWaiting(name string) {
+	dm.updateK8sWatcher(name, K8sWatcherWaiting, false)
+}
+
+// MarkK8sWatcherConnected Function
+func (dm *KubeArmorDaemon) MarkK8sWatcherConnected(name string) {
+	dm.updateK8sWatcher(name, K8sWatcherConnected, false)
+}
+
+// MarkK8sWatcherEvent Function
+func (dm *KubeArmorDaemon) MarkK8sWatcherEvent(name string) {
+	dm.updateK8sWatcher(name, K8sWatcherConnected, true)
+}
+
+// MarkK8sWatcherDisconnected Function
+func (dm *KubeArmorDaemon) MarkK8sWatcherDisconnected(name string) {
+	dm.updateK8sWatcher(name, K8sWatcherDisconnected, false)
+}
+
+// GetK8sWatcherHealthStatus Function
+func (dm *KubeArmorDaemon) GetK8sWatcherHealthStatus() tp.HealthStatus {
+	health := tp.HealthStatus{Name: "k8sWatchers", Healthy: true, Details: map[string]string{}}
+
+	if !dm.K8sEnabled {
+		health.Message = "disabled (no Kubernetes)"
+		return health
+	}
+
+	dm.K8sWatchersLock.RLock()
+	defer dm.K8sWatchersLock.RUnlock()
+
+	names := []string{}
+	for name := range dm.K8sWatchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		watcher := dm.K8sWatchers[name]
+		age := time.Since(watcher.LastActivity)
+
+		lastEvent := "never"
+		if !watcher.LastEvent.IsZero() {
+			lastEvent = time.Since(watcher.LastEvent).Round(time.Second).String() + " ago"
+		}
+
+		health.Details[name] = fmt.Sprintf("%s, %d events, last event %s", watcher.State, watcher.Events, lastEvent)
+
+		if watcher.State == K8sWatcherDisconnected && age > K8sWatcherRetryTimeout {
+			health.Healthy = false
+			health.Message = fmt.Sprintf("failed to watch %s for %s", name, age.Round(time.Second).String())
+		} else if age > K8sWatcherStaleTimeout {
+			health.Healthy = false
+			health.Message = fmt.Sprintf("no activity in the %s watcher for %s", name, age.Round(time.Second).String())
+		}
+	}
+
+	return health
+}
+
+// ================== //
+// == Health Check == //
+// ================== //
+
+// IsReady Function
+func (dm *KubeArmorDaemon) IsReady() bool {
+	return atomic.LoadInt32(&dm.Ready) == 1
+}
+
+// SetReady Function
+func (dm *KubeArmorDaemon) SetReady() {
+	atomic.StoreInt32(&dm.Ready, 1)
+}
+
+// GetHealthStatus Function
+func (dm *KubeArmorDaemon) GetHealthStatus() []tp.HealthStatus {
+	statuses := []tp.HealthStatus{}
+
+	if dm.Logger != nil {
+		statuses = append(statuses, dm.Logger.GetHealthStatus())
+	}
+
+	// the system monitor and the runtime enforcer are being initialized until ready
+	if dm.IsReady() {
+		statuses = append(statuses, dm.SystemMonitor.GetHealthStatus())
+		statuses = append(statuses, dm.RuntimeEnforcer.GetHealthStatus())
+	} else {
+		statuses = append(statuses, tp.HealthStatus{Name: "systemMonitor", Healthy: true, Message: "initializing"})
+		statuses = append(statuses, tp.HealthStatus{Name: "runtimeEnforcer", Healthy: true, Message: "initializing"})
+	}
+
+	statuses = append(statuses, dm.GetK8sWatcherHealthStatus())
+
+	return statuses
+}
+
+// HealthResponse Structure
+type HealthResponse struct {
+	Status     string            `json:"status"`
+	Ready      bool              `json:"ready"`
+	Subsystems []tp.HealthStatus `json:"subsystems"`
+}
+
+// writeHealthResponse Function
+func (dm *KubeArmorDaemon) writeHealthResponse(w http.ResponseWriter, readiness bool) {
+	resp := HealthResponse{Status: "OK", Ready: dm.IsReady(), Subsystems: dm.GetHealthStatus()}
+
+	code := http.StatusOK
+
+	for _, health := range resp.Subsystems {
+		if !health.Healthy {
+			resp.Status = "Unhealthy"
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	if readiness && !resp.Ready {
+		if code == http.StatusOK {
+			resp.Status = "NotReady"
+		}
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		dm.Logger.Errf("Failed to write a health response (%s)", err.Error())
+	}
+}
+
+// HandleHealthz Function
+func (dm *KubeArmorDaemon) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	dm.writeHealthResponse(w, false)
+}
+
+// HandleReadyz Function
+func (dm *KubeArmorDaemon) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	dm.writeHealthResponse(w, true)
+}
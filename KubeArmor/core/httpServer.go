@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+)
+
+// ================= //
+// == HTTP Server == //
+// ================= //
+
+// InitHTTPServer Function
+func (dm *KubeArmorDaemon) InitHTTPServer() bool {
+	mux := http.NewServeMux()
+
+	// Prometheus metrics
+	mux.Handle("/metrics", mt.Handler())
+
+	// health probes
+	mux.HandleFunc("/healthz", dm.HandleHealthz)
+	mux.HandleFunc("/readyz", dm.HandleReadyz)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", dm.Config.HTTP))
+	if err != nil {
+		kg.Errf("Failed to listen a port (%s, %s)", dm.Config.HTTP, err.Error())
+		return false
+	}
+
+	dm.HTTPListener = listener
+	dm.HTTPServer = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	return true
+}
+
+// ServeHTTP Function
+func (dm *KubeArmorDaemon) ServeHTTP() {
+	dm.WgDaemon.Add(1)
+	defer dm.WgDaemon.Done()
+
+	if err := dm.HTTPServer.Serve(dm.HTTPListener); err != nil && err != http.ErrServerClosed {
+		kg.Errf("Terminated the HTTP server (%s)", err.Error())
+	}
+}
+
+// CloseHTTPServer Function
+func (dm *KubeArmorDaemon) CloseHTTPServer() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := dm.HTTPServer.Shutdown(ctx); err != nil {
+		kg.Errf("Failed to stop the HTTP server (%s)", err.Error())
+		return false
+	}
+
+	return true
+}
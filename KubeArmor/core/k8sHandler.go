@@ -4,28 +4,39 @@
 package core
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	rest "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
-	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
 )
 
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// SecurityPolicyResource for KubeArmorPolicies
+var SecurityPolicyResource = schema.GroupVersionResource{Group: "security.kubearmor.com", Version: "v1", Resource: "kubearmorpolicies"}
+
+// HostSecurityPolicyResource for KubeArmorHostPolicies
+var HostSecurityPolicyResource = schema.GroupVersionResource{Group: "security.kubearmor.com", Version: "v1", Resource: "kubearmorhostpolicies"}
+
 // ================= //
 // == K8s Handler == //
 // ================= //
@@ -40,9 +51,8 @@ func init() {
 
 // K8sHandler Structure
 type K8sHandler struct {
-	K8sClient   *kubernetes.Clientset
-	HTTPClient  *http.Client
-	WatchClient *http.Client
+	K8sClient     *kubernetes.Clientset
+	DynamicClient dynamic.Interface
 
 	K8sToken string
 	K8sHost  string
@@ -65,21 +75,6 @@ func NewK8sHandler() *K8sHandler {
 		kh.K8sPort = "8001" // kube-proxy
 	}
 
-	kh.HTTPClient = &http.Client{
-		Timeout: time.Second * 5,
-		// #nosec
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-
-	kh.WatchClient = &http.Client{
-		// #nosec
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-
 	return kh
 }
 
@@ -129,6 +124,13 @@ func (kh *K8sHandler) InitLocalAPIClient() bool {
 	}
 	kh.K8sClient = client
 
+	// for custom resources
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return false
+	}
+	kh.DynamicClient = dynamicClient
+
 	return true
 }
 
@@ -156,110 +158,70 @@ func (kh *K8sHandler) InitInclusterAPIClient() bool {
 	}
 	kh.K8sClient = client
 
-	return true
-}
-
-// ============== //
-// == API Call == //
-// ============== //
-
-// DoRequest Function
-func (kh *K8sHandler) DoRequest(cmd string, data interface{}, path string) ([]byte, error) {
-	URL := ""
-
-	if kl.IsInK8sCluster() {
-		URL = "https://" + kh.K8sHost + ":" + kh.K8sPort
-	} else {
-		URL = "http://" + kh.K8sHost + ":" + kh.K8sPort
-	}
-
-	pbytes, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(cmd, URL+path, bytes.NewBuffer(pbytes))
+	// for custom resources
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
 	if err != nil {
-		return nil, err
-	}
-
-	if kl.IsInK8sCluster() {
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kh.K8sToken))
-	}
-
-	resp, err := kh.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	resBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := resp.Body.Close(); err != nil {
-		kg.Err(err.Error())
+		return false
 	}
+	kh.DynamicClient = dynamicClient
 
-	return resBody, nil
+	return true
 }
 
 // ========== //
 // == Node == //
 // ========== //
 
-// WatchK8sNodes Function
-func (kh *K8sHandler) WatchK8sNodes() *http.Response {
+// NewNodeListWatch Function
+func (kh *K8sHandler) NewNodeListWatch(nodeName string) cache.ListerWatcher {
+	return cache.NewListWatchFromClient(kh.K8sClient.CoreV1().RESTClient(), "nodes", metav1.NamespaceAll, fields.OneTermEqualSelector("metadata.name", nodeName))
+}
+
+// ================ //
+// == Deployment == //
+// ================ //
+
+// PatchDeploymentWithAppArmorAnnotations Function
+func (kh *K8sHandler) PatchDeploymentWithAppArmorAnnotations(namespaceName, deploymentName string, appArmorAnnotations map[string]string) error {
 	if !kl.IsK8sEnv() { // not Kubernetes
 		return nil
 	}
 
-	if kl.IsInK8sCluster() { // kube-apiserver
-		URL := "https://" + kh.K8sHost + ":" + kh.K8sPort + "/api/v1/nodes?watch=true"
-
-		req, err := http.NewRequest("GET", URL, nil)
-		if err != nil {
-			return nil
-		}
+	spec := `{"spec":{"template":{"metadata":{"annotations":{"kubearmor-policy":"enabled",`
+	count := len(appArmorAnnotations)
 
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kh.K8sToken))
+	for k, v := range appArmorAnnotations {
+		spec = spec + `"container.apparmor.security.beta.kubernetes.io/` + k + `":"localhost/` + v + `"`
 
-		resp, err := kh.WatchClient.Do(req)
-		if err != nil {
-			return nil
+		if count > 1 {
+			spec = spec + ","
 		}
 
-		return resp
+		count--
 	}
 
-	// kube-proxy (local)
-	URL := "http://" + kh.K8sHost + ":" + kh.K8sPort + "/api/v1/nodes?watch=true"
+	spec = spec + `}}}}}`
 
-	// #nosec
-	if resp, err := http.Get(URL); err == nil {
-		return resp
+	_, err := kh.K8sClient.AppsV1().Deployments(namespaceName).Patch(context.Background(), deploymentName, types.StrategicMergePatchType, []byte(spec), metav1.PatchOptions{})
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// ================ //
-// == Deployment == //
-// ================ //
-
-// PatchDeploymentWithAppArmorAnnotations Function
-func (kh *K8sHandler) PatchDeploymentWithAppArmorAnnotations(namespaceName, deploymentName string, appArmorAnnotations map[string]string) error {
+// PatchDeploymentWithSeccompProfiles Function
+func (kh *K8sHandler) PatchDeploymentWithSeccompProfiles(namespaceName, deploymentName string, seccompProfiles map[string]string) error {
 	if !kl.IsK8sEnv() { // not Kubernetes
 		return nil
 	}
 
-	spec := `{"spec":{"template":{"metadata":{"annotations":{"kubearmor-policy":"enabled",`
-	count := len(appArmorAnnotations)
+	spec := `{"spec":{"template":{"metadata":{"annotations":{"kubearmor-policy":"enabled"}},"spec":{"containers":[`
+	count := len(seccompProfiles)
 
-	for k, v := range appArmorAnnotations {
-		spec = spec + `"container.apparmor.security.beta.kubernetes.io/` + k + `":"localhost/` + v + `"`
+	for k, v := range seccompProfiles {
+		// localhost profiles are relative to the seccomp directory of kubelet
+		spec = spec + `{"name":"` + k + `","securityContext":{"seccompProfile":{"type":"Localhost","localhostProfile":"` + strings.TrimPrefix(v, "localhost/") + `"}}}`
 
 		if count > 1 {
 			spec = spec + ","
@@ -268,7 +230,7 @@ func (kh *K8sHandler) PatchDeploymentWithAppArmorAnnotations(namespaceName, depl
 		count--
 	}
 
-	spec = spec + `}}}}}`
+	spec = spec + `]}}}}`
 
 	_, err := kh.K8sClient.AppsV1().Deployments(namespaceName).Patch(context.Background(), deploymentName, types.StrategicMergePatchType, []byte(spec), metav1.PatchOptions{})
 	if err != nil {
@@ -341,40 +303,9 @@ func (kh *K8sHandler) GetDeploymentNameControllingReplicaSet(namespaceName, repl
 // == Pods == //
 // ========== //
 
-// WatchK8sPods Function
-func (kh *K8sHandler) WatchK8sPods() *http.Response {
-	if !kl.IsK8sEnv() { // not Kubernetes
-		return nil
-	}
-
-	if kl.IsInK8sCluster() { // kube-apiserver
-		URL := "https://" + kh.K8sHost + ":" + kh.K8sPort + "/api/v1/pods?watch=true"
-
-		req, err := http.NewRequest("GET", URL, nil)
-		if err != nil {
-			return nil
-		}
-
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kh.K8sToken))
-
-		resp, err := kh.WatchClient.Do(req)
-		if err != nil {
-			return nil
-		}
-
-		return resp
-	}
-
-	// kube-proxy (local)
-	URL := "http://" + kh.K8sHost + ":" + kh.K8sPort + "/api/v1/pods?watch=true"
-
-	// #nosec
-	if resp, err := http.Get(URL); err == nil {
-		return resp
-	}
-
-	return nil
+// NewPodListWatch Function
+func (kh *K8sHandler) NewPodListWatch(nodeName string) cache.ListerWatcher {
+	return cache.NewListWatchFromClient(kh.K8sClient.CoreV1().RESTClient(), "pods", metav1.NamespaceAll, fields.OneTermEqualSelector("spec.nodeName", nodeName))
 }
 
 // ====================== //
@@ -387,108 +318,64 @@ func (kh *K8sHandler) CheckCustomResourceDefinition(resourceName string) bool {
 		return false
 	}
 
-	exist := false
-	apiGroup := metav1.APIGroup{}
-
 	// check APIGroup
-	if resBody, errOut := kh.DoRequest("GET", nil, "/apis"); errOut == nil {
-		res := metav1.APIGroupList{}
-		if errIn := json.Unmarshal(resBody, &res); errIn == nil {
-			for _, group := range res.Groups {
-				if group.Name == "security.kubearmor.com" {
-					exist = true
-					apiGroup = group
-					break
-				}
-			}
-		}
+	groups, err := kh.K8sClient.Discovery().ServerGroups()
+	if err != nil {
+		return false
 	}
 
-	// check APIResource
-	if exist {
-		if resBody, errOut := kh.DoRequest("GET", nil, "/apis/"+apiGroup.PreferredVersion.GroupVersion); errOut == nil {
-			res := metav1.APIResourceList{}
-			if errIn := json.Unmarshal(resBody, &res); errIn == nil {
-				for _, resource := range res.APIResources {
-					if resource.Name == resourceName {
-						return true
-					}
-				}
-			}
+	for _, group := range groups.Groups {
+		if group.Name != "security.kubearmor.com" {
+			continue
 		}
-	}
 
-	return false
-}
-
-// WatchK8sSecurityPolicies Function
-func (kh *K8sHandler) WatchK8sSecurityPolicies() *http.Response {
-	if !kl.IsK8sEnv() { // not Kubernetes
-		return nil
-	}
-
-	if kl.IsInK8sCluster() {
-		URL := "https://" + kh.K8sHost + ":" + kh.K8sPort + "/apis/security.kubearmor.com/v1/kubearmorpolicies?watch=true"
-
-		req, err := http.NewRequest("GET", URL, nil)
+		// check APIResource
+		resources, err := kh.K8sClient.Discovery().ServerResourcesForGroupVersion(group.PreferredVersion.GroupVersion)
 		if err != nil {
-			return nil
+			return false
 		}
 
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kh.K8sToken))
-
-		resp, err := kh.WatchClient.Do(req)
-		if err != nil {
-			return nil
+		for _, resource := range resources.APIResources {
+			if resource.Name == resourceName {
+				return true
+			}
 		}
-
-		return resp
 	}
 
-	// kube-proxy (local)
-	URL := "http://" + kh.K8sHost + ":" + kh.K8sPort + "/apis/security.kubearmor.com/v1/kubearmorpolicies?watch=true"
+	return false
+}
 
-	// #nosec
-	if resp, err := http.Get(URL); err == nil {
-		return resp
+// NewCustomResourceListWatch Function
+func (kh *K8sHandler) NewCustomResourceListWatch(resource schema.GroupVersionResource) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return kh.DynamicClient.Resource(resource).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return kh.DynamicClient.Resource(resource).Watch(context.Background(), options)
+		},
 	}
-
-	return nil
 }
 
-// WatchK8sHostSecurityPolicies Function
-func (kh *K8sHandler) WatchK8sHostSecurityPolicies() *http.Response {
+// =============== //
+// == ConfigMap == //
+// =============== //
+
+// GetK8sConfigMap Function
+func (kh *K8sHandler) GetK8sConfigMap(namespaceName, configMapName string) (map[string]string, error) {
 	if !kl.IsK8sEnv() { // not Kubernetes
-		return nil
+		return nil, fmt.Errorf("not Kubernetes")
 	}
 
-	if kl.IsInK8sCluster() {
-		URL := "https://" + kh.K8sHost + ":" + kh.K8sPort + "/apis/security.kubearmor.com/v1/kubearmorhostpolicies?watch=true"
-
-		req, err := http.NewRequest("GET", URL, nil)
-		if err != nil {
-			return nil
-		}
-
-		req.Header.Add("Content-Type", "application/json")
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kh.K8sToken))
-
-		resp, err := kh.WatchClient.Do(req)
-		if err != nil {
-			return nil
-		}
-
-		return resp
+	cm, err := kh.K8sClient.CoreV1().ConfigMaps(namespaceName).Get(context.Background(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
 	}
 
-	// kube-proxy (local)
-	URL := "http://" + kh.K8sHost + ":" + kh.K8sPort + "/apis/security.kubearmor.com/v1/kubearmorhostpolicies?watch=true"
-
-	// #nosec
-	if resp, err := http.Get(URL); err == nil {
-		return resp
-	}
+	return cm.Data, nil
+}
 
-	return nil
+// NewConfigMapListWatch Function
+func (kh *K8sHandler) NewConfigMapListWatch(namespaceName, configMapName string) cache.ListerWatcher {
+	return cache.NewListWatchFromClient(kh.K8sClient.CoreV1().RESTClient(), "configmaps", namespaceName, fields.OneTermEqualSelector("metadata.name", configMapName))
 }
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// K8sInformerResyncPeriod for informers
+// (no periodic resync, reflectors resume watches from the last resourceVersion and relist when it is too old)
+const K8sInformerResyncPeriod = 0
+
+// =================== //
+// == K8s Informers == //
+// =================== //
+
+// k8sWatcherListWatch Structure (updates the state of a watcher on every list and watch)
+type k8sWatcherListWatch struct {
+	dm   *KubeArmorDaemon
+	name string
+	lw   cache.ListerWatcher
+}
+
+// List Function
+func (w *k8sWatcherListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	obj, err := w.lw.List(options)
+	if err != nil {
+		w.dm.MarkK8sWatcherDisconnected(w.name)
+		return nil, err
+	}
+
+	w.dm.MarkK8sWatcherConnected(w.name)
+
+	return obj, nil
+}
+
+// Watch Function
+func (w *k8sWatcherListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := w.lw.Watch(options)
+	if err != nil {
+		w.dm.MarkK8sWatcherDisconnected(w.name)
+		return nil, err
+	}
+
+	w.dm.MarkK8sWatcherConnected(w.name)
+
+	return watcher, nil
+}
+
+// isSameResourceVersion Function
+func isSameResourceVersion(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+// NewK8sInformer Function
+func (dm *KubeArmorDaemon) NewK8sInformer(name string, lw cache.ListerWatcher, objType runtime.Object, handler func(eventType string, obj interface{})) cache.SharedIndexInformer {
+	informer := cache.NewSharedIndexInformer(&k8sWatcherListWatch{dm: dm, name: name, lw: lw}, objType, K8sInformerResyncPeriod, cache.Indexers{})
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			dm.MarkK8sWatcherEvent(name)
+			handler("ADDED", obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// skip the objects not changed while relisting
+			if isSameResourceVersion(oldObj, newObj) {
+				return
+			}
+
+			dm.MarkK8sWatcherEvent(name)
+			handler("MODIFIED", newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			// the last state of an object deleted while disconnected
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			dm.MarkK8sWatcherEvent(name)
+			handler("DELETED", obj)
+		},
+	})
+
+	// the informer has not started yet, so this cannot fail
+	_ = informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		dm.MarkK8sWatcherDisconnected(name)
+		cache.DefaultWatchErrorHandler(r, err)
+	})
+
+	return informer
+}
+
+// DecodeUnstructured Function
+func DecodeUnstructured(obj interface{}, out interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object %T", obj)
+	}
+
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
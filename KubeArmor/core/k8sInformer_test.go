@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func newTestPod(name, resourceVersion string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "multiubuntu", Name: name, ResourceVersion: resourceVersion}}
+}
+
+func TestK8sInformer(t *testing.T) {
+	dm := newTestDaemon(nil)
+
+	dm.K8sWatchers = map[string]*K8sWatcherStatus{}
+	dm.K8sWatchersLock = new(sync.RWMutex)
+
+	fakeWatcher := watch.NewFake()
+
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return &v1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: []v1.Pod{*newTestPod("pod-a", "1")}}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return fakeWatcher, nil
+		},
+	}
+
+	events := make(chan string, 8)
+
+	informer := dm.NewK8sInformer("pods", lw, &v1.Pod{}, func(eventType string, obj interface{}) {
+		if pod, ok := obj.(*v1.Pod); ok {
+			events <- eventType + "/" + pod.Name
+		}
+	})
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+
+	go informer.Run(stopChan)
+
+	fakeWatcher.Add(newTestPod("pod-b", "2"))
+	fakeWatcher.Modify(newTestPod("pod-a", "3"))
+	fakeWatcher.Delete(newTestPod("pod-b", "4"))
+
+	// events are in order for each object
+	expected := map[string]string{"pod-a": "ADDED,MODIFIED", "pod-b": "ADDED,DELETED"}
+	received := map[string]string{}
+
+	for count := 0; count < 4; count++ {
+		select {
+		case event := <-events:
+			pod := strings.Split(event, "/")[1]
+			if received[pod] != "" {
+				received[pod] = received[pod] + ","
+			}
+			received[pod] = received[pod] + strings.Split(event, "/")[0]
+		case <-time.After(time.Second * 5):
+			t.Errorf("[FAIL] Failed to get events (%v)", received)
+			return
+		}
+	}
+
+	for pod, types := range expected {
+		if received[pod] != types {
+			t.Errorf("[FAIL] Got unexpected events for %s (%s, expected %s)", pod, received[pod], types)
+			return
+		}
+	}
+	t.Log("[PASS] Got events from the informer")
+
+	dm.K8sWatchersLock.RLock()
+	watcher := dm.K8sWatchers["pods"]
+	dm.K8sWatchersLock.RUnlock()
+
+	if watcher == nil || watcher.State != K8sWatcherConnected || watcher.Events != 4 {
+		t.Errorf("[FAIL] Failed to update the state of the watcher (%+v)", watcher)
+		return
+	}
+	t.Log("[PASS] Updated the state of the watcher")
+}
+
+func TestDecodeUnstructured(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "security.kubearmor.com/v1",
+		"kind":       "KubeArmorPolicy",
+		"metadata":   map[string]interface{}{"namespace": "multiubuntu", "name": "ksp-block-bash"},
+		"spec": map[string]interface{}{
+			"severity": int64(5),
+			"action":   "Block",
+			"process": map[string]interface{}{
+				"matchPaths": []interface{}{map[string]interface{}{"path": "/bin/bash"}},
+			},
+		},
+	}}
+
+	policy := tp.K8sKubeArmorPolicy{}
+
+	if err := DecodeUnstructured(obj, &policy); err != nil {
+		t.Errorf("[FAIL] Failed to decode a policy (%s)", err.Error())
+		return
+	}
+
+	if policy.Metadata.Name != "ksp-block-bash" || policy.Spec.Severity != 5 || len(policy.Spec.Process.MatchPaths) != 1 || policy.Spec.Process.MatchPaths[0].Path != "/bin/bash" {
+		t.Errorf("[FAIL] Decoded an unexpected policy (%+v)", policy)
+		return
+	}
+	t.Log("[PASS] Decoded a policy")
+
+	if err := DecodeUnstructured(newTestPod("pod-a", "1"), &policy); err == nil {
+		t.Errorf("[FAIL] Decoded an object that is not unstructured")
+		return
+	}
+	t.Log("[PASS] Rejected an object that is not unstructured")
+}
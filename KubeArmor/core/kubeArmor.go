@@ -4,6 +4,8 @@
 package core
 
 import (
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -15,9 +17,13 @@ import (
 	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 
+	dc "github.com/kubearmor/KubeArmor/KubeArmor/discovery"
 	efc "github.com/kubearmor/KubeArmor/KubeArmor/enforcer"
 	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
 	mon "github.com/kubearmor/KubeArmor/KubeArmor/monitor"
+
+	cfg "github.com/kubearmor/KubeArmor/KubeArmor/config"
+	pb "github.com/kubearmor/KubeArmor/protobuf"
 )
 
 // ====================== //
@@ -38,21 +44,50 @@ type KubeArmorDaemon struct {
 	ClusterName string
 
 	// node
-	Node tp.Node
+	Node     tp.Node
+	NodeLock *sync.RWMutex
 
 	// gRPC
 	gRPCPort  string
 	LogPath   string
 	LogFilter string
 
+	// configuration in use
+	Config           cfg.KubeArmorConfig
+	ConfigLock       *sync.RWMutex
+	ConfigUpdateLock *sync.Mutex
+
+	// configuration layers (flags < config file < ConfigMap)
+	BaseConfig    cfg.KubeArmorConfig
+	ConfigFile    []byte
+	ConfigMapData []byte
+
+	// HTTP server (metrics + health probes)
+	HTTPListener net.Listener
+	HTTPServer   *http.Server
+
 	// options
 	EnableKubeArmorPolicy     bool
 	EnableKubeArmorHostPolicy bool
+	EnableSeccompEnforcer     bool
 
 	// flag
 	K8sEnabled bool
 
-	// containers (from docker)
+	// K8s watcher name -> status
+	K8sWatchers     map[string]*K8sWatcherStatus
+	K8sWatchersLock *sync.RWMutex
+
+	// readiness (1 after initialization)
+	Ready int32
+
+	// container runtime
+	Runtime ContainerRuntime
+
+	// containers known to the runtime
+	RuntimeContainers map[string]bool
+
+	// containers (from the container runtime)
 	Containers     map[string]tp.Container
 	ContainersLock *sync.RWMutex
 
@@ -87,6 +122,9 @@ type KubeArmorDaemon struct {
 	// system monitor
 	SystemMonitor *mon.SystemMonitor
 
+	// learning mode
+	Discovery *dc.Discovery
+
 	// runtime enforcer
 	RuntimeEnforcer *efc.RuntimeEnforcer
 
@@ -95,27 +133,42 @@ type KubeArmorDaemon struct {
 }
 
 // NewKubeArmorDaemon Function
-func NewKubeArmorDaemon(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, enableKubeArmorHostPolicy bool) *KubeArmorDaemon {
+func NewKubeArmorDaemon(config cfg.KubeArmorConfig) *KubeArmorDaemon {
 	dm := new(KubeArmorDaemon)
 
-	if clusterName == "" {
+	if config.Cluster == "" {
 		if val, ok := os.LookupEnv("CLUSTER_NAME"); ok {
-			dm.ClusterName = val
+			config.Cluster = val
 		} else {
-			dm.ClusterName = "Default"
+			config.Cluster = "Default"
 		}
-	} else {
-		dm.ClusterName = clusterName
 	}
 
-	dm.gRPCPort = gRPCPort
-	dm.LogPath = logPath
+	dm.Config = config
+	dm.ConfigLock = new(sync.RWMutex)
+	dm.ConfigUpdateLock = new(sync.Mutex)
+
+	dm.BaseConfig = config
+
+	dm.ClusterName = config.Cluster
 
-	dm.EnableKubeArmorPolicy = enableKubeArmorPolicy
-	dm.EnableKubeArmorHostPolicy = enableKubeArmorHostPolicy
+	dm.gRPCPort = config.GRPC
+	dm.LogPath = config.LogPath
+
+	dm.EnableKubeArmorPolicy = config.EnableKubeArmorPolicy
+	dm.EnableKubeArmorHostPolicy = config.EnableKubeArmorHostPolicy
+	dm.EnableSeccompEnforcer = config.EnableSeccompEnforcer
+
+	dm.Node = tp.Node{}
+	dm.NodeLock = new(sync.RWMutex)
 
 	dm.K8sEnabled = false
 
+	dm.K8sWatchers = map[string]*K8sWatcherStatus{}
+	dm.K8sWatchersLock = new(sync.RWMutex)
+
+	dm.Ready = 0
+
 	dm.Containers = map[string]tp.Container{}
 	dm.ContainersLock = new(sync.RWMutex)
 
@@ -163,6 +216,20 @@ func (dm *KubeArmorDaemon) DestroyKubeArmorDaemon() {
 		}
 	}
 
+	if dm.Runtime != nil {
+		// close container runtime
+		if dm.CloseContainerRuntime() {
+			dm.Logger.Print("Stopped the container runtime client")
+		}
+	}
+
+	if dm.HTTPServer != nil {
+		// close HTTP server
+		if dm.CloseHTTPServer() {
+			kg.Print("Stopped the HTTP server")
+		}
+	}
+
 	if dm.Logger != nil {
 		dm.Logger.Print("Terminated the KubeArmor")
 	} else {
@@ -190,8 +257,37 @@ func (dm *KubeArmorDaemon) DestroyKubeArmorDaemon() {
 
 // InitLogger Function
 func (dm *KubeArmorDaemon) InitLogger() bool {
+	// set queue sizes before creating the feeder
+	fd.SetQueueSizes(dm.Config.MessageQueueSize, dm.Config.AlertQueueSize, dm.Config.LogQueueSize)
+
 	dm.Logger = fd.NewFeeder(dm.ClusterName, &dm.Node, dm.gRPCPort, dm.LogPath)
-	return dm.Logger != nil
+	if dm.Logger == nil {
+		return false
+	}
+
+	// share the lock of the node with the feeder
+	dm.Logger.NodeLock = dm.NodeLock
+
+	// report the health of all subsystems in gRPC health checks
+	dm.Logger.SetHealthProvider(dm.GetHealthStatus)
+
+	// set options for gRPC subscribers
+	dm.Logger.SetSubscriberOptions(dm.Config.SubscriberBufferSize, dm.Config.SlowSubscriberPolicy)
+
+	// set replay buffers for resumed subscribers
+	if err := dm.Logger.SetReplayOptions(dm.Config.ReplayBufferSize, dm.Config.ReplayDir); err != nil {
+		kg.Errf("Failed to load the replay logs from %s (%s), keeping replay buffers in memory", dm.Config.ReplayDir, err.Error())
+
+		dm.Config.ReplayDir = ""
+		_ = dm.Logger.SetReplayOptions(dm.Config.ReplayBufferSize, "")
+	}
+
+	// add output sinks
+	if err := dm.Logger.SetOutputSinks(dm.Config.OutputSinks, dm.Config.Sinks); err != nil {
+		kg.Errf("Failed to add output sinks (%s)", err.Error())
+	}
+
+	return true
 }
 
 // ServeLogFeeds Function
@@ -211,23 +307,79 @@ func (dm *KubeArmorDaemon) CloseLogger() bool {
 	return true
 }
 
+// =============== //
+// == Discovery == //
+// =============== //
+
+// InitDiscovery Function
+func (dm *KubeArmorDaemon) InitDiscovery() {
+	dm.Discovery = dc.NewDiscovery(dm.Config.Discovery)
+	dm.Discovery.GetPodLabels = dm.GetPodLabels
+
+	// serve discovered policies with log feeds
+	pb.RegisterDiscoveryServiceServer(dm.Logger.LogServer, &dc.Service{Discovery: dm.Discovery})
+}
+
+// GetPodLabels Function
+func (dm *KubeArmorDaemon) GetPodLabels(namespaceName, podName string) map[string]string {
+	dm.EndPointsLock.RLock()
+	defer dm.EndPointsLock.RUnlock()
+
+	for _, endPoint := range dm.EndPoints {
+		if endPoint.NamespaceName == namespaceName && endPoint.EndPointName == podName {
+			return endPoint.Labels
+		}
+	}
+
+	return nil
+}
+
+// WriteDiscoveredPolicies Function
+func (dm *KubeArmorDaemon) WriteDiscoveredPolicies() {
+	dm.WgDaemon.Add(1)
+	defer dm.WgDaemon.Done()
+
+	dm.Discovery.WritePoliciesPeriodically(StopChan)
+}
+
 // ==================== //
 // == System Monitor == //
 // ==================== //
 
 // InitSystemMonitor Function
 func (dm *KubeArmorDaemon) InitSystemMonitor() bool {
-	dm.SystemMonitor = mon.NewSystemMonitor(dm.Node, dm.Logger, &dm.Containers, &dm.ContainersLock,
+	dm.SystemMonitor = mon.NewSystemMonitor(dm.GetNode(), dm.Logger, &dm.Containers, &dm.ContainersLock,
 		&dm.ActivePidMap, &dm.ActiveHostPidMap, &dm.ActivePidMapLock, &dm.ActiveHostMap, &dm.ActiveHostMapLock)
 	if dm.SystemMonitor == nil {
 		return false
 	}
 
+	// apply the configuration
+	dm.SystemMonitor.SetUntrackedNamespaces(dm.Config.UntrackedNamespaces)
+	dm.SystemMonitor.PerfPageCount = dm.Config.PerfPageCount
+	dm.SystemMonitor.LostEventsReportInterval = dm.Config.LostEventsReportInterval
+
+	dm.SystemMonitor.AncestryDepth = dm.Config.AncestryDepth
+
+	if err := dm.SystemMonitor.SetArgsFilter(dm.Config.MaxArgLength, dm.Config.ArgRedactionRules); err != nil {
+		dm.Logger.Errf("Failed to set the filter of exec arguments (%s)", err.Error())
+	}
+
+	dm.SystemMonitor.LogWorkers = dm.Config.LogWorkers
+	dm.SystemMonitor.LogWorkerQueueSize = dm.Config.LogWorkerQueueSize
+	dm.SystemMonitor.LogBackpressure = dm.Config.LogBackpressure
+
+	// learn the behavior of containers
+	dm.SystemMonitor.Discovery = dm.Discovery
+
 	if err := dm.SystemMonitor.InitBPF(); err != nil {
 		kg.Err(err.Error())
 		return false
 	}
 
+	// apply the host visibility in the kernel
+	dm.UpdateHostVisibility()
+
 	return true
 }
 
@@ -236,18 +388,20 @@ func (dm *KubeArmorDaemon) MonitorSystemEvents() {
 	dm.WgDaemon.Add(1)
 	defer dm.WgDaemon.Done()
 
+	// start workers before tracing syscalls
+	dm.SystemMonitor.StartLogPipelines()
+
 	if dm.EnableKubeArmorPolicy {
 		go dm.SystemMonitor.TraceSyscall()
-		go dm.SystemMonitor.UpdateLogs()
 	}
 
 	if dm.EnableKubeArmorHostPolicy {
 		go dm.SystemMonitor.TraceHostSyscall()
-		go dm.SystemMonitor.UpdateHostLogs()
 	}
 
 	if dm.EnableKubeArmorPolicy || dm.EnableKubeArmorHostPolicy {
 		go dm.SystemMonitor.CleanUpExitedHostPids()
+		go dm.SystemMonitor.ReportLostEvents()
 	}
 }
 
@@ -266,8 +420,22 @@ func (dm *KubeArmorDaemon) CloseSystemMonitor() bool {
 
 // InitRuntimeEnforcer Function
 func (dm *KubeArmorDaemon) InitRuntimeEnforcer() bool {
-	dm.RuntimeEnforcer = efc.NewRuntimeEnforcer(dm.Node, dm.Logger)
-	return dm.RuntimeEnforcer != nil
+	runtimeEnforcer := efc.NewRuntimeEnforcer(dm.GetNode(), dm.Logger)
+
+	// re-evaluate the node policy with the enforcer in use
+	dm.NodeLock.Lock()
+	dm.RuntimeEnforcer = runtimeEnforcer
+	dm.setNodePolicyEnabled(&dm.Node)
+	dm.NodeLock.Unlock()
+
+	if dm.RuntimeEnforcer == nil {
+		return false
+	}
+
+	// audit the rules that the enforcer cannot enforce
+	dm.Logger.IsEnforceable = dm.RuntimeEnforcer.GetRuleChecker()
+
+	return true
 }
 
 // CloseRuntimeEnforcer Function
@@ -302,9 +470,17 @@ func GetOSSigChannel() chan os.Signal {
 // ========== //
 
 // KubeArmor Function
-func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, enableKubeArmorHostPolicy bool) {
+func KubeArmor(config cfg.KubeArmorConfig) {
 	// create a daemon
-	dm := NewKubeArmorDaemon(clusterName, gRPCPort, logPath, enableKubeArmorPolicy, enableKubeArmorHostPolicy)
+	dm := NewKubeArmorDaemon(config)
+
+	// == //
+
+	// load the config file and the ConfigMap
+	if !dm.InitConfig() {
+		kg.Err("Failed to load the configuration")
+		return
+	}
 
 	// == //
 
@@ -324,15 +500,18 @@ func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, ena
 		// wait for a while
 		time.Sleep(time.Second * 1)
 
-		for dm.Node.NodeIP == "" {
+		for dm.GetNode().NodeIP == "" {
 			kg.Print("The node information is not updated yet")
 
 			// wait for a while
 			time.Sleep(time.Second * 1)
 		}
 
-		dm.Node.EnableKubeArmorPolicy = enableKubeArmorPolicy
-		dm.Node.EnableKubeArmorHostPolicy = enableKubeArmorHostPolicy
+		dm.NodeLock.Lock()
+		dm.Node.EnableKubeArmorPolicy = dm.EnableKubeArmorPolicy
+		dm.Node.EnableKubeArmorHostPolicy = dm.EnableKubeArmorHostPolicy
+		dm.Node.EnableSeccompEnforcer = dm.EnableSeccompEnforcer
+		dm.NodeLock.Unlock()
 	} else {
 		dm.Node.NodeName = kl.GetHostName()
 		dm.Node.NodeIP = kl.GetExternalIPAddr()
@@ -343,9 +522,13 @@ func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, ena
 		dm.EnableKubeArmorPolicy = false
 
 		dm.Node.EnableKubeArmorPolicy = false
-		dm.Node.EnableKubeArmorHostPolicy = enableKubeArmorHostPolicy
+		dm.Node.EnableKubeArmorHostPolicy = dm.EnableKubeArmorHostPolicy
+		dm.Node.EnableSeccompEnforcer = dm.EnableSeccompEnforcer
+
+		dm.setNodePolicyEnabled(&dm.Node)
 
-		dm.Node.PolicyEnabled = tp.KubeArmorPolicyEnabled
+		// default host visibility
+		dm.setNodeVisibility(&dm.Node)
 
 		kg.Print("Detected no Kubernetes")
 	}
@@ -363,10 +546,23 @@ func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, ena
 	}
 	dm.Logger.Print("Initialized the logger")
 
+	// initialize the learning mode (before serving gRPC)
+	dm.InitDiscovery()
+
 	// serve log feeds
 	go dm.ServeLogFeeds()
 	dm.Logger.Print("Started to serve gRPC-based log feeds")
 
+	// serve metrics and health probes
+	if dm.Config.HTTP != "none" {
+		if dm.InitHTTPServer() {
+			go dm.ServeHTTP()
+			dm.Logger.Printf("Started to serve metrics and health probes (:%s)", dm.Config.HTTP)
+		} else {
+			dm.Logger.Err("Failed to initialize the HTTP server")
+		}
+	}
+
 	// == //
 
 	if dm.EnableKubeArmorPolicy || dm.EnableKubeArmorHostPolicy {
@@ -406,66 +602,19 @@ func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, ena
 	// == //
 
 	if dm.K8sEnabled && dm.EnableKubeArmorPolicy {
-		dm.Logger.Printf("Container Runtime: %s", dm.Node.ContainerRuntimeVersion)
-
-		if strings.HasPrefix(dm.Node.ContainerRuntimeVersion, "docker") {
-			sockFile := false
-
-			for _, candidate := range []string{"/var/run/docker.sock"} {
-				if _, err := os.Stat(candidate); err == nil {
-					sockFile = true
-					break
-				}
-			}
-
-			if sockFile {
-				// update already deployed containers
-				dm.GetAlreadyDeployedDockerContainers()
-
-				// monitor docker events
-				go dm.MonitorDockerEvents()
-			} else {
-				for _, candidate := range []string{"/var/run/containerd/containerd.sock"} {
-					if _, err := os.Stat(candidate); err == nil {
-						sockFile = true
-						break
-					}
-				}
-
-				if sockFile {
-					// monitor containerd events
-					go dm.MonitorContainerdEvents()
-				} else {
-					dm.Logger.Err("Failed to monitor containers (Docker socket file is not accessible)")
-
-					// destroy the daemon
-					dm.DestroyKubeArmorDaemon()
-
-					return
-				}
-			}
-		} else { // containerd
-			sockFile := false
-
-			for _, candidate := range []string{"/var/run/containerd/containerd.sock", "/var/snap/microk8s/common/run/containerd.sock"} {
-				if _, err := os.Stat(candidate); err == nil {
-					sockFile = true
-					break
-				}
-			}
+		dm.Logger.Printf("Container Runtime: %s", dm.GetNode().ContainerRuntimeVersion)
 
-			if sockFile {
-				// monitor containerd events
-				go dm.MonitorContainerdEvents()
-			} else {
-				dm.Logger.Err("Failed to monitor containers (Containerd socket file is not accessible)")
+		if err := dm.InitContainerRuntime(); err != nil {
+			dm.Logger.Errf("Failed to monitor containers (%s)", err.Error())
 
-				// destroy the daemon
-				dm.DestroyKubeArmorDaemon()
+			// destroy the daemon
+			dm.DestroyKubeArmorDaemon()
 
-				return
-			}
+			return
 		}
+
+		// monitor container events
+		go dm.MonitorContainerRuntime()
 	}
 
 	// == //
@@ -493,8 +642,28 @@ func KubeArmor(clusterName, gRPCPort, logPath string, enableKubeArmorPolicy, ena
 
 	// == //
 
+	if dm.Config.ConfigPath != "" {
+		// watch the config file
+		go dm.WatchConfigFile()
+		dm.Logger.Printf("Started to monitor the config file (%s)", dm.Config.ConfigPath)
+	}
+
+	if dm.K8sEnabled && dm.Config.ConfigMap != "" {
+		// watch the ConfigMap
+		go dm.WatchConfigMap()
+		dm.Logger.Printf("Started to monitor the ConfigMap (%s)", dm.Config.ConfigMap)
+	}
+
+	// write discovered policies into the output directory if set
+	go dm.WriteDiscoveredPolicies()
+
+	// == //
+
 	dm.Logger.Print("Initialized KubeArmor")
 
+	// ready to serve
+	dm.SetReady()
+
 	// == //
 
 	// listen for interrupt signals
@@ -5,15 +5,16 @@ package core
 
 import (
 	"encoding/json"
-	"io"
-	"io/ioutil"
 	"sort"
 	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	efc "github.com/kubearmor/KubeArmor/KubeArmor/enforcer"
+	pl "github.com/kubearmor/KubeArmor/KubeArmor/policy"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 )
 
@@ -21,121 +22,126 @@ import (
 // == Node Update == //
 // ================= //
 
-// WatchK8sNodes Function
-func (dm *KubeArmorDaemon) WatchK8sNodes() {
-	nodeName := kl.GetHostName()
+// GetNode Function
+func (dm *KubeArmorDaemon) GetNode() tp.Node {
+	dm.NodeLock.RLock()
+	defer dm.NodeLock.RUnlock()
 
-	for {
-		if resp := K8s.WatchK8sNodes(); resp != nil {
-			defer resp.Body.Close()
+	return dm.Node
+}
 
-			decoder := json.NewDecoder(resp.Body)
-			for {
-				event := tp.K8sNodeEvent{}
-				if err := decoder.Decode(&event); err == io.EOF {
-					break
-				} else if err != nil {
-					break
-				}
+// setNodePolicyEnabled Function (NodeLock should be held)
+func (dm *KubeArmorDaemon) setNodePolicyEnabled(node *tp.Node) {
+	policy, ok := node.Annotations["kubearmor-policy"]
+	if !ok {
+		policy = "enabled"
+	}
 
-				if event.Object.ObjectMeta.Name != nodeName {
-					continue
-				}
+	if policy == "enabled" && (dm.RuntimeEnforcer == nil || !dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapHostPolicy)) {
+		// exception: no enforcer for host policies
+		policy = "audited"
+	}
 
-				node := tp.Node{}
-				node.NodeName = nodeName
+	if policy == "enabled" {
+		node.PolicyEnabled = tp.KubeArmorPolicyEnabled
+	} else if policy == "audited" || policy == "patched" {
+		node.PolicyEnabled = tp.KubeArmorPolicyAudited
+	} else {
+		node.PolicyEnabled = tp.KubeArmorPolicyDisabled
+	}
+}
 
-				for _, address := range event.Object.Status.Addresses {
-					if address.Type == "InternalIP" {
-						node.NodeIP = address.Address
-						break
-					}
-				}
+// UpdateK8sNode Function
+func (dm *KubeArmorDaemon) UpdateK8sNode(event tp.K8sNodeEvent) {
+	nodeName := kl.GetHostName()
 
-				node.Annotations = map[string]string{}
-				node.Labels = map[string]string{}
-				node.Identities = []string{}
+	if event.Object.ObjectMeta.Name != nodeName {
+		return
+	}
 
-				// update annotations
-				for k, v := range event.Object.ObjectMeta.Annotations {
-					node.Annotations[k] = v
-				}
+	node := tp.Node{}
+	node.NodeName = nodeName
 
-				// update labels and identities
-				for k, v := range event.Object.ObjectMeta.Labels {
-					node.Labels[k] = v
-					node.Identities = append(node.Identities, k+"="+v)
-				}
+	for _, address := range event.Object.Status.Addresses {
+		if address.Type == "InternalIP" {
+			node.NodeIP = address.Address
+			break
+		}
+	}
 
-				sort.Slice(node.Identities, func(i, j int) bool {
-					return node.Identities[i] < node.Identities[j]
-				})
+	node.Annotations = map[string]string{}
+	node.Labels = map[string]string{}
+	node.Identities = []string{}
 
-				// node info
-				node.Architecture = event.Object.Status.NodeInfo.Architecture
-				node.OperatingSystem = event.Object.Status.NodeInfo.OperatingSystem
-				node.OSImage = event.Object.Status.NodeInfo.OSImage
-				node.KernelVersion = event.Object.Status.NodeInfo.KernelVersion
-				node.KubeletVersion = event.Object.Status.NodeInfo.KubeletVersion
+	// update annotations
+	for k, v := range event.Object.ObjectMeta.Annotations {
+		node.Annotations[k] = v
+	}
 
-				// container runtime
-				node.ContainerRuntimeVersion = event.Object.Status.NodeInfo.ContainerRuntimeVersion
+	// update labels and identities
+	for k, v := range event.Object.ObjectMeta.Labels {
+		node.Labels[k] = v
+		node.Identities = append(node.Identities, k+"="+v)
+	}
 
-				// policy options
-				node.EnableKubeArmorPolicy = dm.Node.EnableKubeArmorPolicy
-				node.EnableKubeArmorHostPolicy = dm.Node.EnableKubeArmorHostPolicy
+	sort.Slice(node.Identities, func(i, j int) bool {
+		return node.Identities[i] < node.Identities[j]
+	})
 
-				// == //
+	// node info
+	node.Architecture = event.Object.Status.NodeInfo.Architecture
+	node.OperatingSystem = event.Object.Status.NodeInfo.OperatingSystem
+	node.OSImage = event.Object.Status.NodeInfo.OSImage
+	node.KernelVersion = event.Object.Status.NodeInfo.KernelVersion
+	node.KubeletVersion = event.Object.Status.NodeInfo.KubeletVersion
 
-				if _, ok := node.Annotations["kubearmor-policy"]; ok {
-					if node.Annotations["kubearmor-policy"] != "enabled" && node.Annotations["kubearmor-policy"] != "disabled" && node.Annotations["kubearmor-policy"] != "audited" {
-						node.Annotations["kubearmor-policy"] = "enabled"
-					}
-				} else {
-					node.Annotations["kubearmor-policy"] = "enabled"
-				}
+	// container runtime
+	node.ContainerRuntimeVersion = event.Object.Status.NodeInfo.ContainerRuntimeVersion
 
-				if lsm, err := ioutil.ReadFile("/sys/kernel/security/lsm"); err == nil && !strings.Contains(string(lsm), "apparmor") {
-					// exception: no AppArmor
-					if node.Annotations["kubearmor-policy"] == "enabled" {
-						node.Annotations["kubearmor-policy"] = "audited"
-					}
-				}
+	// policy options
+	prev := dm.GetNode()
 
-				if node.Annotations["kubearmor-policy"] == "enabled" {
-					node.PolicyEnabled = tp.KubeArmorPolicyEnabled
-				} else if node.Annotations["kubearmor-policy"] == "audited" || node.Annotations["kubearmor-policy"] == "patched" {
-					node.PolicyEnabled = tp.KubeArmorPolicyAudited
-				} else {
-					node.PolicyEnabled = tp.KubeArmorPolicyDisabled
-				}
+	node.EnableKubeArmorPolicy = prev.EnableKubeArmorPolicy
+	node.EnableKubeArmorHostPolicy = prev.EnableKubeArmorHostPolicy
+	node.EnableSeccompEnforcer = prev.EnableSeccompEnforcer
 
-				// == //
+	// == //
 
-				if _, ok := node.Annotations["kubearmor-visibility"]; !ok {
-					node.Annotations["kubearmor-visibility"] = "none"
-				}
+	if _, ok := node.Annotations["kubearmor-policy"]; ok {
+		if node.Annotations["kubearmor-policy"] != "enabled" && node.Annotations["kubearmor-policy"] != "disabled" && node.Annotations["kubearmor-policy"] != "audited" {
+			node.Annotations["kubearmor-policy"] = "enabled"
+		}
+	} else {
+		node.Annotations["kubearmor-policy"] = "enabled"
+	}
 
-				for _, visibility := range strings.Split(node.Annotations["kubearmor-visibility"], ",") {
-					if visibility == "process" {
-						node.ProcessVisibilityEnabled = true
-					} else if visibility == "file" {
-						node.FileVisibilityEnabled = true
-					} else if visibility == "network" {
-						node.NetworkVisibilityEnabled = true
-					} else if visibility == "capabilities" {
-						node.CapabilitiesVisibilityEnabled = true
-					}
-				}
+	// == //
 
-				// == //
+	// use the default host visibility if not annotated
+	dm.setNodeVisibility(&node)
 
-				dm.Node = node
-			}
-		} else {
-			time.Sleep(time.Second * 1)
+	// == //
+
+	dm.NodeLock.Lock()
+	dm.setNodePolicyEnabled(&node)
+	dm.Node = node
+	dm.NodeLock.Unlock()
+
+	// update the host visibility in the kernel
+	dm.UpdateHostVisibility()
+}
+
+// WatchK8sNodes Function
+func (dm *KubeArmorDaemon) WatchK8sNodes() {
+	dm.RegisterK8sWatcher("nodes")
+
+	informer := dm.NewK8sInformer("nodes", K8s.NewNodeListWatch(kl.GetHostName()), &v1.Node{}, func(eventType string, obj interface{}) {
+		if node, ok := obj.(*v1.Node); ok {
+			dm.UpdateK8sNode(tp.K8sNodeEvent{Type: eventType, Object: *node})
 		}
-	}
+	})
+
+	informer.Run(StopChan)
 }
 
 // ================ //
@@ -177,7 +183,7 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 		}
 
 		// parse annotations and update visibility flags
-		for _, visibility := range strings.Split(pod.Annotations["kubearmor-visibility"], ",") {
+		for _, visibility := range strings.Split(dm.getPodVisibility(pod), ",") {
 			if visibility == "process" {
 				newPoint.ProcessVisibilityEnabled = true
 			} else if visibility == "file" {
@@ -235,6 +241,15 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 		// update host-side volume mounted
 		newPoint.HostVolumes = append(newPoint.HostVolumes, pod.HostVolumes...)
 
+		newPoint.SeccompProfiles = []string{}
+
+		// update seccomp profile names to the endpoint
+		for _, v := range pod.SeccompProfiles {
+			if !kl.ContainsElement(newPoint.SeccompProfiles, v) {
+				newPoint.SeccompProfiles = append(newPoint.SeccompProfiles, v)
+			}
+		}
+
 		// update security policies with the identities
 		newPoint.SecurityPolicies = dm.GetSecurityPolicies(newPoint.Identities)
 
@@ -251,6 +266,9 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 		// enforce security policies
 		dm.RuntimeEnforcer.UpdateSecurityPolicies(newPoint)
 
+		// filter invisible events in the kernel
+		dm.UpdateVisibilityMap(newPoint)
+
 	} else if action == "MODIFIED" {
 		for idx, endPoint := range dm.EndPoints {
 			if pod.Metadata["namespaceName"] == endPoint.NamespaceName && pod.Metadata["podName"] == endPoint.EndPointName {
@@ -282,7 +300,7 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 				dm.EndPoints[idx].CapabilitiesVisibilityEnabled = false
 
 				// parse annotations and update visibility flags
-				for _, visibility := range strings.Split(pod.Annotations["kubearmor-visibility"], ",") {
+				for _, visibility := range strings.Split(dm.getPodVisibility(pod), ",") {
 					if visibility == "process" {
 						dm.EndPoints[idx].ProcessVisibilityEnabled = true
 					} else if visibility == "file" {
@@ -340,6 +358,15 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 				// update host-side volume mounted
 				dm.EndPoints[idx].HostVolumes = append(dm.EndPoints[idx].HostVolumes, pod.HostVolumes...)
 
+				dm.EndPoints[idx].SeccompProfiles = []string{}
+
+				// update seccomp profile names to the endpoint
+				for _, v := range pod.SeccompProfiles {
+					if !kl.ContainsElement(dm.EndPoints[idx].SeccompProfiles, v) {
+						dm.EndPoints[idx].SeccompProfiles = append(dm.EndPoints[idx].SeccompProfiles, v)
+					}
+				}
+
 				// get security policies according to the updated identities
 				dm.EndPoints[idx].SecurityPolicies = dm.GetSecurityPolicies(dm.EndPoints[idx].Identities)
 
@@ -351,6 +378,9 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 				// enforce security policies
 				dm.RuntimeEnforcer.UpdateSecurityPolicies(dm.EndPoints[idx])
 
+				// filter invisible events in the kernel
+				dm.UpdateVisibilityMap(dm.EndPoints[idx])
+
 				break
 			}
 		}
@@ -367,371 +397,444 @@ func (dm *KubeArmorDaemon) UpdateEndPointWithPod(action string, pod tp.K8sPod) {
 	}
 }
 
-// WatchK8sPods Function
-func (dm *KubeArmorDaemon) WatchK8sPods() {
-	for {
-		if resp := K8s.WatchK8sPods(); resp != nil {
-			defer resp.Body.Close()
-
-			decoder := json.NewDecoder(resp.Body)
-			for {
-				event := tp.K8sPodEvent{}
-				if err := decoder.Decode(&event); err == io.EOF {
-					break
-				} else if err != nil {
-					break
-				}
+// getSeccompProfile Function
+func getSeccompProfile(profile *v1.SeccompProfile) string {
+	if profile.Type == v1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return "localhost/" + *profile.LocalhostProfile
+	} else if profile.Type == v1.SeccompProfileTypeRuntimeDefault {
+		return "runtime/default"
+	}
 
-				// create a pod
+	return "unconfined"
+}
 
-				pod := tp.K8sPod{}
+// UpdateK8sPod Function
+func (dm *KubeArmorDaemon) UpdateK8sPod(event tp.K8sPodEvent) {
+	// create a pod
 
-				pod.Metadata = map[string]string{}
-				pod.Metadata["namespaceName"] = event.Object.ObjectMeta.Namespace
-				pod.Metadata["podName"] = event.Object.ObjectMeta.Name
+	pod := tp.K8sPod{}
 
-				if len(event.Object.ObjectMeta.OwnerReferences) > 0 {
-					if event.Object.ObjectMeta.OwnerReferences[0].Kind == "ReplicaSet" {
-						deploymentName := K8s.GetDeploymentNameControllingReplicaSet(pod.Metadata["namespaceName"], event.Object.ObjectMeta.OwnerReferences[0].Name)
-						if deploymentName != "" {
-							pod.Metadata["deploymentName"] = deploymentName
-						}
-					}
-				}
+	pod.Metadata = map[string]string{}
+	pod.Metadata["namespaceName"] = event.Object.ObjectMeta.Namespace
+	pod.Metadata["podName"] = event.Object.ObjectMeta.Name
 
-				pod.Annotations = map[string]string{}
-				for k, v := range event.Object.Annotations {
-					pod.Annotations[k] = v
-				}
+	if len(event.Object.ObjectMeta.OwnerReferences) > 0 {
+		if event.Object.ObjectMeta.OwnerReferences[0].Kind == "ReplicaSet" {
+			deploymentName := K8s.GetDeploymentNameControllingReplicaSet(pod.Metadata["namespaceName"], event.Object.ObjectMeta.OwnerReferences[0].Name)
+			if deploymentName != "" {
+				pod.Metadata["deploymentName"] = deploymentName
+			}
+		}
+	}
 
-				pod.Labels = map[string]string{}
-				for k, v := range event.Object.Labels {
-					if k == "pod-template-hash" {
-						continue
-					}
+	pod.Annotations = map[string]string{}
+	for k, v := range event.Object.Annotations {
+		pod.Annotations[k] = v
+	}
 
-					if k == "pod-template-generation" {
-						continue
-					}
+	pod.Labels = map[string]string{}
+	for k, v := range event.Object.Labels {
+		if k == "pod-template-hash" {
+			continue
+		}
 
-					if k == "controller-revision-hash" {
-						continue
-					}
-					pod.Labels[k] = v
-				}
+		if k == "pod-template-generation" {
+			continue
+		}
 
-				pod.Containers = map[string]string{}
-				for _, container := range event.Object.Status.ContainerStatuses {
-					if len(container.ContainerID) > 0 {
-						if strings.HasPrefix(container.ContainerID, "docker://") {
-							containerID := strings.TrimPrefix(container.ContainerID, "docker://")
-							pod.Containers[containerID] = container.Name
-						} else if strings.HasPrefix(container.ContainerID, "containerd://") {
-							containerID := strings.TrimPrefix(container.ContainerID, "containerd://")
-							pod.Containers[containerID] = container.Name
-						}
-					}
-				}
+		if k == "controller-revision-hash" {
+			continue
+		}
+		pod.Labels[k] = v
+	}
 
-				// == Policy == //
+	pod.Containers = map[string]string{}
+	for _, container := range event.Object.Status.ContainerStatuses {
+		if len(container.ContainerID) > 0 {
+			if strings.HasPrefix(container.ContainerID, "docker://") {
+				containerID := strings.TrimPrefix(container.ContainerID, "docker://")
+				pod.Containers[containerID] = container.Name
+			} else if strings.HasPrefix(container.ContainerID, "containerd://") {
+				containerID := strings.TrimPrefix(container.ContainerID, "containerd://")
+				pod.Containers[containerID] = container.Name
+			}
+		}
+	}
 
-				if _, ok := pod.Annotations["kubearmor-policy"]; ok {
-					if pod.Annotations["kubearmor-policy"] != "enabled" && pod.Annotations["kubearmor-policy"] != "disabled" && pod.Annotations["kubearmor-policy"] != "audited" {
-						pod.Annotations["kubearmor-policy"] = "enabled"
-					}
-				} else {
-					pod.Annotations["kubearmor-policy"] = "enabled"
-				}
+	// == Policy == //
 
-				// == LSM == //
+	if _, ok := pod.Annotations["kubearmor-policy"]; ok {
+		if pod.Annotations["kubearmor-policy"] != "enabled" && pod.Annotations["kubearmor-policy"] != "disabled" && pod.Annotations["kubearmor-policy"] != "audited" {
+			pod.Annotations["kubearmor-policy"] = "enabled"
+		}
+	} else {
+		pod.Annotations["kubearmor-policy"] = "enabled"
+	}
 
-				if dm.RuntimeEnforcer == nil {
-					// exception: no LSM
-					if pod.Annotations["kubearmor-policy"] == "enabled" {
-						pod.Annotations["kubearmor-policy"] = "audited"
-					}
-				} else if lsm, err := ioutil.ReadFile("/sys/kernel/security/lsm"); err == nil && !strings.Contains(string(lsm), "apparmor") {
-					// exception: no AppArmor
-					if pod.Annotations["kubearmor-policy"] == "enabled" {
-						pod.Annotations["kubearmor-policy"] = "audited"
-					}
-				}
+	// == LSM == //
 
-				// == Exception == //
+	if dm.RuntimeEnforcer == nil {
+		// exception: no LSM
+		if pod.Annotations["kubearmor-policy"] == "enabled" {
+			pod.Annotations["kubearmor-policy"] = "audited"
+		}
+	} else if !dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapContainerPolicy) {
+		// exception: no enforcement in containers (e.g., SELinux)
+		if pod.Annotations["kubearmor-policy"] == "enabled" {
+			pod.Annotations["kubearmor-policy"] = "audited"
+		}
+	}
 
-				if pod.Metadata["namespaceName"] == "kube-system" {
-					// exception: kubernetes app
-					if _, ok := pod.Labels["k8s-app"]; ok {
-						pod.Annotations["kubearmor-policy"] = "audited"
-					}
+	// == Exception == //
 
-					// exception: cilium-operator
-					if val, ok := pod.Labels["io.cilium/app"]; ok && val == "operator" {
-						pod.Annotations["kubearmor-policy"] = "audited"
-					}
-				}
+	if pod.Metadata["namespaceName"] == "kube-system" {
+		// exception: kubernetes app
+		if _, ok := pod.Labels["k8s-app"]; ok {
+			pod.Annotations["kubearmor-policy"] = "audited"
+		}
 
-				// == Visibility == //
+		// exception: cilium-operator
+		if val, ok := pod.Labels["io.cilium/app"]; ok && val == "operator" {
+			pod.Annotations["kubearmor-policy"] = "audited"
+		}
+	}
 
-				if _, ok := pod.Annotations["kubearmor-visibility"]; !ok {
-					pod.Annotations["kubearmor-visibility"] = "none"
-				}
+	// == Visibility == //
 
-				if event.Type == "ADDED" || event.Type == "MODIFIED" {
-					exist := false
+	// the default visibility is used in UpdateEndPointWithPod if not annotated
 
-					dm.K8sPodsLock.Lock()
-					for _, k8spod := range dm.K8sPods {
-						if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-							if k8spod.Annotations["kubearmor-policy"] == "patched" {
-								exist = true
-								break
-							}
-						}
-					}
-					dm.K8sPodsLock.Unlock()
+	if event.Type == "ADDED" || event.Type == "MODIFIED" {
+		exist := false
 
-					if exist {
-						continue
-					}
+		dm.K8sPodsLock.Lock()
+		for _, k8spod := range dm.K8sPods {
+			if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+				if k8spod.Annotations["kubearmor-policy"] == "patched" {
+					exist = true
+					break
 				}
+			}
+		}
+		dm.K8sPodsLock.Unlock()
 
-				// == AppArmor == //
+		if exist {
+			return
+		}
+	}
 
-				if dm.RuntimeEnforcer != nil && dm.RuntimeEnforcer.EnforcerType == "AppArmor" {
-					appArmorAnnotations := map[string]string{}
-					updateAppArmor := false
+	// == AppArmor == //
 
-					for k, v := range pod.Annotations {
-						if strings.HasPrefix(k, "container.apparmor.security.beta.kubernetes.io") {
-							if v == "unconfined" {
-								containerName := strings.Split(k, "/")[1]
-								appArmorAnnotations[containerName] = v
-							} else {
-								containerName := strings.Split(k, "/")[1]
-								appArmorAnnotations[containerName] = strings.Split(v, "/")[1]
-							}
-						}
+	if dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapAppArmorProfiles) {
+		appArmorAnnotations := map[string]string{}
+		updateAppArmor := false
+
+		for k, v := range pod.Annotations {
+			if strings.HasPrefix(k, "container.apparmor.security.beta.kubernetes.io") {
+				if v == "unconfined" {
+					containerName := strings.Split(k, "/")[1]
+					appArmorAnnotations[containerName] = v
+				} else {
+					containerName := strings.Split(k, "/")[1]
+					appArmorAnnotations[containerName] = strings.Split(v, "/")[1]
+				}
+			}
+		}
+
+		for _, container := range event.Object.Spec.Containers {
+			if _, ok := appArmorAnnotations[container.Name]; !ok {
+				appArmorAnnotations[container.Name] = "kubearmor-" + pod.Metadata["namespaceName"] + "-" + container.Name
+				updateAppArmor = true
+			}
+		}
+
+		if event.Type == "ADDED" {
+			// update apparmor profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("ADDED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], AppArmorProfiles: appArmorAnnotations})
+
+			if updateAppArmor && pod.Annotations["kubearmor-policy"] == "enabled" {
+				if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+					// patch the deployment with apparmor annotations
+					if err := K8s.PatchDeploymentWithAppArmorAnnotations(pod.Metadata["namespaceName"], deploymentName, appArmorAnnotations); err != nil {
+						dm.Logger.Errf("Failed to update AppArmor Annotations (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+					} else {
+						dm.Logger.Printf("Patched AppArmor Annotations (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
 					}
+					pod.Annotations["kubearmor-policy"] = "patched"
+				}
+			}
+		} else if event.Type == "MODIFIED" {
+			for _, k8spod := range dm.K8sPods {
+				if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+					prevPolicyEnabled := "disabled"
 
-					for _, container := range event.Object.Spec.Containers {
-						if _, ok := appArmorAnnotations[container.Name]; !ok {
-							appArmorAnnotations[container.Name] = "kubearmor-" + pod.Metadata["namespaceName"] + "-" + container.Name
-							updateAppArmor = true
-						}
+					if val, ok := k8spod.Annotations["kubearmor-policy"]; ok {
+						prevPolicyEnabled = val
 					}
 
-					if event.Type == "ADDED" {
-						// update apparmor profiles
-						dm.RuntimeEnforcer.UpdateAppArmorProfiles("ADDED", appArmorAnnotations)
-
-						if updateAppArmor && pod.Annotations["kubearmor-policy"] == "enabled" {
-							if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
-								// patch the deployment with apparmor annotations
-								if err := K8s.PatchDeploymentWithAppArmorAnnotations(pod.Metadata["namespaceName"], deploymentName, appArmorAnnotations); err != nil {
-									dm.Logger.Errf("Failed to update AppArmor Annotations (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
-								} else {
-									dm.Logger.Printf("Patched AppArmor Annotations (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
-								}
-								pod.Annotations["kubearmor-policy"] = "patched"
-							}
-						}
-					} else if event.Type == "MODIFIED" {
-						for _, k8spod := range dm.K8sPods {
-							if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-								prevPolicyEnabled := "disabled"
-
-								if val, ok := k8spod.Annotations["kubearmor-policy"]; ok {
-									prevPolicyEnabled = val
-								}
-
-								if updateAppArmor && prevPolicyEnabled != "enabled" && pod.Annotations["kubearmor-policy"] == "enabled" {
-									if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
-										// patch the deployment with apparmor annotations
-										if err := K8s.PatchDeploymentWithAppArmorAnnotations(pod.Metadata["namespaceName"], deploymentName, appArmorAnnotations); err != nil {
-											dm.Logger.Errf("Failed to update AppArmor Annotations (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
-										} else {
-											dm.Logger.Printf("Patched AppArmor Annotations (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
-										}
-										pod.Annotations["kubearmor-policy"] = "patched"
-									}
-								}
-
-								break
+					if updateAppArmor && prevPolicyEnabled != "enabled" && pod.Annotations["kubearmor-policy"] == "enabled" {
+						if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+							// patch the deployment with apparmor annotations
+							if err := K8s.PatchDeploymentWithAppArmorAnnotations(pod.Metadata["namespaceName"], deploymentName, appArmorAnnotations); err != nil {
+								dm.Logger.Errf("Failed to update AppArmor Annotations (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+							} else {
+								dm.Logger.Printf("Patched AppArmor Annotations (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
 							}
+							pod.Annotations["kubearmor-policy"] = "patched"
 						}
-					} else if event.Type == "DELETED" {
-						// update apparmor profiles
-						dm.RuntimeEnforcer.UpdateAppArmorProfiles("DELETED", appArmorAnnotations)
 					}
+
+					break
 				}
+			}
+		} else if event.Type == "DELETED" {
+			// update apparmor profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("DELETED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], AppArmorProfiles: appArmorAnnotations})
+		}
+	}
 
-				// == SELinux == //
+	// == SELinux == //
 
-				if dm.RuntimeEnforcer != nil && dm.RuntimeEnforcer.EnforcerType == "SELinux" {
-					pod.HostVolumes = []tp.HostVolumeMount{}
+	if dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapSELinuxContexts) {
+		pod.HostVolumes = []tp.HostVolumeMount{}
 
-					for _, v := range event.Object.Spec.Volumes {
-						if v.HostPath != nil {
-							hostVolume := tp.HostVolumeMount{}
+		for _, v := range event.Object.Spec.Volumes {
+			if v.HostPath != nil {
+				hostVolume := tp.HostVolumeMount{}
 
-							hostVolume.Type = string(*v.HostPath.Type)
-							hostVolume.VolumeName = v.Name
-							hostVolume.PathName = v.HostPath.Path
+				hostVolume.Type = string(*v.HostPath.Type)
+				hostVolume.VolumeName = v.Name
+				hostVolume.PathName = v.HostPath.Path
 
-							hostVolume.UsedByContainerPath = map[string]string{}
-							hostVolume.UsedByContainerReadOnly = map[string]bool{}
+				hostVolume.UsedByContainerPath = map[string]string{}
+				hostVolume.UsedByContainerReadOnly = map[string]bool{}
+
+				pod.HostVolumes = append(pod.HostVolumes, hostVolume)
+			}
+		}
 
-							pod.HostVolumes = append(pod.HostVolumes, hostVolume)
+		for _, container := range event.Object.Spec.Containers {
+			for _, containerVolume := range container.VolumeMounts {
+				for i, hostVoulme := range pod.HostVolumes {
+					if containerVolume.Name == hostVoulme.VolumeName {
+						if _, ok := pod.HostVolumes[i].UsedByContainerReadOnly[container.Name]; !ok {
+							pod.HostVolumes[i].UsedByContainerPath[container.Name] = containerVolume.MountPath
+							pod.HostVolumes[i].UsedByContainerReadOnly[container.Name] = containerVolume.ReadOnly
 						}
 					}
+				}
+			}
 
-					for _, container := range event.Object.Spec.Containers {
-						for _, containerVolume := range container.VolumeMounts {
-							for i, hostVoulme := range pod.HostVolumes {
-								if containerVolume.Name == hostVoulme.VolumeName {
-									if _, ok := pod.HostVolumes[i].UsedByContainerReadOnly[container.Name]; !ok {
-										pod.HostVolumes[i].UsedByContainerPath[container.Name] = containerVolume.MountPath
-										pod.HostVolumes[i].UsedByContainerReadOnly[container.Name] = containerVolume.ReadOnly
-									}
-								}
-							}
-						}
+			if container.SecurityContext != nil && container.SecurityContext.SELinuxOptions != nil {
+				if strings.Contains(container.SecurityContext.SELinuxOptions.Type, ".process") {
+					if _, ok := pod.Annotations["selinux-"+container.Name]; !ok {
+						selinuxContext := strings.Split(container.SecurityContext.SELinuxOptions.Type, ".process")[0]
+						pod.Annotations["selinux-"+container.Name] = selinuxContext
+					}
+				}
+			}
+		}
 
-						if container.SecurityContext != nil && container.SecurityContext.SELinuxOptions != nil {
-							if strings.Contains(container.SecurityContext.SELinuxOptions.Type, ".process") {
-								if _, ok := pod.Annotations["selinux-"+container.Name]; !ok {
-									selinuxContext := strings.Split(container.SecurityContext.SELinuxOptions.Type, ".process")[0]
-									pod.Annotations["selinux-"+container.Name] = selinuxContext
-								}
-							}
-						}
+		seLinuxContexts := map[string]string{}
+		updateSELinux := false
+
+		for _, container := range event.Object.Spec.Containers {
+			if container.SecurityContext == nil || container.SecurityContext.SELinuxOptions == nil || container.SecurityContext.SELinuxOptions.Type == "" {
+				if _, ok1 := seLinuxContexts[container.Name]; !ok1 {
+					if _, ok2 := pod.Metadata["deploymentName"]; !ok2 {
+						continue
 					}
 
-					seLinuxContexts := map[string]string{}
-					updateSELinux := false
+					container.SecurityContext = &v1.SecurityContext{
+						SELinuxOptions: &v1.SELinuxOptions{
+							Type: "kubearmor-" + pod.Metadata["namespaceName"] + "-" + pod.Metadata["deploymentName"] + "-" + container.Name + ".process",
+						},
+					}
 
-					for _, container := range event.Object.Spec.Containers {
-						if container.SecurityContext == nil || container.SecurityContext.SELinuxOptions == nil || container.SecurityContext.SELinuxOptions.Type == "" {
-							if _, ok1 := seLinuxContexts[container.Name]; !ok1 {
-								if _, ok2 := pod.Metadata["deploymentName"]; !ok2 {
-									continue
-								}
+					// clear container volume, if not delete volumeMounts, rolling update error
+					container.VolumeMounts = []v1.VolumeMount{}
 
-								container.SecurityContext = &v1.SecurityContext{
-									SELinuxOptions: &v1.SELinuxOptions{
-										Type: "kubearmor-" + pod.Metadata["namespaceName"] + "-" + pod.Metadata["deploymentName"] + "-" + container.Name + ".process",
-									},
-								}
+					b, _ := json.Marshal(container)
+					seLinuxContexts[container.Name] = string(b)
 
-								// clear container volume, if not delete volumeMounts, rolling update error
-								container.VolumeMounts = []v1.VolumeMount{}
+					// set update flag
+					updateSELinux = true
+				}
+			}
+		}
 
-								b, _ := json.Marshal(container)
-								seLinuxContexts[container.Name] = string(b)
+		if event.Type == "ADDED" {
+			// update selinux profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("ADDED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], SELinuxProfiles: pod.Annotations, HostVolumes: pod.HostVolumes})
+
+			if updateSELinux && pod.Annotations["kubearmor-policy"] == "enabled" {
+				if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+					// patch the deployment with selinux labels
+					if err := K8s.PatchDeploymentWithSELinuxOptions(pod.Metadata["namespaceName"], deploymentName, seLinuxContexts); err != nil {
+						dm.Logger.Errf("Failed to update SELinux security options (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+					} else {
+						dm.Logger.Printf("Patched SELinux security options (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
+					}
+					pod.Annotations["kubearmor-policy"] = "patched"
+				}
+			}
+		} else if event.Type == "MODIFIED" {
+			for _, k8spod := range dm.K8sPods {
+				if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+					prevPolicyEnabled := "disabled"
 
-								// set update flag
-								updateSELinux = true
-							}
-						}
+					if val, ok := k8spod.Annotations["kubearmor-policy"]; ok {
+						prevPolicyEnabled = val
 					}
 
-					if event.Type == "ADDED" {
-						// update selinux profiles
-						dm.RuntimeEnforcer.UpdateSELinuxProfiles("ADDED", pod.Annotations, pod.HostVolumes)
-
-						if updateSELinux && pod.Annotations["kubearmor-policy"] == "enabled" {
-							if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
-								// patch the deployment with selinux labels
-								if err := K8s.PatchDeploymentWithSELinuxOptions(pod.Metadata["namespaceName"], deploymentName, seLinuxContexts); err != nil {
-									dm.Logger.Errf("Failed to update SELinux security options (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
-								} else {
-									dm.Logger.Printf("Patched SELinux security options (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
-								}
-								pod.Annotations["kubearmor-policy"] = "patched"
-							}
-						}
-					} else if event.Type == "MODIFIED" {
-						for _, k8spod := range dm.K8sPods {
-							if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-								prevPolicyEnabled := "disabled"
-
-								if val, ok := k8spod.Annotations["kubearmor-policy"]; ok {
-									prevPolicyEnabled = val
-								}
-
-								if updateSELinux && prevPolicyEnabled != "enabled" && pod.Annotations["kubearmor-policy"] == "enabled" {
-									if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
-										// patch the deployment with selinux labels
-										if err := K8s.PatchDeploymentWithSELinuxOptions(pod.Metadata["namespaceName"], deploymentName, seLinuxContexts); err != nil {
-											dm.Logger.Errf("Failed to update SELinux security options (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
-										} else {
-											dm.Logger.Printf("Patched SELinux security options (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
-										}
-										pod.Annotations["kubearmor-policy"] = "patched"
-									}
-								}
-
-								break
+					if updateSELinux && prevPolicyEnabled != "enabled" && pod.Annotations["kubearmor-policy"] == "enabled" {
+						if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+							// patch the deployment with selinux labels
+							if err := K8s.PatchDeploymentWithSELinuxOptions(pod.Metadata["namespaceName"], deploymentName, seLinuxContexts); err != nil {
+								dm.Logger.Errf("Failed to update SELinux security options (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+							} else {
+								dm.Logger.Printf("Patched SELinux security options (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
 							}
+							pod.Annotations["kubearmor-policy"] = "patched"
 						}
-					} else if event.Type == "DELETED" {
-						// update selinux profiles
-						dm.RuntimeEnforcer.UpdateSELinuxProfiles("DELETED", pod.Annotations, pod.HostVolumes)
 					}
+
+					break
 				}
+			}
+		} else if event.Type == "DELETED" {
+			// update selinux profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("DELETED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], SELinuxProfiles: pod.Annotations, HostVolumes: pod.HostVolumes})
+		}
+	}
 
-				// == //
+	// == Seccomp == //
+
+	if dm.RuntimeEnforcer.HasCapability(efc.EnforcerCapSeccompProfiles) {
+		seccompProfiles := map[string]string{}
+		newProfiles := map[string]string{}
+
+		for _, container := range event.Object.Spec.Containers {
+			if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+				// exception: the seccomp profile of the container
+				seccompProfiles[container.Name] = getSeccompProfile(container.SecurityContext.SeccompProfile)
+			} else if event.Object.Spec.SecurityContext != nil && event.Object.Spec.SecurityContext.SeccompProfile != nil {
+				// exception: the seccomp profile of the pod (not to weaken it, e.g., runtime/default)
+				continue
+			} else {
+				seccompProfiles[container.Name] = efc.GetSeccompProfileName(pod.Metadata["namespaceName"], container.Name)
+				newProfiles[container.Name] = seccompProfiles[container.Name]
+			}
+		}
 
-				dm.K8sPodsLock.Lock()
+		updateSeccomp := len(newProfiles) > 0
 
-				if event.Type == "ADDED" {
-					new := true
-					for _, k8spod := range dm.K8sPods {
-						if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-							new = false
-							break
-						}
-					}
-					if new {
-						dm.K8sPods = append(dm.K8sPods, pod)
+		pod.SeccompProfiles = seccompProfiles
+
+		if event.Type == "ADDED" {
+			// update seccomp profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("ADDED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], SeccompProfiles: seccompProfiles})
+
+			if updateSeccomp && pod.Annotations["kubearmor-policy"] == "enabled" {
+				if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+					// patch the deployment with seccomp profiles
+					if err := K8s.PatchDeploymentWithSeccompProfiles(pod.Metadata["namespaceName"], deploymentName, newProfiles); err != nil {
+						dm.Logger.Errf("Failed to update Seccomp Profiles (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+					} else {
+						dm.Logger.Printf("Patched Seccomp Profiles (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
 					}
-				} else if event.Type == "MODIFIED" {
-					for idx, k8spod := range dm.K8sPods {
-						if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-							dm.K8sPods[idx] = pod
-							break
-						}
+					pod.Annotations["kubearmor-policy"] = "patched"
+				}
+			}
+		} else if event.Type == "MODIFIED" {
+			for _, k8spod := range dm.K8sPods {
+				if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+					prevPolicyEnabled := "disabled"
+
+					if val, ok := k8spod.Annotations["kubearmor-policy"]; ok {
+						prevPolicyEnabled = val
 					}
-				} else if event.Type == "DELETED" {
-					for idx, k8spod := range dm.K8sPods {
-						if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
-							dm.K8sPods = append(dm.K8sPods[:idx], dm.K8sPods[idx+1:]...)
-							break
+
+					if updateSeccomp && prevPolicyEnabled != "enabled" && pod.Annotations["kubearmor-policy"] == "enabled" {
+						if deploymentName, ok := pod.Metadata["deploymentName"]; ok {
+							// patch the deployment with seccomp profiles
+							if err := K8s.PatchDeploymentWithSeccompProfiles(pod.Metadata["namespaceName"], deploymentName, newProfiles); err != nil {
+								dm.Logger.Errf("Failed to update Seccomp Profiles (%s/%s/%s, %s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"], err.Error())
+							} else {
+								dm.Logger.Printf("Patched Seccomp Profiles (%s/%s/%s)", pod.Metadata["namespaceName"], deploymentName, pod.Metadata["podName"])
+							}
+							pod.Annotations["kubearmor-policy"] = "patched"
 						}
 					}
-				} else { // Otherwise
-					dm.K8sPodsLock.Unlock()
-					continue
-				}
 
-				dm.K8sPodsLock.Unlock()
+					break
+				}
+			}
+		} else if event.Type == "DELETED" {
+			// update seccomp profiles
+			dm.RuntimeEnforcer.RegisterEndpoint("DELETED", efc.Endpoint{NamespaceName: pod.Metadata["namespaceName"], EndPointName: pod.Metadata["podName"], SeccompProfiles: seccompProfiles})
+		}
+	}
 
-				// == //
+	// == //
 
-				if pod.Annotations["kubearmor-policy"] == "patched" {
-					dm.Logger.Printf("Detected a Pod (patched/%s/%s)", pod.Metadata["namespaceName"], pod.Metadata["podName"])
-				} else {
-					dm.Logger.Printf("Detected a Pod (%s/%s/%s)", strings.ToLower(event.Type), pod.Metadata["namespaceName"], pod.Metadata["podName"])
-				}
+	dm.K8sPodsLock.Lock()
 
-				// update a endpoint corresponding to the pod
-				dm.UpdateEndPointWithPod(event.Type, pod)
+	if event.Type == "ADDED" {
+		new := true
+		for _, k8spod := range dm.K8sPods {
+			if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+				new = false
+				break
+			}
+		}
+		if new {
+			dm.K8sPods = append(dm.K8sPods, pod)
+		}
+	} else if event.Type == "MODIFIED" {
+		for idx, k8spod := range dm.K8sPods {
+			if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+				dm.K8sPods[idx] = pod
+				break
+			}
+		}
+	} else if event.Type == "DELETED" {
+		for idx, k8spod := range dm.K8sPods {
+			if k8spod.Metadata["namespaceName"] == pod.Metadata["namespaceName"] && k8spod.Metadata["podName"] == pod.Metadata["podName"] {
+				dm.K8sPods = append(dm.K8sPods[:idx], dm.K8sPods[idx+1:]...)
+				break
 			}
-		} else {
-			time.Sleep(time.Second * 1)
 		}
+	} else { // Otherwise
+		dm.K8sPodsLock.Unlock()
+		return
+	}
+
+	dm.K8sPodsLock.Unlock()
+
+	// == //
+
+	if pod.Annotations["kubearmor-policy"] == "patched" {
+		dm.Logger.Printf("Detected a Pod (patched/%s/%s)", pod.Metadata["namespaceName"], pod.Metadata["podName"])
+	} else {
+		dm.Logger.Printf("Detected a Pod (%s/%s/%s)", strings.ToLower(event.Type), pod.Metadata["namespaceName"], pod.Metadata["podName"])
 	}
+
+	// update a endpoint corresponding to the pod
+	dm.UpdateEndPointWithPod(event.Type, pod)
+}
+
+// WatchK8sPods Function
+func (dm *KubeArmorDaemon) WatchK8sPods() {
+	dm.RegisterK8sWatcher("pods")
+
+	// only the pods scheduled on this node
+	informer := dm.NewK8sInformer("pods", K8s.NewPodListWatch(dm.GetNode().NodeName), &v1.Pod{}, func(eventType string, obj interface{}) {
+		if pod, ok := obj.(*v1.Pod); ok {
+			dm.UpdateK8sPod(tp.K8sPodEvent{Type: eventType, Object: *pod})
+		}
+	})
+
+	informer.Run(StopChan)
 }
 
 // ============================ //
@@ -800,855 +903,542 @@ func (dm *KubeArmorDaemon) UpdateSecurityPolicy(action string, secPolicy tp.Secu
 
 			// enforce security policies
 			dm.RuntimeEnforcer.UpdateSecurityPolicies(dm.EndPoints[idx])
+
+			// filter invisible events in the kernel
+			dm.UpdateVisibilityMap(dm.EndPoints[idx])
 		}
 	}
 }
 
-// WatchSecurityPolicies Function
-func (dm *KubeArmorDaemon) WatchSecurityPolicies() {
-	for {
-		if !K8s.CheckCustomResourceDefinition("kubearmorpolicies") {
-			time.Sleep(time.Second * 1)
-			continue
-		}
-
-		if resp := K8s.WatchK8sSecurityPolicies(); resp != nil {
-			defer resp.Body.Close()
+// UpdateK8sSecurityPolicy Function
+func (dm *KubeArmorDaemon) UpdateK8sSecurityPolicy(event tp.K8sKubeArmorPolicyEvent) {
+	if event.Object.Status.Status != "" && event.Object.Status.Status != "OK" {
+		return
+	}
 
-			decoder := json.NewDecoder(resp.Body)
-			for {
-				event := tp.K8sKubeArmorPolicyEvent{}
-				if err := decoder.Decode(&event); err == io.EOF {
-					break
-				} else if err != nil {
-					break
-				}
+	// create a security policy
 
-				if event.Object.Status.Status != "" && event.Object.Status.Status != "OK" {
-					continue
-				}
+	secPolicy, err := pl.ConvertKubeArmorPolicy(event.Object)
+	if err != nil {
+		dm.Logger.Errf("Failed to convert a security policy (%s)", err.Error())
+		return
+	}
 
-				dm.SecurityPoliciesLock.Lock()
+	dm.SecurityPoliciesLock.Lock()
 
-				// create a security policy
+	// update a security policy into the policy list
 
-				secPolicy := tp.SecurityPolicy{}
+	if event.Type == "ADDED" {
+		new := true
+		for _, policy := range dm.SecurityPolicies {
+			if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				new = false
+				break
+			}
+		}
+		if new {
+			dm.SecurityPolicies = append(dm.SecurityPolicies, secPolicy)
+		}
+	} else if event.Type == "MODIFIED" {
+		for idx, policy := range dm.SecurityPolicies {
+			if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.SecurityPolicies[idx] = secPolicy
+				break
+			}
+		}
+	} else if event.Type == "DELETED" {
+		for idx, policy := range dm.SecurityPolicies {
+			if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.SecurityPolicies = append(dm.SecurityPolicies[:idx], dm.SecurityPolicies[idx+1:]...)
+				break
+			}
+		}
+	}
 
-				secPolicy.Metadata = map[string]string{}
-				secPolicy.Metadata["namespaceName"] = event.Object.Metadata.Namespace
-				secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
+	dm.SecurityPoliciesLock.Unlock()
 
-				if err := kl.Clone(event.Object.Spec, &secPolicy.Spec); err != nil {
-					dm.Logger.Err("Failed to clone a spec")
-				}
+	dm.Logger.Printf("Detected a Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
 
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
+	// apply security policies to pods
+	dm.UpdateSecurityPolicy(event.Type, secPolicy)
+}
 
-				if secPolicy.Spec.Severity == 0 {
-					secPolicy.Spec.Severity = 1 // the lowest severity, by default
-				}
+// WatchSecurityPolicies Function
+func (dm *KubeArmorDaemon) WatchSecurityPolicies() {
+	dm.RegisterK8sWatcher("kubearmorpolicies")
 
-				switch secPolicy.Spec.Action {
-				case "allow":
-					secPolicy.Spec.Action = "Allow"
-				case "audit":
-					secPolicy.Spec.Action = "Audit"
-				case "block":
-					secPolicy.Spec.Action = "Block"
-				case "":
-					secPolicy.Spec.Action = "Block" // by default
-				}
+	for !K8s.CheckCustomResourceDefinition("kubearmorpolicies") {
+		dm.MarkK8sWatcherWaiting("kubearmorpolicies")
+		time.Sleep(time.Second * 1)
+	}
 
-				// add identities
+	informer := dm.NewK8sInformer("kubearmorpolicies", K8s.NewCustomResourceListWatch(SecurityPolicyResource), &unstructured.Unstructured{}, func(eventType string, obj interface{}) {
+		event := tp.K8sKubeArmorPolicyEvent{Type: eventType}
+		if err := DecodeUnstructured(obj, &event.Object); err != nil {
+			dm.Logger.Errf("Failed to decode a security policy (%s)", err.Error())
+			return
+		}
+		dm.UpdateK8sSecurityPolicy(event)
+	})
 
-				secPolicy.Spec.Selector.Identities = []string{"namespaceName=" + event.Object.Metadata.Namespace}
+	informer.Run(StopChan)
+}
 
-				for k, v := range secPolicy.Spec.Selector.MatchLabels {
-					secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
-				}
+// ================================= //
+// == Host Security Policy Update == //
+// ================================= //
 
-				sort.Slice(secPolicy.Spec.Selector.Identities, func(i, j int) bool {
-					return secPolicy.Spec.Selector.Identities[i] < secPolicy.Spec.Selector.Identities[j]
-				})
+// UpdateHostSecurityPolicies Function
+func (dm *KubeArmorDaemon) UpdateHostSecurityPolicies() {
+	dm.HostSecurityPoliciesLock.Lock()
+	defer dm.HostSecurityPoliciesLock.Unlock()
 
-				// add severities, tags, messages, and actions
+	node := dm.GetNode()
 
-				if len(secPolicy.Spec.Process.MatchPaths) > 0 {
-					for idx, path := range secPolicy.Spec.Process.MatchPaths {
-						if path.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+	secPolicies := []tp.HostSecurityPolicy{}
 
-						if len(path.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	for _, policy := range dm.HostSecurityPolicies {
+		if kl.MatchIdentities(policy.Spec.NodeSelector.Identities, node.Identities) {
+			secPolicies = append(secPolicies, policy)
+		}
+	}
 
-						if len(path.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	// update host security policies
+	dm.Logger.UpdateHostSecurityPolicies("UPDATED", secPolicies)
 
-						if len(path.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				}
+	// keep the events that host security policies need in the kernel
+	dm.updateHostVisibility()
 
-				if len(secPolicy.Spec.Process.MatchDirectories) > 0 {
-					for idx, dir := range secPolicy.Spec.Process.MatchDirectories {
-						if dir.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+	if node.PolicyEnabled == tp.KubeArmorPolicyEnabled {
+		// enforce host security policies
+		dm.RuntimeEnforcer.UpdateHostSecurityPolicies(secPolicies)
+	}
+}
 
-						if len(dir.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+// UpdateK8sHostSecurityPolicy Function
+func (dm *KubeArmorDaemon) UpdateK8sHostSecurityPolicy(event tp.K8sKubeArmorHostPolicyEvent) {
+	if event.Object.Status.Status != "" && event.Object.Status.Status != "OK" {
+		return
+	}
 
-						if len(dir.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	dm.HostSecurityPoliciesLock.Lock()
 
-						if len(dir.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				}
+	// create a host security policy
 
-				if len(secPolicy.Spec.Process.MatchPatterns) > 0 {
-					for idx, pat := range secPolicy.Spec.Process.MatchPatterns {
-						if pat.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+	secPolicy := tp.HostSecurityPolicy{}
 
-						if len(pat.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	secPolicy.Metadata = map[string]string{}
+	secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
 
-						if len(pat.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	if err := kl.Clone(event.Object.Spec, &secPolicy.Spec); err != nil {
+		dm.Logger.Err("Failed to clone a spec")
+	}
 
-						if len(pat.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				}
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
 
-				if len(secPolicy.Spec.File.MatchPaths) > 0 {
-					for idx, path := range secPolicy.Spec.File.MatchPaths {
-						if path.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+	if secPolicy.Spec.Severity == 0 {
+		secPolicy.Spec.Severity = 1 // the lowest severity, by default
+	}
 
-						if len(path.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	switch secPolicy.Spec.Action {
+	case "allow":
+		secPolicy.Spec.Action = "Allow"
+	case "audit":
+		secPolicy.Spec.Action = "Audit"
+	case "block":
+		secPolicy.Spec.Action = "Block"
+	case "":
+		secPolicy.Spec.Action = "Block" // by default
+	}
 
-						if len(path.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	// add identities
 
-						if len(path.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				}
+	secPolicy.Spec.NodeSelector.Identities = []string{}
 
-				if len(secPolicy.Spec.File.MatchDirectories) > 0 {
-					for idx, dir := range secPolicy.Spec.File.MatchDirectories {
-						if dir.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+	for k, v := range secPolicy.Spec.NodeSelector.MatchLabels {
+		secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
+	}
 
-						if len(dir.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	sort.Slice(secPolicy.Spec.NodeSelector.Identities, func(i, j int) bool {
+		return secPolicy.Spec.NodeSelector.Identities[i] < secPolicy.Spec.NodeSelector.Identities[j]
+	})
 
-						if len(dir.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	// add severities, tags, messages, and actions
 
-						if len(dir.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+	if len(secPolicy.Spec.Process.MatchPaths) > 0 {
+		for idx, path := range secPolicy.Spec.Process.MatchPaths {
+			if path.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Severity
 				}
+			}
 
-				if len(secPolicy.Spec.File.MatchPatterns) > 0 {
-					for idx, pat := range secPolicy.Spec.File.MatchPatterns {
-						if pat.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(pat.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(pat.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(pat.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(path.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Tags
 				}
+			}
 
-				if len(secPolicy.Spec.Network.MatchProtocols) > 0 {
-					for idx, proto := range secPolicy.Spec.Network.MatchProtocols {
-						if proto.Severity == 0 {
-							if secPolicy.Spec.Network.Severity != 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Network.Severity
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(proto.Tags) == 0 {
-							if len(secPolicy.Spec.Network.Tags) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Network.Tags
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(proto.Message) == 0 {
-							if len(secPolicy.Spec.Network.Message) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Network.Message
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(proto.Action) == 0 {
-							if len(secPolicy.Spec.Network.Action) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Network.Action
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(path.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Message
 				}
+			}
 
-				if len(secPolicy.Spec.Capabilities.MatchCapabilities) > 0 {
-					for idx, cap := range secPolicy.Spec.Capabilities.MatchCapabilities {
-						if cap.Severity == 0 {
-							if secPolicy.Spec.Capabilities.Severity != 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Capabilities.Severity
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(cap.Tags) == 0 {
-							if len(secPolicy.Spec.Capabilities.Tags) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Capabilities.Tags
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(cap.Message) == 0 {
-							if len(secPolicy.Spec.Capabilities.Message) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Capabilities.Message
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(cap.Action) == 0 {
-							if len(secPolicy.Spec.Capabilities.Action) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Capabilities.Action
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(path.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Action
 				}
+			}
+		}
+	} else if len(secPolicy.Spec.Process.MatchDirectories) > 0 {
+		for idx, dir := range secPolicy.Spec.Process.MatchDirectories {
+			if dir.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
 
-				if len(secPolicy.Spec.SELinux.MatchVolumeMounts) > 0 {
-					for idx, se := range secPolicy.Spec.SELinux.MatchVolumeMounts {
-						if se.Severity == 0 {
-							if secPolicy.Spec.SELinux.Severity != 0 {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Severity = secPolicy.Spec.SELinux.Severity
-							} else {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(se.Tags) == 0 {
-							if len(secPolicy.Spec.SELinux.Tags) > 0 {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Tags = secPolicy.Spec.SELinux.Tags
-							} else {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(se.Message) == 0 {
-							if len(secPolicy.Spec.SELinux.Message) > 0 {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Message = secPolicy.Spec.SELinux.Message
-							} else {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(se.Action) == 0 {
-							if len(secPolicy.Spec.SELinux.Action) > 0 {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Action = secPolicy.Spec.SELinux.Action
-							} else {
-								secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(dir.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
 				}
+			}
 
-				// update a security policy into the policy list
+			if len(dir.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Message
+				}
+			}
 
-				if event.Type == "ADDED" {
-					new := true
-					for _, policy := range dm.SecurityPolicies {
-						if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							new = false
-							break
-						}
-					}
-					if new {
-						dm.SecurityPolicies = append(dm.SecurityPolicies, secPolicy)
-					}
-				} else if event.Type == "MODIFIED" {
-					for idx, policy := range dm.SecurityPolicies {
-						if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							dm.SecurityPolicies[idx] = secPolicy
-							break
-						}
-					}
-				} else if event.Type == "DELETED" {
-					for idx, policy := range dm.SecurityPolicies {
-						if policy.Metadata["namespaceName"] == secPolicy.Metadata["namespaceName"] && policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							dm.SecurityPolicies = append(dm.SecurityPolicies[:idx], dm.SecurityPolicies[idx+1:]...)
-							break
-						}
-					}
+			if len(dir.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	} else if len(secPolicy.Spec.Process.MatchPatterns) > 0 {
+		for idx, pat := range secPolicy.Spec.Process.MatchPatterns {
+			if pat.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
 				}
+			}
 
-				dm.SecurityPoliciesLock.Unlock()
+			if len(pat.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
 
-				dm.Logger.Printf("Detected a Security Policy (%s/%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["namespaceName"], secPolicy.Metadata["policyName"])
+			if len(pat.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Message
+				}
+			}
 
-				// apply security policies to pods
-				dm.UpdateSecurityPolicy(event.Type, secPolicy)
+			if len(pat.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Action
+				}
 			}
 		}
 	}
-}
 
-// ================================= //
-// == Host Security Policy Update == //
-// ================================= //
+	if len(secPolicy.Spec.File.MatchPaths) > 0 {
+		for idx, path := range secPolicy.Spec.File.MatchPaths {
+			if path.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
 
-// UpdateHostSecurityPolicies Function
-func (dm *KubeArmorDaemon) UpdateHostSecurityPolicies() {
-	dm.HostSecurityPoliciesLock.Lock()
-	defer dm.HostSecurityPoliciesLock.Unlock()
+			if len(path.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
 
-	secPolicies := []tp.HostSecurityPolicy{}
+			if len(path.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.Message
+				}
+			}
 
-	for _, policy := range dm.HostSecurityPolicies {
-		if kl.MatchIdentities(policy.Spec.NodeSelector.Identities, dm.Node.Identities) {
-			secPolicies = append(secPolicies, policy)
+			if len(path.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.Action
+				}
+			}
 		}
-	}
+	} else if len(secPolicy.Spec.File.MatchDirectories) > 0 {
+		for idx, dir := range secPolicy.Spec.File.MatchDirectories {
+			if dir.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
 
-	// update host security policies
-	dm.Logger.UpdateHostSecurityPolicies("UPDATED", secPolicies)
+			if len(dir.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
 
-	if dm.Node.PolicyEnabled == tp.KubeArmorPolicyEnabled {
-		// enforce host security policies
-		dm.RuntimeEnforcer.UpdateHostSecurityPolicies(secPolicies)
-	}
-}
+			if len(dir.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.Message
+				}
+			}
 
-// WatchHostSecurityPolicies Function
-func (dm *KubeArmorDaemon) WatchHostSecurityPolicies() {
-	for {
-		if !K8s.CheckCustomResourceDefinition("kubearmorhostpolicies") {
-			time.Sleep(time.Second * 1)
-			continue
+			if len(dir.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.Action
+				}
+			}
 		}
-
-		if resp := K8s.WatchK8sHostSecurityPolicies(); resp != nil {
-			defer resp.Body.Close()
-
-			decoder := json.NewDecoder(resp.Body)
-			for {
-				event := tp.K8sKubeArmorHostPolicyEvent{}
-				if err := decoder.Decode(&event); err == io.EOF {
-					break
-				} else if err != nil {
-					break
+	} else if len(secPolicy.Spec.File.MatchPatterns) > 0 {
+		for idx, pat := range secPolicy.Spec.File.MatchPatterns {
+			if pat.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
 				}
+			}
 
-				if event.Object.Status.Status != "" && event.Object.Status.Status != "OK" {
-					continue
+			if len(pat.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
 				}
+			}
 
-				dm.HostSecurityPoliciesLock.Lock()
-
-				// create a host security policy
-
-				secPolicy := tp.HostSecurityPolicy{}
-
-				secPolicy.Metadata = map[string]string{}
-				secPolicy.Metadata["policyName"] = event.Object.Metadata.Name
-
-				if err := kl.Clone(event.Object.Spec, &secPolicy.Spec); err != nil {
-					dm.Logger.Err("Failed to clone a spec")
+			if len(pat.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.Message
 				}
+			}
 
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
-				kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
-
-				if secPolicy.Spec.Severity == 0 {
-					secPolicy.Spec.Severity = 1 // the lowest severity, by default
+			if len(pat.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.Action
 				}
+			}
+		}
+	}
 
-				switch secPolicy.Spec.Action {
-				case "allow":
-					secPolicy.Spec.Action = "Allow"
-				case "audit":
-					secPolicy.Spec.Action = "Audit"
-				case "block":
-					secPolicy.Spec.Action = "Block"
-				case "":
-					secPolicy.Spec.Action = "Block" // by default
+	if len(secPolicy.Spec.Network.MatchProtocols) > 0 {
+		for idx, proto := range secPolicy.Spec.Network.MatchProtocols {
+			if proto.Severity == 0 {
+				if secPolicy.Spec.Network.Severity != 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Network.Severity
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Severity
 				}
+			}
 
-				// add identities
-
-				secPolicy.Spec.NodeSelector.Identities = []string{}
-
-				for k, v := range secPolicy.Spec.NodeSelector.MatchLabels {
-					secPolicy.Spec.NodeSelector.Identities = append(secPolicy.Spec.NodeSelector.Identities, k+"="+v)
+			if len(proto.Tags) == 0 {
+				if len(secPolicy.Spec.Network.Tags) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Network.Tags
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Tags
 				}
+			}
 
-				sort.Slice(secPolicy.Spec.NodeSelector.Identities, func(i, j int) bool {
-					return secPolicy.Spec.NodeSelector.Identities[i] < secPolicy.Spec.NodeSelector.Identities[j]
-				})
-
-				// add severities, tags, messages, and actions
-
-				if len(secPolicy.Spec.Process.MatchPaths) > 0 {
-					for idx, path := range secPolicy.Spec.Process.MatchPaths {
-						if path.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(path.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(path.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(path.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				} else if len(secPolicy.Spec.Process.MatchDirectories) > 0 {
-					for idx, dir := range secPolicy.Spec.Process.MatchDirectories {
-						if dir.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(dir.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(dir.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(dir.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				} else if len(secPolicy.Spec.Process.MatchPatterns) > 0 {
-					for idx, pat := range secPolicy.Spec.Process.MatchPatterns {
-						if pat.Severity == 0 {
-							if secPolicy.Spec.Process.Severity != 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Process.Severity
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(pat.Tags) == 0 {
-							if len(secPolicy.Spec.Process.Tags) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Process.Tags
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(pat.Message) == 0 {
-							if len(secPolicy.Spec.Process.Message) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Process.Message
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(pat.Action) == 0 {
-							if len(secPolicy.Spec.Process.Action) > 0 {
-								secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Process.Action
-							} else {
-								secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(proto.Message) == 0 {
+				if len(secPolicy.Spec.Network.Message) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Network.Message
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Message
 				}
+			}
 
-				if len(secPolicy.Spec.File.MatchPaths) > 0 {
-					for idx, path := range secPolicy.Spec.File.MatchPaths {
-						if path.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(path.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(path.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(path.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				} else if len(secPolicy.Spec.File.MatchDirectories) > 0 {
-					for idx, dir := range secPolicy.Spec.File.MatchDirectories {
-						if dir.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
-
-						if len(dir.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
-
-						if len(dir.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.Message
-							}
-						}
-
-						if len(dir.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				} else if len(secPolicy.Spec.File.MatchPatterns) > 0 {
-					for idx, pat := range secPolicy.Spec.File.MatchPatterns {
-						if pat.Severity == 0 {
-							if secPolicy.Spec.File.Severity != 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.File.Severity
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+			if len(proto.Action) == 0 {
+				if len(secPolicy.Spec.Network.Action) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Network.Action
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
 
-						if len(pat.Tags) == 0 {
-							if len(secPolicy.Spec.File.Tags) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.File.Tags
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	if len(secPolicy.Spec.Capabilities.MatchCapabilities) > 0 {
+		for idx, cap := range secPolicy.Spec.Capabilities.MatchCapabilities {
+			if cap.Severity == 0 {
+				if secPolicy.Spec.Capabilities.Severity != 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Capabilities.Severity
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
 
-						if len(pat.Message) == 0 {
-							if len(secPolicy.Spec.File.Message) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.File.Message
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.Message
-							}
-						}
+			if len(cap.Tags) == 0 {
+				if len(secPolicy.Spec.Capabilities.Tags) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Capabilities.Tags
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
 
-						if len(pat.Action) == 0 {
-							if len(secPolicy.Spec.File.Action) > 0 {
-								secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.File.Action
-							} else {
-								secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(cap.Message) == 0 {
+				if len(secPolicy.Spec.Capabilities.Message) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Capabilities.Message
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Message
 				}
+			}
 
-				if len(secPolicy.Spec.Network.MatchProtocols) > 0 {
-					for idx, proto := range secPolicy.Spec.Network.MatchProtocols {
-						if proto.Severity == 0 {
-							if secPolicy.Spec.Network.Severity != 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Network.Severity
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+			if len(cap.Action) == 0 {
+				if len(secPolicy.Spec.Capabilities.Action) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Capabilities.Action
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
 
-						if len(proto.Tags) == 0 {
-							if len(secPolicy.Spec.Network.Tags) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Network.Tags
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	if len(secPolicy.Spec.Syscalls.MatchSyscalls) > 0 {
+		for idx, sys := range secPolicy.Spec.Syscalls.MatchSyscalls {
+			if sys.Severity == 0 {
+				if secPolicy.Spec.Syscalls.Severity != 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Severity = secPolicy.Spec.Syscalls.Severity
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
 
-						if len(proto.Message) == 0 {
-							if len(secPolicy.Spec.Network.Message) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Network.Message
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Message
-							}
-						}
+			if len(sys.Tags) == 0 {
+				if len(secPolicy.Spec.Syscalls.Tags) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Tags = secPolicy.Spec.Syscalls.Tags
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
 
-						if len(proto.Action) == 0 {
-							if len(secPolicy.Spec.Network.Action) > 0 {
-								secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Network.Action
-							} else {
-								secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
+			if len(sys.Message) == 0 {
+				if len(secPolicy.Spec.Syscalls.Message) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Message = secPolicy.Spec.Syscalls.Message
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Message = secPolicy.Spec.Message
 				}
+			}
 
-				if len(secPolicy.Spec.Capabilities.MatchCapabilities) > 0 {
-					for idx, cap := range secPolicy.Spec.Capabilities.MatchCapabilities {
-						if cap.Severity == 0 {
-							if secPolicy.Spec.Capabilities.Severity != 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Capabilities.Severity
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Severity
-							}
-						}
+			if len(sys.Action) == 0 {
+				if len(secPolicy.Spec.Syscalls.Action) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = secPolicy.Spec.Syscalls.Action
+				} else if secPolicy.Spec.Action != "Allow" {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = secPolicy.Spec.Action
+				} else {
+					// syscalls cannot be allowed (whitelisted), so they are audited
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = "Audit"
+				}
+			}
+		}
+	}
 
-						if len(cap.Tags) == 0 {
-							if len(secPolicy.Spec.Capabilities.Tags) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Capabilities.Tags
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Tags
-							}
-						}
+	// update a security policy into the policy list
 
-						if len(cap.Message) == 0 {
-							if len(secPolicy.Spec.Capabilities.Message) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Capabilities.Message
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Message
-							}
-						}
+	if event.Type == "ADDED" {
+		new := true
+		for _, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				new = false
+				break
+			}
+		}
+		if new {
+			dm.HostSecurityPolicies = append(dm.HostSecurityPolicies, secPolicy)
+		}
+	} else if event.Type == "MODIFIED" {
+		for idx, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.HostSecurityPolicies[idx] = secPolicy
+				break
+			}
+		}
+	} else if event.Type == "DELETED" {
+		for idx, policy := range dm.HostSecurityPolicies {
+			if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
+				dm.HostSecurityPolicies = append(dm.HostSecurityPolicies[:idx], dm.HostSecurityPolicies[idx+1:]...)
+				break
+			}
+		}
+	}
 
-						if len(cap.Action) == 0 {
-							if len(secPolicy.Spec.Capabilities.Action) > 0 {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Capabilities.Action
-							} else {
-								secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Action
-							}
-						}
-					}
-				}
+	dm.HostSecurityPoliciesLock.Unlock()
 
-				// update a security policy into the policy list
+	dm.Logger.Printf("Detected a Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
 
-				if event.Type == "ADDED" {
-					new := true
-					for _, policy := range dm.HostSecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							new = false
-							break
-						}
-					}
-					if new {
-						dm.HostSecurityPolicies = append(dm.HostSecurityPolicies, secPolicy)
-					}
-				} else if event.Type == "MODIFIED" {
-					for idx, policy := range dm.HostSecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							dm.HostSecurityPolicies[idx] = secPolicy
-							break
-						}
-					}
-				} else if event.Type == "DELETED" {
-					for idx, policy := range dm.HostSecurityPolicies {
-						if policy.Metadata["policyName"] == secPolicy.Metadata["policyName"] {
-							dm.HostSecurityPolicies = append(dm.HostSecurityPolicies[:idx], dm.HostSecurityPolicies[idx+1:]...)
-							break
-						}
-					}
-				}
+	// apply security policies to a host
+	dm.UpdateHostSecurityPolicies()
+}
 
-				dm.HostSecurityPoliciesLock.Unlock()
+// WatchHostSecurityPolicies Function
+func (dm *KubeArmorDaemon) WatchHostSecurityPolicies() {
+	dm.RegisterK8sWatcher("kubearmorhostpolicies")
 
-				dm.Logger.Printf("Detected a Host Security Policy (%s/%s)", strings.ToLower(event.Type), secPolicy.Metadata["policyName"])
+	for !K8s.CheckCustomResourceDefinition("kubearmorhostpolicies") {
+		dm.MarkK8sWatcherWaiting("kubearmorhostpolicies")
+		time.Sleep(time.Second * 1)
+	}
 
-				// apply security policies to a host
-				dm.UpdateHostSecurityPolicies()
-			}
+	informer := dm.NewK8sInformer("kubearmorhostpolicies", K8s.NewCustomResourceListWatch(HostSecurityPolicyResource), &unstructured.Unstructured{}, func(eventType string, obj interface{}) {
+		event := tp.K8sKubeArmorHostPolicyEvent{Type: eventType}
+		if err := DecodeUnstructured(obj, &event.Object); err != nil {
+			dm.Logger.Errf("Failed to decode a host security policy (%s)", err.Error())
+			return
 		}
-	}
+		dm.UpdateK8sHostSecurityPolicy(event)
+	})
+
+	informer.Run(StopChan)
 }
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package core
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func TestSetNodePolicyEnabled(t *testing.T) {
+	dm := newTestDaemon(newFakeRuntime(true))
+
+	// no enforcer is initialized yet
+	node := tp.Node{NodeName: "test", Annotations: map[string]string{"kubearmor-policy": "enabled"}}
+	dm.setNodePolicyEnabled(&node)
+
+	if node.PolicyEnabled != tp.KubeArmorPolicyAudited {
+		t.Errorf("[FAIL] Enabled host policies without an enforcer (%d)", node.PolicyEnabled)
+		return
+	}
+	t.Log("[PASS] Audited host policies without an enforcer")
+
+	node.Annotations["kubearmor-policy"] = "disabled"
+	dm.setNodePolicyEnabled(&node)
+
+	if node.PolicyEnabled != tp.KubeArmorPolicyDisabled {
+		t.Errorf("[FAIL] Failed to disable host policies (%d)", node.PolicyEnabled)
+		return
+	}
+	t.Log("[PASS] Disabled host policies")
+}
+
+func TestGetSeccompProfile(t *testing.T) {
+	localhostProfile := "kubearmor/multiubuntu-ubuntu-1-container.json"
+
+	if profile := getSeccompProfile(&v1.SeccompProfile{Type: v1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}); profile != "localhost/kubearmor/multiubuntu-ubuntu-1-container.json" {
+		t.Errorf("[FAIL] Got an unexpected localhost profile (%s)", profile)
+		return
+	}
+
+	if profile := getSeccompProfile(&v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}); profile != "runtime/default" {
+		t.Errorf("[FAIL] Got an unexpected runtime profile (%s)", profile)
+		return
+	}
+	t.Log("[PASS] Got the seccomp profiles from security contexts")
+}
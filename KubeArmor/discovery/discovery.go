@@ -0,0 +1,642 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// DefaultCollapseThreshold is the number of files in a directory to use matchDirectories
+const DefaultCollapseThreshold = 8
+
+// DefaultWriteInterval to write policies into the output directory
+const DefaultWriteInterval = 60
+
+// MaxDiscoveredPaths per operation in a target
+const MaxDiscoveredPaths = 16384
+
+// invalid characters in policy names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ============ //
+// == Config == //
+// ============ //
+
+// Target Structure
+type Target struct {
+	NamespaceName string            `json:"namespace"`
+	MatchLabels   map[string]string `json:"matchLabels,omitempty"`
+}
+
+// Config Structure
+type Config struct {
+	// workloads to learn
+	Targets []Target `json:"targets,omitempty"`
+
+	// number of files in a directory to collapse them into the directory
+	CollapseThreshold int `json:"collapseThreshold,omitempty"`
+
+	// directory to write policies into (none if empty)
+	OutputDir string `json:"outputDir,omitempty"`
+
+	// interval to write policies (seconds)
+	WriteInterval int `json:"writeInterval,omitempty"`
+}
+
+// DefaultConfig Function
+func DefaultConfig() Config {
+	return Config{
+		Targets:           []Target{},
+		CollapseThreshold: DefaultCollapseThreshold,
+		WriteInterval:     DefaultWriteInterval,
+	}
+}
+
+// Validate Function
+func (config Config) Validate() error {
+	if config.CollapseThreshold < 2 {
+		return fmt.Errorf("invalid discovery collapseThreshold %d (should be 2 or more)", config.CollapseThreshold)
+	}
+
+	if config.WriteInterval <= 0 {
+		return fmt.Errorf("invalid discovery writeInterval %d", config.WriteInterval)
+	}
+
+	for _, target := range config.Targets {
+		if target.NamespaceName == "" {
+			return fmt.Errorf("no namespace in a discovery target")
+		}
+	}
+
+	return nil
+}
+
+// ============= //
+// == Session == //
+// ============= //
+
+// Session Structure
+type Session struct {
+	Target Target
+
+	// path -> source paths
+	Processes map[string]map[string]bool
+	Files     map[string]map[string]bool
+
+	// protocol -> source paths
+	Protocols map[string]map[string]bool
+
+	// for logs after reaching MaxDiscoveredPaths
+	Overflowed bool
+}
+
+// NewSession Function
+func NewSession(target Target) *Session {
+	return &Session{
+		Target:    target,
+		Processes: map[string]map[string]bool{},
+		Files:     map[string]map[string]bool{},
+		Protocols: map[string]map[string]bool{},
+	}
+}
+
+// Matches Function
+func (ss *Session) Matches(namespaceName string, labels map[string]string) bool {
+	if ss.Target.NamespaceName != namespaceName {
+		return false
+	}
+
+	for k, v := range ss.Target.MatchLabels {
+		if val, ok := labels[k]; !ok || val != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// add Function
+func (ss *Session) add(entries map[string]map[string]bool, resource, source string) {
+	sources, ok := entries[resource]
+	if !ok {
+		if len(entries) >= MaxDiscoveredPaths {
+			if !ss.Overflowed {
+				kg.Warnf("Observed more than %d paths in %s, ignoring new ones", MaxDiscoveredPaths, getPolicyName(ss.Target))
+			}
+			ss.Overflowed = true
+			return
+		}
+
+		sources = map[string]bool{}
+		entries[resource] = sources
+	}
+
+	// fromSource only takes absolute paths
+	if strings.HasPrefix(source, "/") {
+		sources[source] = true
+	} else {
+		sources[""] = true
+	}
+}
+
+// getProtocol Function
+func getProtocol(resource string) string {
+	fields := map[string]string{}
+
+	for _, field := range strings.Fields(resource) {
+		if kv := strings.SplitN(field, "=", 2); len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+
+	if fields["domain"] != "AF_INET" && fields["domain"] != "AF_INET6" {
+		return ""
+	}
+
+	// the type can have flags (e.g., SOCK_STREAM|SOCK_NONBLOCK|SOCK_CLOEXEC)
+	sockType := ""
+
+	for _, val := range strings.Split(fields["type"], "|") {
+		if val == "SOCK_STREAM" || val == "SOCK_DGRAM" || val == "SOCK_RAW" {
+			sockType = val
+			break
+		}
+	}
+
+	switch sockType {
+	case "SOCK_STREAM":
+		return "tcp"
+	case "SOCK_DGRAM":
+		return "udp"
+	case "SOCK_RAW":
+		if fields["protocol"] == "1" || fields["protocol"] == "58" {
+			return "icmp"
+		}
+	}
+
+	return ""
+}
+
+// Observe Function
+func (ss *Session) Observe(log tp.Log) {
+	switch log.Operation {
+	case "Process":
+		if path := strings.Split(log.Resource, " ")[0]; strings.HasPrefix(path, "/") {
+			ss.add(ss.Processes, path, log.Source)
+		}
+	case "File":
+		if strings.HasPrefix(log.Resource, "/") {
+			ss.add(ss.Files, log.Resource, log.Source)
+		}
+	case "Network":
+		if !strings.HasPrefix(log.Data, "syscall=SYS_SOCKET") {
+			return
+		}
+
+		if protocol := getProtocol(log.Resource); protocol != "" {
+			ss.add(ss.Protocols, protocol, log.Source)
+		}
+	}
+}
+
+// ============ //
+// == Policy == //
+// ============ //
+
+// PolicyMetadata Structure
+type PolicyMetadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// PolicySpec Structure
+type PolicySpec struct {
+	Selector tp.SelectorType `json:"selector"`
+
+	Process *tp.ProcessType `json:"process,omitempty"`
+	File    *tp.FileType    `json:"file,omitempty"`
+	Network *tp.NetworkType `json:"network,omitempty"`
+
+	Severity int    `json:"severity"`
+	Action   string `json:"action"`
+}
+
+// KubeArmorPolicy Structure
+type KubeArmorPolicy struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   PolicyMetadata `json:"metadata"`
+	Spec       PolicySpec     `json:"spec"`
+}
+
+// getPolicyName Function
+func getPolicyName(target Target) string {
+	keys := []string{}
+	for k := range target.MatchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	name := "discovered-" + target.NamespaceName
+	for _, k := range keys {
+		name = name + "-" + target.MatchLabels[k]
+	}
+
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+
+	return name
+}
+
+// getSources Function
+func getSources(sources map[string]bool) []tp.MatchSourceType {
+	// allowed from any source
+	if sources[""] {
+		return nil
+	}
+
+	fromSource := []tp.MatchSourceType{}
+	for source := range sources {
+		fromSource = append(fromSource, tp.MatchSourceType{Path: source})
+	}
+
+	sort.Slice(fromSource, func(i, j int) bool {
+		return fromSource[i].Path < fromSource[j].Path
+	})
+
+	return fromSource
+}
+
+// mergeSources Function
+func mergeSources(dst, src map[string]bool) {
+	for source := range src {
+		dst[source] = true
+	}
+}
+
+// collapseFiles Function
+func collapseFiles(files map[string]map[string]bool, threshold int) (map[string]map[string]bool, map[string]map[string]bool) {
+	// directory -> files
+	dirs := map[string][]string{}
+
+	for path := range files {
+		dir := filepath.Dir(path)
+		dirs[dir] = append(dirs[dir], path)
+	}
+
+	paths := map[string]map[string]bool{}
+	directories := map[string]map[string]bool{}
+
+	for dir, dirFiles := range dirs {
+		if len(dirFiles) < threshold || dir == "/" {
+			for _, path := range dirFiles {
+				paths[path] = files[path]
+			}
+			continue
+		}
+
+		sources := map[string]bool{}
+		for _, path := range dirFiles {
+			mergeSources(sources, files[path])
+		}
+
+		directories[dir+"/"] = sources
+	}
+
+	return paths, directories
+}
+
+// sortedKeys Function
+func sortedKeys(entries map[string]map[string]bool) []string {
+	keys := []string{}
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// GeneratePolicy Function
+func (ss *Session) GeneratePolicy(threshold int) KubeArmorPolicy {
+	policy := KubeArmorPolicy{
+		APIVersion: "security.kubearmor.com/v1",
+		Kind:       "KubeArmorPolicy",
+		Metadata: PolicyMetadata{
+			Name:      getPolicyName(ss.Target),
+			Namespace: ss.Target.NamespaceName,
+		},
+		Spec: PolicySpec{
+			Selector: tp.SelectorType{MatchLabels: ss.Target.MatchLabels},
+			Severity: 1,
+			Action:   "Allow",
+		},
+	}
+
+	if len(ss.Processes) > 0 {
+		policy.Spec.Process = &tp.ProcessType{}
+
+		for _, path := range sortedKeys(ss.Processes) {
+			policy.Spec.Process.MatchPaths = append(policy.Spec.Process.MatchPaths,
+				tp.ProcessPathType{Path: path, FromSource: getSources(ss.Processes[path])})
+		}
+	}
+
+	if len(ss.Files) > 0 {
+		policy.Spec.File = &tp.FileType{}
+
+		paths, directories := collapseFiles(ss.Files, threshold)
+
+		for _, path := range sortedKeys(paths) {
+			policy.Spec.File.MatchPaths = append(policy.Spec.File.MatchPaths,
+				tp.FilePathType{Path: path, FromSource: getSources(paths[path])})
+		}
+
+		for _, dir := range sortedKeys(directories) {
+			policy.Spec.File.MatchDirectories = append(policy.Spec.File.MatchDirectories,
+				tp.FileDirectoryType{Directory: dir, FromSource: getSources(directories[dir])})
+		}
+	}
+
+	if len(ss.Protocols) > 0 {
+		policy.Spec.Network = &tp.NetworkType{}
+
+		for _, protocol := range sortedKeys(ss.Protocols) {
+			policy.Spec.Network.MatchProtocols = append(policy.Spec.Network.MatchProtocols,
+				tp.NetworkProtocolType{Protocol: protocol, FromSource: getSources(ss.Protocols[protocol])})
+		}
+	}
+
+	return policy
+}
+
+// =============== //
+// == Discovery == //
+// =============== //
+
+// Discovery Structure
+type Discovery struct {
+	Config Config
+
+	// one session per target
+	Sessions []*Session
+	Lock     *sync.RWMutex
+
+	// labels of a pod (set by the daemon)
+	GetPodLabels func(namespaceName, podName string) map[string]string
+
+	// policy name -> the last written policy
+	Written map[string][]byte
+}
+
+// NewDiscovery Function
+func NewDiscovery(config Config) *Discovery {
+	dc := &Discovery{}
+
+	dc.Lock = new(sync.RWMutex)
+	dc.Written = map[string][]byte{}
+
+	dc.UpdateConfig(config)
+
+	return dc
+}
+
+// UpdateConfig Function
+func (dc *Discovery) UpdateConfig(config Config) {
+	dc.Lock.Lock()
+	defer dc.Lock.Unlock()
+
+	sessions := []*Session{}
+
+	for _, target := range config.Targets {
+		var session *Session
+
+		// keep what is learned so far for the same target
+		for _, ss := range dc.Sessions {
+			if ss.Target.NamespaceName == target.NamespaceName && reflect.DeepEqual(ss.Target.MatchLabels, target.MatchLabels) {
+				session = ss
+				break
+			}
+		}
+
+		if session == nil {
+			session = NewSession(target)
+			kg.Printf("Started to learn the behavior of pods in %s (labels: %v)", target.NamespaceName, target.MatchLabels)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	dc.Config = config
+	dc.Sessions = sessions
+}
+
+// IsEnabled Function
+func (dc *Discovery) IsEnabled() bool {
+	if dc == nil {
+		return false
+	}
+
+	dc.Lock.RLock()
+	defer dc.Lock.RUnlock()
+
+	return len(dc.Sessions) > 0
+}
+
+// IsTarget Function
+func (dc *Discovery) IsTarget(namespaceName string, labels map[string]string) bool {
+	if dc == nil {
+		return false
+	}
+
+	dc.Lock.RLock()
+	defer dc.Lock.RUnlock()
+
+	for _, ss := range dc.Sessions {
+		if ss.Matches(namespaceName, labels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Observe Function
+func (dc *Discovery) Observe(log tp.Log) {
+	if dc == nil || log.ContainerID == "" || log.NamespaceName == "" || log.Result != "Passed" {
+		return
+	}
+
+	// find the sessions under the read lock since most logs are not from the targets
+	sessions := []*Session{}
+
+	dc.Lock.RLock()
+
+	var labels map[string]string
+
+	for _, ss := range dc.Sessions {
+		if ss.Target.NamespaceName != log.NamespaceName {
+			continue
+		}
+
+		if labels == nil && dc.GetPodLabels != nil {
+			labels = dc.GetPodLabels(log.NamespaceName, log.PodName)
+		}
+
+		if ss.Matches(log.NamespaceName, labels) {
+			sessions = append(sessions, ss)
+		}
+	}
+
+	dc.Lock.RUnlock()
+
+	if len(sessions) == 0 {
+		return
+	}
+
+	dc.Lock.Lock()
+	defer dc.Lock.Unlock()
+
+	for _, ss := range sessions {
+		ss.Observe(log)
+	}
+}
+
+// GetPolicies Function
+func (dc *Discovery) GetPolicies(namespaceName string, labels map[string]string) []KubeArmorPolicy {
+	dc.Lock.RLock()
+	defer dc.Lock.RUnlock()
+
+	policies := []KubeArmorPolicy{}
+
+	for _, ss := range dc.Sessions {
+		if namespaceName != "" && ss.Target.NamespaceName != namespaceName {
+			continue
+		}
+
+		if len(labels) > 0 && !reflect.DeepEqual(ss.Target.MatchLabels, labels) {
+			continue
+		}
+
+		policies = append(policies, ss.GeneratePolicy(dc.Config.CollapseThreshold))
+	}
+
+	return policies
+}
+
+// WritePolicies Function
+func (dc *Discovery) WritePolicies(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	for _, policy := range dc.GetPolicies("", nil) {
+		// nothing observed yet
+		if policy.Spec.Process == nil && policy.Spec.File == nil && policy.Spec.Network == nil {
+			continue
+		}
+
+		data, err := yaml.Marshal(policy)
+		if err != nil {
+			return err
+		}
+
+		// skip the policies not changed
+		if bytes.Equal(dc.Written[policy.Metadata.Name], data) {
+			continue
+		}
+
+		path := filepath.Join(dir, policy.Metadata.Name+".yaml")
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+
+		dc.Written[policy.Metadata.Name] = data
+	}
+
+	return nil
+}
+
+// WritePoliciesPeriodically Function
+func (dc *Discovery) WritePoliciesPeriodically(stopChan chan struct{}) {
+	for {
+		dc.Lock.RLock()
+		dir := dc.Config.OutputDir
+		interval := time.Duration(dc.Config.WriteInterval) * time.Second
+		dc.Lock.RUnlock()
+
+		select {
+		case <-stopChan:
+			if dir != "" {
+				if err := dc.WritePolicies(dir); err != nil {
+					kg.Errf("Failed to write discovered policies (%s)", err.Error())
+				}
+			}
+			return
+		case <-time.After(interval):
+		}
+
+		if dir == "" {
+			continue
+		}
+
+		if err := dc.WritePolicies(dir); err != nil {
+			kg.Errf("Failed to write discovered policies (%s)", err.Error())
+		}
+	}
+}
+
+// ============= //
+// == Service == //
+// ============= //
+
+// Service Structure
+type Service struct {
+	Discovery *Discovery
+}
+
+// GetDiscoveredPolicies Function
+func (ds *Service) GetDiscoveredPolicies(ctx context.Context, req *pb.DiscoveryRequest) (*pb.DiscoveryReply, error) {
+	reply := &pb.DiscoveryReply{}
+
+	for _, policy := range ds.Discovery.GetPolicies(req.NamespaceName, req.Labels) {
+		data, err := yaml.Marshal(policy)
+		if err != nil {
+			return nil, err
+		}
+
+		reply.Policies = append(reply.Policies, &pb.DiscoveredPolicy{
+			NamespaceName: policy.Metadata.Namespace,
+			PolicyName:    policy.Metadata.Name,
+			Labels:        policy.Spec.Selector.MatchLabels,
+			Policy:        string(data),
+		})
+	}
+
+	return reply, nil
+}
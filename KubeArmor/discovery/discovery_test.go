@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pl "github.com/kubearmor/KubeArmor/KubeArmor/policy"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+)
+
+func newTestDiscovery() *Discovery {
+	config := DefaultConfig()
+	config.CollapseThreshold = 3
+	config.Targets = []Target{{NamespaceName: "multiubuntu", MatchLabels: map[string]string{"group": "group-1"}}}
+
+	dc := NewDiscovery(config)
+	dc.GetPodLabels = func(namespaceName, podName string) map[string]string {
+		if podName == "ubuntu-1" {
+			return map[string]string{"group": "group-1", "container": "ubuntu-1"}
+		}
+		return map[string]string{"group": "group-2"}
+	}
+
+	logs := []tp.Log{
+		{Operation: "Process", Source: "/bin/bash", Resource: "/bin/sleep 1"},
+		{Operation: "Process", Source: "/bin/bash", Resource: "/bin/ls -al"},
+		{Operation: "Process", Source: "runc:[2:INIT]", Resource: "/bin/bash"},
+		{Operation: "File", Source: "/bin/cat", Resource: "/etc/hostname"},
+		{Operation: "File", Source: "/bin/ls", Resource: "relative/path"},
+		{Operation: "Network", Source: "/usr/bin/curl", Resource: "domain=AF_INET type=SOCK_STREAM protocol=0", Data: "syscall=SYS_SOCKET"},
+		{Operation: "Network", Source: "/usr/bin/curl", Resource: "domain=AF_UNIX type=SOCK_STREAM protocol=0", Data: "syscall=SYS_SOCKET"},
+	}
+
+	// noisy files in a directory
+	for i := 0; i < 3; i++ {
+		logs = append(logs, tp.Log{Operation: "File", Source: "/usr/bin/python3", Resource: fmt.Sprintf("/usr/lib/python3/module%d.py", i)})
+	}
+
+	for _, log := range logs {
+		log.NamespaceName = "multiubuntu"
+		log.PodName = "ubuntu-1"
+		log.ContainerID = "abc"
+		log.Result = "Passed"
+
+		dc.Observe(log)
+	}
+
+	// not selected or failed
+	dc.Observe(tp.Log{NamespaceName: "multiubuntu", PodName: "ubuntu-3", ContainerID: "def", Operation: "Process", Source: "/bin/bash", Resource: "/bin/rm", Result: "Passed"})
+	dc.Observe(tp.Log{NamespaceName: "multiubuntu", PodName: "ubuntu-1", ContainerID: "abc", Operation: "File", Source: "/bin/cat", Resource: "/etc/shadow", Result: "Permission denied"})
+
+	return dc
+}
+
+func TestGeneratePolicy(t *testing.T) {
+	dc := newTestDiscovery()
+
+	policies := dc.GetPolicies("", nil)
+	if len(policies) != 1 {
+		t.Errorf("[FAIL] Got %d policies instead of 1", len(policies))
+		return
+	}
+
+	policy := policies[0]
+
+	if policy.Metadata.Name != "discovered-multiubuntu-group-1" || policy.Spec.Action != "Allow" {
+		t.Errorf("[FAIL] Got unexpected metadata (%+v)", policy.Metadata)
+		return
+	}
+
+	process := policy.Spec.Process
+	if process == nil || len(process.MatchPaths) != 3 {
+		t.Errorf("[FAIL] Got unexpected process rules (%+v)", process)
+		return
+	}
+
+	// runc is not an absolute path, so /bin/bash is allowed from any source
+	if process.MatchPaths[0].Path != "/bin/bash" || len(process.MatchPaths[0].FromSource) != 0 ||
+		process.MatchPaths[2].Path != "/bin/sleep" || process.MatchPaths[2].FromSource[0].Path != "/bin/bash" {
+		t.Errorf("[FAIL] Got unexpected process paths (%+v)", process.MatchPaths)
+		return
+	}
+	t.Log("[PASS] Generated process rules")
+
+	file := policy.Spec.File
+	if file == nil || len(file.MatchPaths) != 1 || len(file.MatchDirectories) != 1 {
+		t.Errorf("[FAIL] Got unexpected file rules (%+v)", file)
+		return
+	}
+
+	if file.MatchPaths[0].Path != "/etc/hostname" || file.MatchDirectories[0].Directory != "/usr/lib/python3/" ||
+		file.MatchDirectories[0].FromSource[0].Path != "/usr/bin/python3" {
+		t.Errorf("[FAIL] Failed to collapse files (%+v)", file)
+		return
+	}
+	t.Log("[PASS] Collapsed files into a directory")
+
+	network := policy.Spec.Network
+	if network == nil || len(network.MatchProtocols) != 1 || network.MatchProtocols[0].Protocol != "tcp" {
+		t.Errorf("[FAIL] Got unexpected network rules (%+v)", network)
+		return
+	}
+	t.Log("[PASS] Generated network rules")
+}
+
+func TestIsTarget(t *testing.T) {
+	dc := newTestDiscovery()
+
+	if !dc.IsTarget("multiubuntu", map[string]string{"group": "group-1", "container": "ubuntu-1"}) {
+		t.Errorf("[FAIL] Failed to find a target")
+		return
+	}
+
+	if dc.IsTarget("multiubuntu", map[string]string{"group": "group-2"}) || dc.IsTarget("default", map[string]string{"group": "group-1"}) {
+		t.Errorf("[FAIL] Found a pod that is not a target")
+		return
+	}
+
+	var nilDiscovery *Discovery
+	if nilDiscovery.IsTarget("multiubuntu", map[string]string{"group": "group-1"}) {
+		t.Errorf("[FAIL] Found a target without discovery")
+		return
+	}
+	t.Log("[PASS] Found the targets of discovery")
+}
+
+func TestGetProtocol(t *testing.T) {
+	tests := []struct {
+		resource string
+		protocol string
+	}{
+		{resource: "domain=AF_INET type=SOCK_STREAM protocol=0", protocol: "tcp"},
+		{resource: "domain=AF_INET6 type=SOCK_STREAM|SOCK_NONBLOCK|SOCK_CLOEXEC protocol=0", protocol: "tcp"},
+		{resource: "domain=AF_INET type=SOCK_DGRAM|SOCK_CLOEXEC protocol=0", protocol: "udp"},
+		{resource: "domain=AF_INET type=SOCK_RAW|SOCK_NONBLOCK protocol=1", protocol: "icmp"},
+		{resource: "domain=AF_INET6 type=SOCK_RAW protocol=58", protocol: "icmp"},
+		{resource: "domain=AF_INET type=SOCK_RAW protocol=255", protocol: ""},
+		{resource: "domain=AF_UNIX type=SOCK_STREAM|SOCK_CLOEXEC protocol=0", protocol: ""},
+		{resource: "domain=AF_INET type=SOCK_NONBLOCK protocol=0", protocol: ""},
+	}
+
+	for _, test := range tests {
+		if protocol := getProtocol(test.resource); protocol != test.protocol {
+			t.Errorf("[FAIL] Got %q instead of %q (%s)", protocol, test.protocol, test.resource)
+			return
+		}
+	}
+	t.Log("[PASS] Got protocols from socket types with flags")
+}
+
+func TestWritePolicies(t *testing.T) {
+	dc := newTestDiscovery()
+
+	dir, err := ioutil.TempDir("", "kubearmor-discovery")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	if err := dc.WritePolicies(dir); err != nil {
+		t.Errorf("[FAIL] Failed to write policies (%s)", err.Error())
+		return
+	}
+
+	// the generated policy should be loaded as it is
+	secPolicies, err := pl.LoadKubeArmorPolicies([]string{filepath.Join(dir, "discovered-multiubuntu-group-1.yaml")})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to load the written policy (%s)", err.Error())
+		return
+	}
+
+	if len(secPolicies) != 1 || secPolicies[0].Spec.Process.MatchPaths[0].Action != "Allow" {
+		t.Errorf("[FAIL] Got an unexpected policy (%+v)", secPolicies)
+		return
+	}
+	t.Log("[PASS] Wrote policies")
+
+	ds := &Service{Discovery: dc}
+
+	reply, err := ds.GetDiscoveredPolicies(context.Background(), &pb.DiscoveryRequest{NamespaceName: "default"})
+	if err != nil || len(reply.Policies) != 0 {
+		t.Errorf("[FAIL] Got policies for another namespace")
+		return
+	}
+
+	reply, err = ds.GetDiscoveredPolicies(context.Background(), &pb.DiscoveryRequest{NamespaceName: "multiubuntu"})
+	if err != nil || len(reply.Policies) != 1 || reply.Policies[0].Policy == "" {
+		t.Errorf("[FAIL] Failed to get discovered policies over gRPC")
+		return
+	}
+	t.Log("[PASS] Served discovered policies")
+}
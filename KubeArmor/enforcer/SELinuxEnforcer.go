@@ -5,19 +5,30 @@ package enforcer
 
 import (
 	"bufio"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
 	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 
 	"golang.org/x/sys/unix"
 )
 
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// the command to manage SELinux modules
+var semanageCommand = "semanage"
+
 // ====================== //
 // == SELinux Enforcer == //
 // ====================== //
@@ -37,14 +48,34 @@ type SELinuxEnforcer struct {
 func NewSELinuxEnforcer(logger *fd.Feeder) *SELinuxEnforcer {
 	se := &SELinuxEnforcer{}
 
+	if err := se.Init(tp.Node{}, logger); err != nil {
+		logger.Errf("Failed to initialize SELinux Enforcer (%s)", err.Error())
+		return nil
+	}
+
+	return se
+}
+
+// Name Function
+func (se *SELinuxEnforcer) Name() string {
+	return "SELinux"
+}
+
+// Capabilities Function
+func (se *SELinuxEnforcer) Capabilities() uint32 {
+	// SELinux profiles only cover host volumes, so security policies in containers are audited
+	return EnforcerCapSELinuxContexts
+}
+
+// Init Function
+func (se *SELinuxEnforcer) Init(node tp.Node, logger *fd.Feeder) error {
 	se.Logger = logger
 
 	se.SELinuxProfiles = map[string]int{}
 	se.SELinuxProfilesLock = &sync.Mutex{}
 
 	if _, err := os.Stat("/usr/sbin/semanage"); err != nil {
-		se.Logger.Errf("Failed to find /usr/sbin/semanage (%s)", err.Error())
-		return nil
+		return fmt.Errorf("failed to find /usr/sbin/semanage (%s)", err.Error())
 	}
 
 	se.SELinuxContextTemplates = "/KubeArmor/templates/"
@@ -56,12 +87,16 @@ func NewSELinuxEnforcer(logger *fd.Feeder) *SELinuxEnforcer {
 	}
 
 	// install template cil
-	if err := kl.RunCommandAndWaitWithErr("semanage", []string{"module", "-a", se.SELinuxContextTemplates + "base_container.cil"}); err != nil {
-		se.Logger.Errf("Failed to register a SELinux profile, %s (%s)", se.SELinuxContextTemplates+"base_container.cil", err.Error())
-		return nil
+	if err := kl.RunCommandAndWaitWithErr(semanageCommand, []string{"module", "-a", se.SELinuxContextTemplates + "base_container.cil"}); err != nil {
+		return fmt.Errorf("failed to register a SELinux profile, %s (%s)", se.SELinuxContextTemplates+"base_container.cil", err.Error())
 	}
 
-	return se
+	return nil
+}
+
+// Destroy Function
+func (se *SELinuxEnforcer) Destroy() error {
+	return se.DestroySELinuxEnforcer()
 }
 
 // DestroySELinuxEnforcer Function
@@ -76,7 +111,7 @@ func (se *SELinuxEnforcer) DestroySELinuxEnforcer() error {
 	}
 
 	// remove template cil
-	if err := kl.RunCommandAndWaitWithErr("semanage", []string{"module", "-r", "base_container"}); err != nil {
+	if err := kl.RunCommandAndWaitWithErr(semanageCommand, []string{"module", "-r", "base_container"}); err != nil {
 		se.Logger.Errf("Failed to register a SELinux profile, %s (%s)", se.SELinuxContextTemplates+"base_container.cil", err.Error())
 		return nil
 	}
@@ -146,6 +181,14 @@ func GetSELinuxType(path string) (string, error) {
 	return strings.Split(string(label), ":")[2], nil
 }
 
+// reloadSELinuxProfile Function
+func reloadSELinuxProfile(args []string) error {
+	start := time.Now()
+	err := kl.RunCommandAndWaitWithErr(semanageCommand, args)
+	mt.ObserveProfileReload("SELinux", start, err)
+	return err
+}
+
 // RegisterSELinuxProfile Function
 func (se *SELinuxEnforcer) RegisterSELinuxProfile(containerName string, hostVolumes []tp.HostVolumeMount, profileName string) bool {
 	// skip if selinux enforcer is not active
@@ -213,18 +256,26 @@ func (se *SELinuxEnforcer) RegisterSELinuxProfile(containerName string, hostVolu
 		se.Logger.Err(err.Error())
 	}
 
-	if err := kl.RunCommandAndWaitWithErr("semanage", []string{"module", "-a", profilePath}); err != nil {
+	if err := reloadSELinuxProfile([]string{"module", "-a", profilePath}); err != nil {
 		se.Logger.Errf("Failed to register a SELinux profile (%s, %s)", profileName, err.Error())
 		return false
 	}
 
+	// an existing profile is rewritten and reloaded above
 	if _, ok := se.SELinuxProfiles[profileName]; !ok {
 		se.SELinuxProfiles[profileName] = 1
-		se.Logger.Errf("Registered a SELinux profile (%s)", profileName)
-		return true
+		se.Logger.Printf("Registered a SELinux profile (%s)", profileName)
 	}
 
-	return false
+	return true
+}
+
+// GetProfileCount Function
+func (se *SELinuxEnforcer) GetProfileCount() int {
+	se.SELinuxProfilesLock.Lock()
+	defer se.SELinuxProfilesLock.Unlock()
+
+	return len(se.SELinuxProfiles)
 }
 
 // UnregisterSELinuxProfile Function
@@ -254,7 +305,7 @@ func (se *SELinuxEnforcer) UnregisterSELinuxProfile(profileName string) bool {
 		return false
 	}
 
-	if err := kl.RunCommandAndWaitWithErr("semanage", []string{"module", "-r", profileName}); err != nil {
+	if err := kl.RunCommandAndWaitWithErr(semanageCommand, []string{"module", "-r", profileName}); err != nil {
 		se.Logger.Errf("Unabale to unregister a SELinux profile (%s, %s)", profileName, err.Error())
 		return false
 	}
@@ -271,6 +322,29 @@ func (se *SELinuxEnforcer) UnregisterSELinuxProfile(profileName string) bool {
 	return true
 }
 
+// RegisterEndpoint Function
+func (se *SELinuxEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
+	for k, v := range endPoint.SELinuxProfiles {
+		if strings.HasPrefix(k, "selinux-") { // selinux- + [container_name]
+			containerName := strings.Split(k, "selinux-")[1]
+			if action == "ADDED" {
+				if !se.RegisterSELinuxProfile(containerName, endPoint.HostVolumes, v) {
+					mt.EnforcerFailures.WithLabelValues(se.Name(), "register").Inc()
+				}
+			} else if action == "DELETED" {
+				if !se.UnregisterSELinuxProfile(v) {
+					mt.EnforcerFailures.WithLabelValues(se.Name(), "unregister").Inc()
+				}
+			}
+		}
+	}
+}
+
+// Status Function
+func (se *SELinuxEnforcer) Status() map[string]string {
+	return map[string]string{"profiles": strconv.Itoa(se.GetProfileCount())}
+}
+
 // ================================= //
 // == Security Policy Enforcement == //
 // ================================= //
@@ -449,7 +523,7 @@ func (se *SELinuxEnforcer) UpdateSELinuxProfile(endPoint tp.EndPoint, seLinuxPro
 			return
 		}
 
-		if err := kl.RunCommandAndWaitWithErr("semanage", []string{"module", "-a", se.SELinuxContextTemplates + seLinuxProfile + ".cil"}); err == nil {
+		if err := reloadSELinuxProfile([]string{"module", "-a", se.SELinuxContextTemplates + seLinuxProfile + ".cil"}); err == nil {
 			se.Logger.Printf("Updated %d security rule(s) to %s/%s/%s", ruleCount, endPoint.NamespaceName, endPoint.EndPointName, seLinuxProfile)
 		} else {
 			se.Logger.Errf("Failed to update %d security rule(s) to %s/%s/%s (%s)", ruleCount, endPoint.NamespaceName, endPoint.EndPointName, seLinuxProfile, err.Error())
@@ -7,9 +7,13 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 )
 
@@ -65,3 +69,51 @@ func TestSELinuxEnforcer(t *testing.T) {
 	}
 	t.Log("[PASS] Destroyed logger")
 }
+
+func TestSELinuxRegisterEndpoint(t *testing.T) {
+	// node
+	node := tp.Node{}
+	node.NodeName = "nodeName"
+	node.NodeIP = "nodeIP"
+
+	// create logger
+	logger := feeder.NewFeeder("Default", &node, "32767", "none")
+	if logger == nil {
+		t.Log("[FAIL] Failed to create logger")
+		return
+	}
+	defer func() { _ = logger.DestroyFeeder() }()
+
+	// use a temporary template directory without semanage
+	templates, err := ioutil.TempDir("", "selinux")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer func() { _ = os.RemoveAll(templates) }()
+
+	prevCommand := semanageCommand
+	semanageCommand = "true"
+	defer func() { semanageCommand = prevCommand }()
+
+	se := &SELinuxEnforcer{Logger: logger, SELinuxProfiles: map[string]int{}, SELinuxProfilesLock: &sync.Mutex{}, SELinuxContextTemplates: templates + "/"}
+
+	failures := mt.EnforcerFailures.WithLabelValues(se.Name(), "register")
+	prevFailures := testutil.ToFloat64(failures)
+
+	// a pod registered twice (e.g., restarted KubeArmor)
+	endPoint := Endpoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", SELinuxProfiles: map[string]string{"selinux-ubuntu-1-container": "multiubuntu-ubuntu-1-container"}}
+	se.RegisterEndpoint("ADDED", endPoint)
+	se.RegisterEndpoint("ADDED", endPoint)
+
+	if se.GetProfileCount() != 1 {
+		t.Errorf("[FAIL] Failed to register a SELinux profile (%v)", se.SELinuxProfiles)
+		return
+	}
+
+	if count := testutil.ToFloat64(failures); count != prevFailures {
+		t.Errorf("[FAIL] Counted %v failures for an existing SELinux profile", count-prevFailures)
+		return
+	}
+	t.Log("[PASS] Registered an existing SELinux profile without failures")
+}
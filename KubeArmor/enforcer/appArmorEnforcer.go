@@ -4,15 +4,19 @@
 package enforcer
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
 	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 )
 
@@ -24,6 +28,7 @@ import (
 type AppArmorEnforcer struct {
 	// host
 	HostName string
+	Arch     string
 
 	// options
 	EnableKubeArmorPolicy     bool
@@ -47,8 +52,29 @@ type AppArmorEnforcer struct {
 func NewAppArmorEnforcer(node tp.Node, logger *fd.Feeder) *AppArmorEnforcer {
 	ae := &AppArmorEnforcer{}
 
+	if err := ae.Init(node, logger); err != nil {
+		logger.Errf("Failed to initialize AppArmor Enforcer (%s)", err.Error())
+		return nil
+	}
+
+	return ae
+}
+
+// Name Function
+func (ae *AppArmorEnforcer) Name() string {
+	return "AppArmor"
+}
+
+// Capabilities Function
+func (ae *AppArmorEnforcer) Capabilities() uint32 {
+	return EnforcerCapContainerPolicy | EnforcerCapHostPolicy | EnforcerCapAppArmorProfiles
+}
+
+// Init Function
+func (ae *AppArmorEnforcer) Init(node tp.Node, logger *fd.Feeder) error {
 	// host
 	ae.HostName = node.NodeName
+	ae.Arch = kl.GetArchitecture(node.Architecture)
 
 	// options
 	ae.EnableKubeArmorPolicy = node.EnableKubeArmorPolicy
@@ -75,7 +101,7 @@ func NewAppArmorEnforcer(node tp.Node, logger *fd.Feeder) *AppArmorEnforcer {
 		"  ## == POLICY END == ##\n" +
 		"\n" +
 		"  ## == POST START == ##\n" +
-		"  /lib/x86_64-linux-gnu/{*,**} rm,\n" +
+		"  " + GetLibraryPath(ae.Arch) + "/{*,**} rm,\n" +
 		"\n" +
 		"  deny @{PROC}/{*,**^[0-9*],sys/kernel/shm*} wkx,\n" +
 		"  deny @{PROC}/sysrq-trigger rwklx,\n" +
@@ -104,8 +130,7 @@ func NewAppArmorEnforcer(node tp.Node, logger *fd.Feeder) *AppArmorEnforcer {
 
 	files, err := ioutil.ReadDir("/etc/apparmor.d")
 	if err != nil {
-		ae.Logger.Errf("Failed to read /etc/apparmor.d (%s)", err.Error())
-		return nil
+		return fmt.Errorf("failed to read /etc/apparmor.d (%s)", err.Error())
 	}
 
 	existingProfiles := []string{}
@@ -163,11 +188,16 @@ func NewAppArmorEnforcer(node tp.Node, logger *fd.Feeder) *AppArmorEnforcer {
 
 	if ae.EnableKubeArmorHostPolicy {
 		if ok := ae.RegisterAppArmorHostProfile(); !ok {
-			return nil
+			return errors.New("failed to register the host profile")
 		}
 	}
 
-	return ae
+	return nil
+}
+
+// Destroy Function
+func (ae *AppArmorEnforcer) Destroy() error {
+	return ae.DestroyAppArmorEnforcer()
 }
 
 // DestroyAppArmorEnforcer Function
@@ -192,6 +222,14 @@ func (ae *AppArmorEnforcer) DestroyAppArmorEnforcer() error {
 // == AppArmor Profile Management == //
 // ================================= //
 
+// reloadAppArmorProfile Function
+func reloadAppArmorProfile(args []string) error {
+	start := time.Now()
+	err := kl.RunCommandAndWaitWithErr("apparmor_parser", args)
+	mt.ObserveProfileReload("AppArmor", start, err)
+	return err
+}
+
 // RegisterAppArmorProfile Function
 func (ae *AppArmorEnforcer) RegisterAppArmorProfile(profileName string) bool {
 	// skip if AppArmorEnforcer is not active
@@ -233,7 +271,7 @@ func (ae *AppArmorEnforcer) RegisterAppArmorProfile(profileName string) bool {
 		return false
 	}
 
-	if err := kl.RunCommandAndWaitWithErr("apparmor_parser", []string{"-r", "-W", "/etc/apparmor.d/" + profileName}); err == nil {
+	if err := reloadAppArmorProfile([]string{"-r", "-W", "/etc/apparmor.d/" + profileName}); err == nil {
 		if _, ok := ae.AppArmorProfiles[profileName]; !ok {
 			ae.AppArmorProfiles[profileName] = 1
 			ae.Logger.Printf("Registered an AppArmor profile (%s)", profileName)
@@ -246,6 +284,14 @@ func (ae *AppArmorEnforcer) RegisterAppArmorProfile(profileName string) bool {
 	return true
 }
 
+// GetProfileCount Function
+func (ae *AppArmorEnforcer) GetProfileCount() int {
+	ae.AppArmorProfilesLock.Lock()
+	defer ae.AppArmorProfilesLock.Unlock()
+
+	return len(ae.AppArmorProfiles)
+}
+
 // UnregisterAppArmorProfile Function
 func (ae *AppArmorEnforcer) UnregisterAppArmorProfile(profileName string) bool {
 	// skip if AppArmorEnforcer is not active
@@ -287,7 +333,7 @@ func (ae *AppArmorEnforcer) UnregisterAppArmorProfile(profileName string) bool {
 		return false
 	}
 
-	if err := kl.RunCommandAndWaitWithErr("apparmor_parser", []string{"-r", "-W", "/etc/apparmor.d/" + profileName}); err != nil {
+	if err := reloadAppArmorProfile([]string{"-r", "-W", "/etc/apparmor.d/" + profileName}); err != nil {
 		ae.Logger.Errf("Failed to unregister an AppArmor profile (%s, %s)", profileName, err.Error())
 		return false
 	}
@@ -299,6 +345,26 @@ func (ae *AppArmorEnforcer) UnregisterAppArmorProfile(profileName string) bool {
 	return true
 }
 
+// RegisterEndpoint Function
+func (ae *AppArmorEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
+	for _, profile := range endPoint.AppArmorProfiles {
+		if action == "ADDED" {
+			if !ae.RegisterAppArmorProfile(profile) {
+				mt.EnforcerFailures.WithLabelValues(ae.Name(), "register").Inc()
+			}
+		} else if action == "DELETED" {
+			if !ae.UnregisterAppArmorProfile(profile) {
+				mt.EnforcerFailures.WithLabelValues(ae.Name(), "unregister").Inc()
+			}
+		}
+	}
+}
+
+// Status Function
+func (ae *AppArmorEnforcer) Status() map[string]string {
+	return map[string]string{"profiles": strconv.Itoa(ae.GetProfileCount())}
+}
+
 // ====================================== //
 // == AppArmor Host Profile Management == //
 // ====================================== //
@@ -384,7 +450,7 @@ func (ae *AppArmorEnforcer) RegisterAppArmorHostProfile() bool {
 		return false
 	}
 
-	if err := kl.RunCommandAndWaitWithErr("apparmor_parser", []string{"-r", "-W", "-C", "/etc/apparmor.d/kubearmor.host"}); err == nil {
+	if err := reloadAppArmorProfile([]string{"-r", "-W", "-C", "/etc/apparmor.d/kubearmor.host"}); err == nil {
 		ae.Logger.Printf("Registered the KubeArmor host profile in %s", ae.HostName)
 	} else {
 		ae.Logger.Errf("Failed to registered the KubeArmor host profile in %s (%s)", ae.HostName, err.Error())
@@ -437,7 +503,7 @@ func (ae *AppArmorEnforcer) UpdateAppArmorProfile(endPoint tp.EndPoint, appArmor
 			return
 		}
 
-		if err := kl.RunCommandAndWaitWithErr("apparmor_parser", []string{"-r", "-W", "/etc/apparmor.d/" + appArmorProfile}); err == nil {
+		if err := reloadAppArmorProfile([]string{"-r", "-W", "/etc/apparmor.d/" + appArmorProfile}); err == nil {
 			ae.Logger.Printf("Updated %d security rules to %s/%s/%s", policyCount, endPoint.NamespaceName, endPoint.EndPointName, appArmorProfile)
 		} else {
 			ae.Logger.Printf("Failed to update %d security rules to %s/%s/%s (%s)", policyCount, endPoint.NamespaceName, endPoint.EndPointName, appArmorProfile, err.Error())
@@ -503,7 +569,7 @@ func (ae *AppArmorEnforcer) UpdateAppArmorHostProfile(secPolicies []tp.HostSecur
 			return
 		}
 
-		if err := kl.RunCommandAndWaitWithErr("apparmor_parser", []string{"-r", "-W", "/etc/apparmor.d/kubearmor.host"}); err == nil {
+		if err := reloadAppArmorProfile([]string{"-r", "-W", "/etc/apparmor.d/kubearmor.host"}); err == nil {
 			ae.Logger.Printf("Updated %d host security rules to the KubeArmor host profile in %s", policyCount, ae.HostName)
 		} else {
 			ae.Logger.Errf("Failed to update %d host security rules to the KubeArmor host profile in %s (%s)", policyCount, ae.HostName, err.Error())
@@ -984,6 +984,9 @@ func GenerateHostProfileBody(securityPolicies []tp.HostSecurityPolicy) (int, str
 					allowedHostProcessMatchPaths(path, fromSources)
 				} else if path.Action == "Audit" {
 					auditedHostProcessMatchPaths(path, &processAuditList, fromSources)
+				} else if path.Action == "Block" && len(path.Args) > 0 {
+					// AppArmor cannot see arguments, thus audit the executable instead of blocking it
+					auditedHostProcessMatchPaths(path, &processAuditList, fromSources)
 				} else if path.Action == "Block" {
 					blockedHostProcessMatchPaths(path, &processBlackList, fromSources)
 				}
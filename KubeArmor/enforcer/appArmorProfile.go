@@ -1125,9 +1125,25 @@ func GenerateProfileHead(processWhiteList, fileWhiteList, networkWhiteList, capa
 	return profileHead
 }
 
+// appArmorLibraryPaths for the supported architectures
+var appArmorLibraryPaths = map[string]string{
+	"amd64": "/lib/x86_64-linux-gnu",
+	"arm64": "/lib/aarch64-linux-gnu",
+}
+
+// GetLibraryPath Function
+func GetLibraryPath(arch string) string {
+	if path, ok := appArmorLibraryPaths[kl.GetArchitecture(arch)]; ok {
+		return path
+	}
+
+	// any multiarch directory for the others
+	return "/lib/*-linux-gnu*"
+}
+
 // GenerateProfileFoot Function
-func GenerateProfileFoot() string {
-	profileFoot := "  /lib/x86_64-linux-gnu/{*,**} rm,\n"
+func GenerateProfileFoot(arch string) string {
+	profileFoot := "  " + GetLibraryPath(arch) + "/{*,**} rm,\n"
 	profileFoot = profileFoot + "\n"
 	profileFoot = profileFoot + "  deny @{PROC}/{*,**^[0-9*],sys/kernel/shm*} wkx,\n"
 	profileFoot = profileFoot + "  deny @{PROC}/sysrq-trigger rwklx,\n"
@@ -1151,7 +1167,7 @@ func GenerateProfileFoot() string {
 // == //
 
 // GenerateProfileBody Function
-func GenerateProfileBody(securityPolicies []tp.SecurityPolicy) (int, string) {
+func GenerateProfileBody(securityPolicies []tp.SecurityPolicy, arch string) (int, string) {
 	// preparation
 
 	count := 0
@@ -1193,6 +1209,9 @@ func GenerateProfileBody(securityPolicies []tp.SecurityPolicy) (int, string) {
 					allowedProcessMatchPaths(path, &processWhiteList, fromSources)
 				} else if path.Action == "Audit" {
 					auditedProcessMatchPaths(path, &processAuditList, fromSources)
+				} else if path.Action == "Block" && len(path.Args) > 0 {
+					// AppArmor cannot see arguments, thus audit the executable instead of blocking it
+					auditedProcessMatchPaths(path, &processAuditList, fromSources)
 				} else if path.Action == "Block" {
 					blockedProcessMatchPaths(path, &processBlackList, fromSources)
 				}
@@ -1430,7 +1449,7 @@ func GenerateProfileBody(securityPolicies []tp.SecurityPolicy) (int, string) {
 
 		bodyFromSource = bodyFromSource + fmt.Sprintf("    ## == POST START (%s) == ##\n", source)
 
-		bodyFromSource = bodyFromSource + strings.Replace(GenerateProfileFoot(), "  ", "    ", -1)
+		bodyFromSource = bodyFromSource + strings.Replace(GenerateProfileFoot(arch), "  ", "    ", -1)
 
 		bodyFromSource = bodyFromSource + fmt.Sprintf("    ## == POST END (%s) == ##\n", source)
 		bodyFromSource = bodyFromSource + "  }\n"
@@ -1458,7 +1477,7 @@ func GenerateProfileBody(securityPolicies []tp.SecurityPolicy) (int, string) {
 
 	// foot
 
-	profileFoot := "  ## == POST START == ##\n" + GenerateProfileFoot() + "  ## == POST END == ##\n"
+	profileFoot := "  ## == POST START == ##\n" + GenerateProfileFoot(arch) + "  ## == POST END == ##\n"
 
 	// finalization
 
@@ -1485,7 +1504,7 @@ func (ae *AppArmorEnforcer) GenerateAppArmorProfile(appArmorProfile string, secu
 
 	// generate a profile body
 
-	count, newProfileBody := GenerateProfileBody(securityPolicies)
+	count, newProfileBody := GenerateProfileBody(securityPolicies, ae.Arch)
 
 	newProfile := "## == Managed by KubeArmor == ##\n" +
 		"\n" +
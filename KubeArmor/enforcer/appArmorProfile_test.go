@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"strings"
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func TestGenerateProfileFoot(t *testing.T) {
+	tests := []struct {
+		arch    string
+		libPath string
+	}{
+		{arch: "amd64", libPath: "/lib/x86_64-linux-gnu/{*,**} rm,"},
+		{arch: "x86_64", libPath: "/lib/x86_64-linux-gnu/{*,**} rm,"},
+		{arch: "arm64", libPath: "/lib/aarch64-linux-gnu/{*,**} rm,"},
+		{arch: "aarch64", libPath: "/lib/aarch64-linux-gnu/{*,**} rm,"},
+		{arch: "riscv64", libPath: "/lib/*-linux-gnu*/{*,**} rm,"},
+	}
+
+	secPolicy := tp.SecurityPolicy{}
+	secPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/bash", Action: "Block"}}
+
+	for _, test := range tests {
+		if foot := GenerateProfileFoot(test.arch); !strings.HasPrefix(foot, "  "+test.libPath+"\n") {
+			t.Errorf("[FAIL] Got an unexpected profile foot for %s (%s)", test.arch, strings.Split(foot, "\n")[0])
+			return
+		}
+
+		_, body := GenerateProfileBody([]tp.SecurityPolicy{secPolicy}, test.arch)
+
+		if !strings.Contains(body, test.libPath) {
+			t.Errorf("[FAIL] Failed to find %s in a profile for %s", test.libPath, test.arch)
+			return
+		}
+
+		t.Logf("[PASS] Generated a profile for %s", test.arch)
+	}
+}
@@ -0,0 +1,685 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/perf"
+	"golang.org/x/sys/unix"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// BPFEnforcerObjectName is the CO-RE object of the BPF-LSM enforcer (built by BPF/Makefile)
+const BPFEnforcerObjectName = "enforcer.bpf.o"
+
+// BPF Maps (the same as the ones in BPF/enforcer.bpf.c)
+const (
+	BPFContainersMapName = "enforcer_containers"
+	BPFRulesMapName      = "enforcer_rules"
+	BPFNetRulesMapName   = "enforcer_net_rules"
+	BPFAlertsMapName     = "enforcer_alerts"
+)
+
+// BPF Rule Types (the same as RULE_* in BPF/enforcer.bpf.c)
+const (
+	BPFRuleProcess uint32 = 1
+	BPFRuleFile    uint32 = 2
+	BPFRuleNetwork uint32 = 3
+)
+
+// BPF Rule Flags
+const (
+	BPFRuleBlock    uint32 = 0x1
+	BPFRuleReadOnly uint32 = 0x2
+)
+
+// BPFMaxPathLen is the size of paths in rules (including the trailing NUL)
+const BPFMaxPathLen = 256
+
+// BPF-LSM programs (bprm_check_security, file_open and socket_connect)
+var bpfEnforcerPrograms = []string{"enforce_proc", "enforce_file", "enforce_net"}
+
+// protocol name -> IPPROTO_* (raw matches any SOCK_RAW socket)
+var bpfProtocols = map[string]uint32{
+	"icmp":   1,
+	"tcp":    6,
+	"udp":    17,
+	"icmpv6": 58,
+	"raw":    255,
+}
+
+// ================== //
+// == BPF Enforcer == //
+// ================== //
+
+// BPFRule Structure
+type BPFRule struct {
+	Type     uint32
+	Path     string
+	Protocol uint32
+}
+
+// bpfRuleKey Structure (rule_key_t)
+type bpfRuleKey struct {
+	MntNS uint32
+	Type  uint32
+	Path  [BPFMaxPathLen]byte
+}
+
+// bpfNetKey Structure (net_key_t)
+type bpfNetKey struct {
+	MntNS    uint32
+	Protocol uint32
+}
+
+// BPFAlert Structure (alert_t)
+type BPFAlert struct {
+	Timestamp uint64
+
+	PidNS uint32
+	MntNS uint32
+
+	HostPID uint32
+	PPID    uint32
+	PID     uint32
+	UID     uint32
+
+	Type  uint32
+	Flags uint32
+
+	Family   uint32
+	SockType uint32
+	Protocol uint32
+
+	Retval int32
+
+	Comm [16]byte
+	Path [BPFMaxPathLen]byte
+}
+
+// BPFEnforcer Structure
+type BPFEnforcer struct {
+	// logs
+	Logger *fd.Feeder
+
+	// BPF-LSM programs
+	Collection *ebpf.Collection
+	Links      []int
+	Reader     *perf.Reader
+
+	// container id -> container (with its mount namespace)
+	Containers map[string]tp.Container
+
+	// container id -> rules
+	ContainerRules map[string]map[BPFRule]uint32
+
+	// mount namespace -> rules in the kernel
+	KernelRules map[uint32]map[BPFRule]uint32
+
+	ContainersLock *sync.RWMutex
+}
+
+// NewBPFEnforcer Function
+func NewBPFEnforcer(logger *fd.Feeder) *BPFEnforcer {
+	be := &BPFEnforcer{}
+
+	if err := be.Init(tp.Node{}, logger); err != nil {
+		logger.Errf("Failed to initialize BPF-LSM Enforcer (%s)", err.Error())
+		return nil
+	}
+
+	return be
+}
+
+// Name Function
+func (be *BPFEnforcer) Name() string {
+	return "BPFLSM"
+}
+
+// Capabilities Function
+func (be *BPFEnforcer) Capabilities() uint32 {
+	return EnforcerCapContainerPolicy | EnforcerCapContainerNamespaces
+}
+
+// Init Function
+func (be *BPFEnforcer) Init(node tp.Node, logger *fd.Feeder) error {
+	be.Logger = logger
+
+	be.Containers = map[string]tp.Container{}
+	be.ContainerRules = map[string]map[BPFRule]uint32{}
+	be.KernelRules = map[uint32]map[BPFRule]uint32{}
+	be.ContainersLock = new(sync.RWMutex)
+
+	if _, err := os.Stat("/sys/kernel/btf/vmlinux"); err != nil {
+		return fmt.Errorf("failed to find the kernel BTF (%s)", err.Error())
+	}
+
+	objPath := "/KubeArmor/BPF/" + BPFEnforcerObjectName
+
+	if kl.IsK8sLocal() {
+		if ex, err := os.Executable(); err == nil {
+			objPath = filepath.Dir(ex) + "/BPF/" + BPFEnforcerObjectName
+		}
+	}
+
+	if err := be.loadPrograms(objPath); err != nil {
+		be.closePrograms()
+		return err
+	}
+
+	go be.TraceAlerts()
+
+	return nil
+}
+
+// loadPrograms Function
+func (be *BPFEnforcer) loadPrograms(objPath string) error {
+	spec, err := ebpf.LoadCollectionSpec(filepath.Clean(objPath))
+	if err != nil {
+		return fmt.Errorf("error loading %s: %v", objPath, err)
+	}
+
+	be.Collection, err = ebpf.NewCollection(spec)
+	if err != nil {
+		return fmt.Errorf("error loading %s into the kernel: %v", objPath, err)
+	}
+
+	for _, progName := range bpfEnforcerPrograms {
+		prog, ok := be.Collection.Programs[progName]
+		if !ok {
+			return fmt.Errorf("error loading %s: not found", progName)
+		}
+
+		link, err := attachLSM(prog)
+		if err != nil {
+			return fmt.Errorf("error attaching %s: %v", progName, err)
+		}
+
+		be.Links = append(be.Links, link)
+	}
+
+	alertsMap, ok := be.Collection.Maps[BPFAlertsMapName]
+	if !ok {
+		return fmt.Errorf("%s is not found", BPFAlertsMapName)
+	}
+
+	be.Reader, err = perf.NewReader(alertsMap, os.Getpagesize()*64)
+	if err != nil {
+		return fmt.Errorf("error initializing alerts perf buffer: %v", err)
+	}
+
+	return nil
+}
+
+// attachLSM Function (BPF_RAW_TRACEPOINT_OPEN without a name attaches an LSM program to its hook)
+func attachLSM(prog *ebpf.Program) (int, error) {
+	attr := struct {
+		name   uint64
+		progFD uint32
+		_      uint32
+	}{progFD: uint32(prog.FD())}
+
+	link, _, errno := unix.Syscall(unix.SYS_BPF, unix.BPF_RAW_TRACEPOINT_OPEN, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr))
+	if errno != 0 {
+		return -1, errno
+	}
+
+	return int(link), nil
+}
+
+// closePrograms Function
+func (be *BPFEnforcer) closePrograms() {
+	if be.Reader != nil {
+		_ = be.Reader.Close()
+	}
+
+	for _, link := range be.Links {
+		_ = unix.Close(link)
+	}
+	be.Links = nil
+
+	if be.Collection != nil {
+		be.Collection.Close()
+	}
+}
+
+// DestroyBPFEnforcer Function
+func (be *BPFEnforcer) DestroyBPFEnforcer() error {
+	// skip if BPF enforcer is not active
+	if be == nil {
+		return nil
+	}
+
+	// detaching the programs drops all the rules in the kernel
+	be.closePrograms()
+
+	return nil
+}
+
+// Destroy Function
+func (be *BPFEnforcer) Destroy() error {
+	return be.DestroyBPFEnforcer()
+}
+
+// ===================== //
+// == BPF Rule Update == //
+// ===================== //
+
+// addBPFRule Function
+func addBPFRule(rules map[BPFRule]uint32, rule BPFRule, flags uint32) {
+	// blocking every access wins over blocking writes only
+	if old, ok := rules[rule]; ok && old&BPFRuleReadOnly == 0 {
+		return
+	}
+	rules[rule] = flags
+}
+
+// isBPFRulePath Function
+func isBPFRulePath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasSuffix(path, "/") && len(path) < BPFMaxPathLen
+}
+
+// isBPFRuleEnforceable Function
+func isBPFRuleEnforceable(rule interface{}) bool {
+	// allow rules, rules with fromSource, ownerOnly or args, directories and patterns are left to the system monitor
+	switch r := rule.(type) {
+	case tp.ProcessPathType:
+		return r.Action == "Block" && len(r.FromSource) == 0 && !r.OwnerOnly && len(r.Args) == 0 && isBPFRulePath(r.Path)
+	case tp.FilePathType:
+		return r.Action == "Block" && len(r.FromSource) == 0 && !r.OwnerOnly && isBPFRulePath(r.Path)
+	case tp.NetworkProtocolType:
+		_, ok := bpfProtocols[strings.ToLower(r.Protocol)]
+		return r.Action == "Block" && len(r.FromSource) == 0 && ok
+	}
+
+	return false
+}
+
+// IsEnforceable Function
+func (be *BPFEnforcer) IsEnforceable(rule interface{}) bool {
+	return isBPFRuleEnforceable(rule)
+}
+
+// GenerateBPFRules Function
+func GenerateBPFRules(secPolicies []tp.SecurityPolicy) map[BPFRule]uint32 {
+	rules := map[BPFRule]uint32{}
+
+	for _, secPolicy := range secPolicies {
+		for _, path := range secPolicy.Spec.Process.MatchPaths {
+			if !isBPFRuleEnforceable(path) {
+				continue
+			}
+			addBPFRule(rules, BPFRule{Type: BPFRuleProcess, Path: path.Path}, BPFRuleBlock)
+		}
+
+		for _, path := range secPolicy.Spec.File.MatchPaths {
+			if !isBPFRuleEnforceable(path) {
+				continue
+			}
+
+			if path.ReadOnly {
+				addBPFRule(rules, BPFRule{Type: BPFRuleFile, Path: path.Path}, BPFRuleBlock|BPFRuleReadOnly)
+			} else {
+				addBPFRule(rules, BPFRule{Type: BPFRuleFile, Path: path.Path}, BPFRuleBlock)
+			}
+		}
+
+		for _, proto := range secPolicy.Spec.Network.MatchProtocols {
+			if !isBPFRuleEnforceable(proto) {
+				continue
+			}
+			addBPFRule(rules, BPFRule{Type: BPFRuleNetwork, Protocol: bpfProtocols[strings.ToLower(proto.Protocol)]}, BPFRuleBlock)
+		}
+	}
+
+	return rules
+}
+
+// putBPFRule Function
+func (be *BPFEnforcer) putBPFRule(mntns uint32, rule BPFRule, flags uint32, delete bool) error {
+	if be.Collection == nil {
+		return nil
+	}
+
+	mapName := BPFRulesMapName
+	if rule.Type == BPFRuleNetwork {
+		mapName = BPFNetRulesMapName
+	}
+
+	rulesMap, ok := be.Collection.Maps[mapName]
+	if !ok {
+		return fmt.Errorf("%s is not found", mapName)
+	}
+
+	var key interface{}
+
+	if rule.Type == BPFRuleNetwork {
+		key = bpfNetKey{MntNS: mntns, Protocol: rule.Protocol}
+	} else {
+		ruleKey := bpfRuleKey{MntNS: mntns, Type: rule.Type}
+		copy(ruleKey.Path[:], rule.Path)
+		key = ruleKey
+	}
+
+	if delete {
+		return rulesMap.Delete(key)
+	}
+	return rulesMap.Put(key, flags)
+}
+
+// putBPFRuleTypes Function
+func (be *BPFEnforcer) putBPFRuleTypes(mntns uint32, rules map[BPFRule]uint32) error {
+	if be.Collection == nil {
+		return nil
+	}
+
+	containersMap, ok := be.Collection.Maps[BPFContainersMapName]
+	if !ok {
+		return fmt.Errorf("%s is not found", BPFContainersMapName)
+	}
+
+	if len(rules) == 0 {
+		if err := containersMap.Delete(mntns); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	types := uint32(0)
+	for rule := range rules {
+		types |= 1 << rule.Type
+	}
+
+	return containersMap.Put(mntns, types)
+}
+
+// updateKernelRules Function (ContainersLock should be held)
+func (be *BPFEnforcer) updateKernelRules(mntns uint32, rules map[BPFRule]uint32) {
+	if mntns == 0 {
+		return
+	}
+
+	oldRules := be.KernelRules[mntns]
+	newRules := map[BPFRule]uint32{}
+
+	failed := false
+
+	for rule := range oldRules {
+		if _, ok := rules[rule]; !ok {
+			if err := be.putBPFRule(mntns, rule, 0, true); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+				be.Logger.Warnf("Failed to delete a BPF-LSM rule (%s)", err.Error())
+				failed = true
+			}
+		}
+	}
+
+	for rule, flags := range rules {
+		if oldFlags, ok := oldRules[rule]; !ok || oldFlags != flags {
+			if err := be.putBPFRule(mntns, rule, flags, false); err != nil {
+				be.Logger.Warnf("Failed to add a BPF-LSM rule (%s)", err.Error())
+				failed = true
+				continue
+			}
+		}
+		newRules[rule] = flags
+	}
+
+	if err := be.putBPFRuleTypes(mntns, newRules); err != nil {
+		be.Logger.Warnf("Failed to update the BPF-LSM rules of a container (%s)", err.Error())
+		failed = true
+	}
+
+	if failed {
+		mt.EnforcerFailures.WithLabelValues("BPFLSM", "register").Inc()
+	}
+
+	if len(newRules) == 0 {
+		delete(be.KernelRules, mntns)
+	} else {
+		be.KernelRules[mntns] = newRules
+	}
+}
+
+// RegisterContainer Function
+func (be *BPFEnforcer) RegisterContainer(container tp.Container) {
+	if container.MntNS == 0 {
+		return
+	}
+
+	be.ContainersLock.Lock()
+	defer be.ContainersLock.Unlock()
+
+	// keep the endpoint given by policy updates
+	if old, ok := be.Containers[container.ContainerID]; ok && old.EndPointName != "" {
+		container.NamespaceName = old.NamespaceName
+		container.EndPointName = old.EndPointName
+		container.PolicyEnabled = old.PolicyEnabled
+	}
+
+	be.Containers[container.ContainerID] = container
+
+	if rules, ok := be.ContainerRules[container.ContainerID]; ok {
+		be.updateKernelRules(container.MntNS, rules)
+	}
+}
+
+// UnregisterContainer Function
+func (be *BPFEnforcer) UnregisterContainer(containerID string) {
+	be.ContainersLock.Lock()
+	defer be.ContainersLock.Unlock()
+
+	if container, ok := be.Containers[containerID]; ok {
+		be.updateKernelRules(container.MntNS, map[BPFRule]uint32{})
+		delete(be.Containers, containerID)
+	}
+
+	delete(be.ContainerRules, containerID)
+}
+
+// RegisterEndpoint Function
+func (be *BPFEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
+	for _, container := range endPoint.Containers {
+		if action == "ADDED" {
+			be.RegisterContainer(container)
+		} else if action == "DELETED" {
+			be.UnregisterContainer(container.ContainerID)
+		}
+	}
+}
+
+// UpdateSecurityPolicies Function
+func (be *BPFEnforcer) UpdateSecurityPolicies(endPoint tp.EndPoint) {
+	// skip if BPF enforcer is not active
+	if be == nil {
+		return
+	}
+
+	rules := map[BPFRule]uint32{}
+
+	if endPoint.PolicyEnabled == tp.KubeArmorPolicyEnabled {
+		rules = GenerateBPFRules(endPoint.SecurityPolicies)
+	}
+
+	be.ContainersLock.Lock()
+	defer be.ContainersLock.Unlock()
+
+	for _, containerID := range endPoint.Containers {
+		be.ContainerRules[containerID] = rules
+
+		// containers not registered yet have no mount namespace
+		container := be.Containers[containerID]
+
+		container.ContainerID = containerID
+		container.NamespaceName = endPoint.NamespaceName
+		container.EndPointName = endPoint.EndPointName
+		container.PolicyEnabled = endPoint.PolicyEnabled
+
+		be.Containers[containerID] = container
+
+		be.updateKernelRules(container.MntNS, rules)
+	}
+}
+
+// UpdateHostSecurityPolicies Function
+func (be *BPFEnforcer) UpdateHostSecurityPolicies(secPolicies []tp.HostSecurityPolicy) {
+	// host security policies are not enforced by BPF-LSM yet
+}
+
+// Status Function
+func (be *BPFEnforcer) Status() map[string]string {
+	return map[string]string{"rules": strconv.Itoa(be.GetRuleCount())}
+}
+
+// GetRuleCount Function
+func (be *BPFEnforcer) GetRuleCount() int {
+	be.ContainersLock.RLock()
+	defer be.ContainersLock.RUnlock()
+
+	count := 0
+	for _, rules := range be.KernelRules {
+		count += len(rules)
+	}
+
+	return count
+}
+
+// ================ //
+// == BPF Alerts == //
+// ================ //
+
+// bpfString Function
+func bpfString(str []byte) string {
+	if idx := bytes.IndexByte(str, 0); idx >= 0 {
+		return string(str[:idx])
+	}
+	return string(str)
+}
+
+// hostByteOrder Function
+func hostByteOrder() binary.ByteOrder {
+	one := uint16(1)
+	if *(*byte)(unsafe.Pointer(&one)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// BuildAlertLog Function
+func (be *BPFEnforcer) BuildAlertLog(alert BPFAlert) tp.Log {
+	log := tp.Log{}
+
+	timestamp, updatedTime := kl.GetDateTimeNow()
+
+	log.Timestamp = timestamp
+	log.UpdatedTime = updatedTime
+
+	be.ContainersLock.RLock()
+	for _, container := range be.Containers {
+		if container.MntNS != 0 && container.MntNS == alert.MntNS {
+			log.ContainerID = container.ContainerID
+			log.ContainerName = container.ContainerName
+
+			log.NamespaceName = container.NamespaceName
+			log.PodName = container.EndPointName
+
+			log.PolicyEnabled = container.PolicyEnabled
+			break
+		}
+	}
+	be.ContainersLock.RUnlock()
+
+	log.HostPID = int32(alert.HostPID)
+	log.PPID = int32(alert.PPID)
+	log.PID = int32(alert.PID)
+	log.UID = int32(alert.UID)
+
+	log.Source = bpfString(alert.Comm[:])
+
+	switch alert.Type {
+	case BPFRuleProcess:
+		log.Operation = "Process"
+		log.Resource = bpfString(alert.Path[:])
+		log.Data = "lsm=bprm_check_security"
+
+	case BPFRuleFile:
+		log.Operation = "File"
+		log.Resource = bpfString(alert.Path[:])
+		log.Data = "lsm=file_open"
+
+	case BPFRuleNetwork:
+		family := "AF_INET"
+		if alert.Family == unix.AF_INET6 {
+			family = "AF_INET6"
+		}
+
+		sockType := strconv.Itoa(int(alert.SockType))
+		switch alert.SockType {
+		case unix.SOCK_STREAM:
+			sockType = "SOCK_STREAM"
+		case unix.SOCK_DGRAM:
+			sockType = "SOCK_DGRAM"
+		case unix.SOCK_RAW:
+			sockType = "SOCK_RAW"
+		}
+
+		log.Operation = "Network"
+		log.Resource = "domain=" + family + " type=" + sockType + " protocol=" + strconv.Itoa(int(alert.Protocol))
+		log.Data = "lsm=socket_connect"
+	}
+
+	// denied by the enforcer regardless of which policy the feeder matches
+	log.Type = "MatchedPolicy"
+	log.Action = "Block"
+	log.Result = "Operation not permitted"
+
+	return log
+}
+
+// TraceAlerts Function
+func (be *BPFEnforcer) TraceAlerts() {
+	byteOrder := hostByteOrder()
+
+	for {
+		record, err := be.Reader.Read()
+		if err != nil {
+			if perf.IsClosed(err) {
+				return
+			}
+			continue
+		}
+
+		if record.LostSamples > 0 {
+			be.Logger.Warnf("Lost %d alerts in the BPF-LSM enforcer", record.LostSamples)
+			continue
+		}
+
+		alert := BPFAlert{}
+
+		if err := binary.Read(bytes.NewReader(record.RawSample), byteOrder, &alert); err != nil {
+			be.Logger.Warnf("Failed to decode an alert of the BPF-LSM enforcer (%s)", err.Error())
+			continue
+		}
+
+		be.Logger.PushLog(be.BuildAlertLog(alert))
+	}
+}
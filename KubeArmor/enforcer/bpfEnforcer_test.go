@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func newTestBPFEnforcer() *BPFEnforcer {
+	return &BPFEnforcer{
+		Containers:     map[string]tp.Container{},
+		ContainerRules: map[string]map[BPFRule]uint32{},
+		KernelRules:    map[uint32]map[BPFRule]uint32{},
+		ContainersLock: new(sync.RWMutex),
+	}
+}
+
+func newTestBPFPolicy() tp.SecurityPolicy {
+	secPolicy := tp.SecurityPolicy{}
+
+	secPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{
+		{Path: "/bin/bash", Action: "Block"},
+		{Path: "/bin/sleep", Action: "Block", Args: []string{"10"}},
+		{Path: "/bin/ls", Action: "Allow"},
+	}
+	secPolicy.Spec.File.MatchPaths = []tp.FilePathType{
+		{Path: "/etc/passwd", ReadOnly: true, Action: "Block"},
+		{Path: "/etc/shadow", Action: "Block", FromSource: []tp.MatchSourceType{{Path: "/bin/cat"}}},
+		{Path: "/secret.txt", Action: "Block"},
+		{Path: "/secret.txt", ReadOnly: true, Action: "Block"},
+	}
+	secPolicy.Spec.Network.MatchProtocols = []tp.NetworkProtocolType{
+		{Protocol: "TCP", Action: "Block"},
+		{Protocol: "sctp", Action: "Block"},
+	}
+
+	return secPolicy
+}
+
+func TestGenerateBPFRules(t *testing.T) {
+	rules := GenerateBPFRules([]tp.SecurityPolicy{newTestBPFPolicy()})
+
+	expected := map[BPFRule]uint32{
+		{Type: BPFRuleProcess, Path: "/bin/bash"}:   BPFRuleBlock,
+		{Type: BPFRuleFile, Path: "/etc/passwd"}:    BPFRuleBlock | BPFRuleReadOnly,
+		{Type: BPFRuleFile, Path: "/secret.txt"}:    BPFRuleBlock,
+		{Type: BPFRuleNetwork, Protocol: uint32(6)}: BPFRuleBlock,
+	}
+
+	if len(rules) != len(expected) {
+		t.Errorf("[FAIL] Generated unexpected rules (%v)", rules)
+		return
+	}
+
+	for rule, flags := range expected {
+		if rules[rule] != flags {
+			t.Errorf("[FAIL] Generated an unexpected rule (%+v: %d, expected %d)", rule, rules[rule], flags)
+			return
+		}
+	}
+	t.Log("[PASS] Generated BPF-LSM rules")
+
+	be := newTestBPFEnforcer()
+
+	if !be.IsEnforceable(tp.ProcessPathType{Path: "/bin/bash", Action: "Block"}) ||
+		be.IsEnforceable(tp.ProcessPathType{Path: "/bin/ls", Action: "Allow"}) ||
+		be.IsEnforceable(tp.FileDirectoryType{Directory: "/etc/", Action: "Block"}) ||
+		be.IsEnforceable(tp.FilePathType{Path: "/etc/shadow", Action: "Block", OwnerOnly: true}) {
+		t.Errorf("[FAIL] Failed to tell the rules that BPF-LSM can enforce")
+		return
+	}
+	t.Log("[PASS] Told the rules that BPF-LSM can enforce")
+}
+
+func TestBPFEnforcerRules(t *testing.T) {
+	be := newTestBPFEnforcer()
+
+	endPoint := tp.EndPoint{
+		NamespaceName:    "multiubuntu",
+		EndPointName:     "ubuntu-1",
+		Containers:       []string{"container-a", "container-b"},
+		SecurityPolicies: []tp.SecurityPolicy{newTestBPFPolicy()},
+		PolicyEnabled:    tp.KubeArmorPolicyEnabled,
+	}
+
+	// policies can come before and after containers
+	be.RegisterContainer(tp.Container{ContainerID: "container-a", MntNS: 4026532001})
+	be.UpdateSecurityPolicies(endPoint)
+	be.RegisterContainer(tp.Container{ContainerID: "container-b", MntNS: 4026532002})
+
+	if len(be.KernelRules[4026532001]) != 4 || len(be.KernelRules[4026532002]) != 4 || be.GetRuleCount() != 8 {
+		t.Errorf("[FAIL] Failed to add rules to containers (%v)", be.KernelRules)
+		return
+	}
+
+	if be.Containers["container-b"].NamespaceName != "multiubuntu" || be.Containers["container-b"].EndPointName != "ubuntu-1" {
+		t.Errorf("[FAIL] Failed to keep the endpoint of a container (%+v)", be.Containers["container-b"])
+		return
+	}
+	t.Log("[PASS] Added rules to containers")
+
+	endPoint.PolicyEnabled = tp.KubeArmorPolicyAudited
+	be.UpdateSecurityPolicies(endPoint)
+
+	if be.GetRuleCount() != 0 {
+		t.Errorf("[FAIL] Failed to remove rules in the audit mode (%v)", be.KernelRules)
+		return
+	}
+	t.Log("[PASS] Removed rules in the audit mode")
+
+	endPoint.PolicyEnabled = tp.KubeArmorPolicyEnabled
+	be.UpdateSecurityPolicies(endPoint)
+	be.UnregisterContainer("container-a")
+
+	if _, ok := be.KernelRules[4026532001]; ok || be.GetRuleCount() != 4 {
+		t.Errorf("[FAIL] Failed to remove the rules of a container (%v)", be.KernelRules)
+		return
+	}
+	t.Log("[PASS] Removed the rules of a container")
+}
+
+func TestBuildAlertLog(t *testing.T) {
+	be := newTestBPFEnforcer()
+
+	be.Containers["container-a"] = tp.Container{ContainerID: "container-a", ContainerName: "ubuntu", NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", MntNS: 4026532001, PolicyEnabled: tp.KubeArmorPolicyEnabled}
+
+	// alert_t in BPF/enforcer.bpf.c
+	if size := binary.Size(BPFAlert{}); size != 328 {
+		t.Errorf("[FAIL] Got an unexpected size of alerts (%d)", size)
+		return
+	}
+
+	alert := BPFAlert{MntNS: 4026532001, HostPID: 1234, PID: 12, Type: BPFRuleFile, Flags: BPFRuleBlock, Retval: -1}
+	copy(alert.Comm[:], "cat")
+	copy(alert.Path[:], "/secret.txt")
+
+	log := be.BuildAlertLog(alert)
+
+	if log.PodName != "ubuntu-1" || log.ContainerName != "ubuntu" || log.Operation != "File" || log.Resource != "/secret.txt" || log.Source != "cat" || log.Action != "Block" || log.HostPID != 1234 {
+		t.Errorf("[FAIL] Built an unexpected log (%+v)", log)
+		return
+	}
+	t.Log("[PASS] Built a log for a file alert")
+
+	alert = BPFAlert{MntNS: 4026532001, Type: BPFRuleNetwork, Family: 2, SockType: 1, Protocol: 6, Retval: -1}
+
+	log = be.BuildAlertLog(alert)
+
+	if log.Operation != "Network" || log.Resource != "domain=AF_INET type=SOCK_STREAM protocol=6" || log.Action != "Block" {
+		t.Errorf("[FAIL] Built an unexpected log (%+v)", log)
+		return
+	}
+	t.Log("[PASS] Built a log for a network alert")
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Enforcer Capabilities
+const (
+	// enforces security policies in containers (otherwise, they are audited)
+	EnforcerCapContainerPolicy uint32 = 0x1
+
+	// enforces host security policies
+	EnforcerCapHostPolicy uint32 = 0x2
+
+	// needs the AppArmor profiles of containers (pod annotations)
+	EnforcerCapAppArmorProfiles uint32 = 0x10
+
+	// needs the SELinux contexts and host volumes of containers (pod security options)
+	EnforcerCapSELinuxContexts uint32 = 0x20
+
+	// needs the namespaces of containers (container runtimes)
+	EnforcerCapContainerNamespaces uint32 = 0x40
+
+	// needs the seccomp profiles of containers (pod security contexts)
+	EnforcerCapSeccompProfiles uint32 = 0x80
+)
+
+// ErrEnforcerDisabled is returned by Init if the enforcer is not enabled by the options (skipped silently)
+var ErrEnforcerDisabled = errors.New("enforcer disabled")
+
+// ============== //
+// == Enforcer == //
+// ============== //
+
+// Endpoint Structure (what enforcers need to prepare the containers of an endpoint)
+type Endpoint struct {
+	NamespaceName string
+	EndPointName  string
+
+	// container name -> AppArmor profile (EnforcerCapAppArmorProfiles)
+	AppArmorProfiles map[string]string
+
+	// selinux- + container name -> SELinux context (EnforcerCapSELinuxContexts)
+	SELinuxProfiles map[string]string
+	HostVolumes     []tp.HostVolumeMount
+
+	// containers with their namespaces (EnforcerCapContainerNamespaces)
+	Containers []tp.Container
+
+	// container name -> seccomp profile (EnforcerCapSeccompProfiles)
+	SeccompProfiles map[string]string
+}
+
+// RuleChecker Interface (for enforcers that can only enforce some rules)
+type RuleChecker interface {
+	// IsEnforceable returns false for the rules to be audited (e.g., tp.FileDirectoryType)
+	IsEnforceable(rule interface{}) bool
+}
+
+// Enforcer Interface
+type Enforcer interface {
+	// Name of the enforcer (e.g., AppArmor)
+	Name() string
+
+	// Capabilities of the enforcer (EnforcerCap*)
+	Capabilities() uint32
+
+	// Init the enforcer (returns an error if the enforcer cannot run on this node)
+	Init(node tp.Node, logger *fd.Feeder) error
+
+	// RegisterEndpoint prepares (ADDED) or releases (DELETED) the containers of an endpoint
+	RegisterEndpoint(action string, endPoint Endpoint)
+
+	// UpdateSecurityPolicies enforces the security policies of an endpoint
+	UpdateSecurityPolicies(endPoint tp.EndPoint)
+
+	// UpdateHostSecurityPolicies enforces host security policies
+	UpdateHostSecurityPolicies(secPolicies []tp.HostSecurityPolicy)
+
+	// Status returns the details for health checks (e.g., the number of profiles)
+	Status() map[string]string
+
+	// Destroy the enforcer
+	Destroy() error
+}
+
+// Factory Function Type
+type Factory func() Enforcer
+
+// enforcerBackend Structure
+type enforcerBackend struct {
+	Name string
+
+	// the LSM in /sys/kernel/security/lsm that the enforcer runs on (empty if no LSM is needed)
+	LSM string
+
+	Factory Factory
+}
+
+// enforcerBackends (in the order of preference)
+var enforcerBackends []enforcerBackend
+var enforcerBackendsLock *sync.RWMutex
+
+// init Function
+func init() {
+	enforcerBackends = []enforcerBackend{}
+	enforcerBackendsLock = new(sync.RWMutex)
+
+	RegisterEnforcer("AppArmor", "apparmor", func() Enforcer { return &AppArmorEnforcer{} })
+	RegisterEnforcer("SELinux", "selinux", func() Enforcer { return &SELinuxEnforcer{} })
+	RegisterEnforcer("BPFLSM", "bpf", func() Enforcer { return &BPFEnforcer{} })
+	RegisterEnforcer("Seccomp", "", func() Enforcer { return &SeccompEnforcer{} })
+}
+
+// RegisterEnforcer Function (a new enforcer is preferred less than the registered ones)
+func RegisterEnforcer(name, lsm string, factory Factory) {
+	enforcerBackendsLock.Lock()
+	defer enforcerBackendsLock.Unlock()
+
+	for idx, backend := range enforcerBackends {
+		if backend.Name == name {
+			enforcerBackends[idx] = enforcerBackend{Name: name, LSM: lsm, Factory: factory}
+			return
+		}
+	}
+
+	enforcerBackends = append(enforcerBackends, enforcerBackend{Name: name, LSM: lsm, Factory: factory})
+}
+
+// UnregisterEnforcer Function
+func UnregisterEnforcer(name string) {
+	enforcerBackendsLock.Lock()
+	defer enforcerBackendsLock.Unlock()
+
+	for idx, backend := range enforcerBackends {
+		if backend.Name == name {
+			enforcerBackends = append(enforcerBackends[:idx], enforcerBackends[idx+1:]...)
+			return
+		}
+	}
+}
+
+// GetEnforcerNames Function
+func GetEnforcerNames() []string {
+	enforcerBackendsLock.RLock()
+	defer enforcerBackendsLock.RUnlock()
+
+	names := []string{}
+	for _, backend := range enforcerBackends {
+		names = append(names, backend.Name)
+	}
+
+	return names
+}
+
+// getEnforcerBackends Function (the ones whose LSMs are enabled, e.g., "lockdown,capability,yama,apparmor")
+func getEnforcerBackends(lsm string) []enforcerBackend {
+	enabled := map[string]bool{}
+	for _, name := range strings.Split(strings.TrimSpace(lsm), ",") {
+		enabled[name] = true
+	}
+
+	enforcerBackendsLock.RLock()
+	defer enforcerBackendsLock.RUnlock()
+
+	backends := []enforcerBackend{}
+	for _, backend := range enforcerBackends {
+		if backend.LSM == "" || enabled[backend.LSM] {
+			backends = append(backends, backend)
+		}
+	}
+
+	return backends
+}
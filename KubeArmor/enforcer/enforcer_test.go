@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// fakeEnforcer Structure (a no-op enforcer that records its calls)
+type fakeEnforcer struct {
+	name    string
+	initErr error
+
+	endPoints  []string
+	secUpdates int
+	destroyed  bool
+}
+
+func (fe *fakeEnforcer) Name() string {
+	return fe.name
+}
+
+func (fe *fakeEnforcer) Capabilities() uint32 {
+	return EnforcerCapContainerPolicy | EnforcerCapContainerNamespaces
+}
+
+func (fe *fakeEnforcer) Init(node tp.Node, logger *fd.Feeder) error {
+	return fe.initErr
+}
+
+func (fe *fakeEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
+	fe.endPoints = append(fe.endPoints, action+"/"+endPoint.NamespaceName+"/"+endPoint.EndPointName)
+}
+
+func (fe *fakeEnforcer) UpdateSecurityPolicies(endPoint tp.EndPoint) {
+	fe.secUpdates++
+}
+
+func (fe *fakeEnforcer) UpdateHostSecurityPolicies(secPolicies []tp.HostSecurityPolicy) {
+	//
+}
+
+func (fe *fakeEnforcer) Status() map[string]string {
+	return map[string]string{"endpoints": strconv.Itoa(len(fe.endPoints))}
+}
+
+func (fe *fakeEnforcer) Destroy() error {
+	fe.destroyed = true
+	return nil
+}
+
+func TestEnforcerRegistry(t *testing.T) {
+	names := GetEnforcerNames()
+	if len(names) != 4 || names[0] != "AppArmor" || names[1] != "SELinux" || names[2] != "BPFLSM" || names[3] != "Seccomp" {
+		t.Errorf("[FAIL] Got unexpected enforcers (%v)", names)
+		return
+	}
+	t.Log("[PASS] Got the built-in enforcers")
+
+	RegisterEnforcer("Fake", "fakelsm", func() Enforcer { return &fakeEnforcer{name: "Fake"} })
+	defer UnregisterEnforcer("Fake")
+
+	backends := getEnforcerBackends("lockdown,capability,fakelsm,bpf\n")
+	// seccomp does not need any LSM
+	if len(backends) != 3 || backends[0].Name != "BPFLSM" || backends[1].Name != "Seccomp" || backends[2].Name != "Fake" {
+		t.Errorf("[FAIL] Got unexpected enforcers for LSMs (%v)", backends)
+		return
+	}
+	t.Log("[PASS] Got enforcers for LSMs")
+
+	UnregisterEnforcer("Fake")
+
+	if names := GetEnforcerNames(); len(names) != 4 {
+		t.Errorf("[FAIL] Failed to unregister an enforcer (%v)", names)
+		return
+	}
+	t.Log("[PASS] Unregistered an enforcer")
+}
+
+func TestRuntimeEnforcer(t *testing.T) {
+	// node
+	node := tp.Node{}
+	node.NodeName = "nodeName"
+	node.NodeIP = "nodeIP"
+
+	// create logger
+	logger := fd.NewFeeder("Default", &node, "32767", "none")
+	if logger == nil {
+		t.Log("[FAIL] Failed to create logger")
+		return
+	}
+	defer func() { _ = logger.DestroyFeeder() }()
+
+	// only use fake enforcers
+	UnregisterEnforcer("Seccomp")
+	defer RegisterEnforcer("Seccomp", "", func() Enforcer { return &SeccompEnforcer{} })
+
+	broken := &fakeEnforcer{name: "Broken", initErr: errors.New("not supported")}
+	fake := &fakeEnforcer{name: "Fake"}
+
+	RegisterEnforcer("Broken", "fakelsm", func() Enforcer { return broken })
+	RegisterEnforcer("Fake", "fakelsm", func() Enforcer { return fake })
+	defer UnregisterEnforcer("Broken")
+	defer UnregisterEnforcer("Fake")
+
+	if re := newRuntimeEnforcerWithLSM(node, logger, "lockdown,capability"); re != nil {
+		t.Errorf("[FAIL] Created Runtime Enforcer without enforcers (%s)", re.EnforcerType)
+		return
+	}
+	t.Log("[PASS] Skipped Runtime Enforcer without enforcers")
+
+	re := newRuntimeEnforcerWithLSM(node, logger, "lockdown,capability,fakelsm")
+	if re == nil || re.EnforcerType != "Fake" {
+		t.Errorf("[FAIL] Failed to fall back to the next enforcer")
+		return
+	}
+	t.Log("[PASS] Fell back to the next enforcer")
+
+	if !re.HasCapability(EnforcerCapContainerNamespaces) || re.HasCapability(EnforcerCapContainerNamespaces|EnforcerCapHostPolicy) {
+		t.Errorf("[FAIL] Got unexpected capabilities")
+		return
+	}
+	t.Log("[PASS] Checked capabilities")
+
+	re.RegisterEndpoint("ADDED", Endpoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1"})
+	re.UpdateSecurityPolicies(tp.EndPoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1"})
+	re.RegisterEndpoint("DELETED", Endpoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1"})
+
+	if len(fake.endPoints) != 2 || fake.endPoints[1] != "DELETED/multiubuntu/ubuntu-1" || fake.secUpdates != 1 || len(broken.endPoints) != 0 {
+		t.Errorf("[FAIL] Failed to pass endpoints to the enforcer (%v)", fake.endPoints)
+		return
+	}
+	t.Log("[PASS] Passed endpoints to the enforcer")
+
+	health := re.GetHealthStatus()
+	if health.Details["type"] != "Fake" || health.Details["endpoints"] != "2" {
+		t.Errorf("[FAIL] Got an unexpected health status (%v)", health.Details)
+		return
+	}
+	t.Log("[PASS] Got the health status")
+
+	if err := re.DestroyRuntimeEnforcer(); err != nil || !fake.destroyed {
+		t.Errorf("[FAIL] Failed to destroy Runtime Enforcer")
+		return
+	}
+	t.Log("[PASS] Destroyed Runtime Enforcer")
+}
@@ -8,7 +8,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
 	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
@@ -23,21 +22,16 @@ type RuntimeEnforcer struct {
 	// LSM type
 	EnforcerType string
 
-	// LSMs
-	appArmorEnforcer *AppArmorEnforcer
-	seLinuxEnforcer  *SELinuxEnforcer
+	// LSM enforcer (see enforcer.go)
+	enforcer Enforcer
 }
 
 // NewRuntimeEnforcer Function
 func NewRuntimeEnforcer(node tp.Node, logger *fd.Feeder) *RuntimeEnforcer {
-	re := &RuntimeEnforcer{}
-
-	re.Logger = logger
-
 	if !kl.IsK8sLocal() {
 		// mount securityfs
 		if err := kl.RunCommandAndWaitWithErr("mount", []string{"-t", "securityfs", "securityfs", "/sys/kernel/security"}); err != nil {
-			re.Logger.Errf("Failed to mount securityfs (%s)", err.Error())
+			logger.Errf("Failed to mount securityfs (%s)", err.Error())
 		}
 	}
 
@@ -47,73 +41,74 @@ func NewRuntimeEnforcer(node tp.Node, logger *fd.Feeder) *RuntimeEnforcer {
 	if _, err := os.Stat(filepath.Clean(lsmPath)); err == nil {
 		lsm, err = ioutil.ReadFile(lsmPath)
 		if err != nil {
-			re.Logger.Errf("Failed to read /sys/kernel/security/lsm (%s)", err.Error())
+			logger.Errf("Failed to read /sys/kernel/security/lsm (%s)", err.Error())
 			return nil
 		}
 	}
 
-	re.EnforcerType = string(lsm)
+	return newRuntimeEnforcerWithLSM(node, logger, string(lsm))
+}
 
-	if strings.Contains(re.EnforcerType, "apparmor") {
-		re.appArmorEnforcer = NewAppArmorEnforcer(node, logger)
-		if re.appArmorEnforcer != nil {
-			re.Logger.Print("Initialized AppArmor Enforcer")
-			re.EnforcerType = "AppArmor"
-		} else {
-			return nil
-		}
-	} else if strings.Contains(re.EnforcerType, "selinux") {
-		re.seLinuxEnforcer = NewSELinuxEnforcer(logger)
-		if re.seLinuxEnforcer != nil {
-			re.Logger.Print("Initialized SELinux Enforcer")
-			re.EnforcerType = "SELinux"
-		} else {
-			return nil
+// newRuntimeEnforcerWithLSM Function
+func newRuntimeEnforcerWithLSM(node tp.Node, logger *fd.Feeder, lsm string) *RuntimeEnforcer {
+	re := &RuntimeEnforcer{}
+
+	re.Logger = logger
+
+	// use the first enforcer that can run on the enabled LSMs
+	for _, backend := range getEnforcerBackends(lsm) {
+		enforcer := backend.Factory()
+
+		if err := enforcer.Init(node, logger); err == ErrEnforcerDisabled {
+			continue
+		} else if err != nil {
+			re.Logger.Errf("Failed to initialize %s Enforcer (%s)", backend.Name, err.Error())
+			continue
 		}
-	} else {
-		return nil
+
+		re.Logger.Printf("Initialized %s Enforcer", backend.Name)
+
+		re.EnforcerType = enforcer.Name()
+		re.enforcer = enforcer
+
+		return re
 	}
 
-	return re
+	return nil
 }
 
-// UpdateAppArmorProfiles Function
-func (re *RuntimeEnforcer) UpdateAppArmorProfiles(action string, profiles map[string]string) {
+// HasCapability Function
+func (re *RuntimeEnforcer) HasCapability(capability uint32) bool {
 	// skip if runtime enforcer is not active
 	if re == nil {
-		return
+		return false
 	}
 
-	if re.EnforcerType == "AppArmor" {
-		for _, profile := range profiles {
-			if action == "ADDED" {
-				re.appArmorEnforcer.RegisterAppArmorProfile(profile)
-			} else if action == "DELETED" {
-				re.appArmorEnforcer.UnregisterAppArmorProfile(profile)
-			}
-		}
+	return re.enforcer.Capabilities()&capability == capability
+}
+
+// GetRuleChecker Function
+func (re *RuntimeEnforcer) GetRuleChecker() func(rule interface{}) bool {
+	// skip if runtime enforcer is not active
+	if re == nil {
+		return nil
+	}
+
+	if checker, ok := re.enforcer.(RuleChecker); ok {
+		return checker.IsEnforceable
 	}
+
+	return nil
 }
 
-// UpdateSELinuxProfiles Function
-func (re *RuntimeEnforcer) UpdateSELinuxProfiles(action string, profiles map[string]string, hostVolumes []tp.HostVolumeMount) {
+// RegisterEndpoint Function
+func (re *RuntimeEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
 	// skip if runtime enforcer is not active
 	if re == nil {
 		return
 	}
 
-	if re.EnforcerType == "SELinux" {
-		for k, v := range profiles {
-			if strings.HasPrefix(k, "selinux-") { // selinux- + [container_name]
-				containerName := strings.Split(k, "selinux-")[1]
-				if action == "ADDED" {
-					re.seLinuxEnforcer.RegisterSELinuxProfile(containerName, hostVolumes, v)
-				} else if action == "DELETED" {
-					re.seLinuxEnforcer.UnregisterSELinuxProfile(v)
-				}
-			}
-		}
-	}
+	re.enforcer.RegisterEndpoint(action, endPoint)
 }
 
 // UpdateSecurityPolicies Function
@@ -123,11 +118,7 @@ func (re *RuntimeEnforcer) UpdateSecurityPolicies(endPoint tp.EndPoint) {
 		return
 	}
 
-	if re.EnforcerType == "AppArmor" {
-		re.appArmorEnforcer.UpdateSecurityPolicies(endPoint)
-	} else if re.EnforcerType == "SELinux" {
-		re.seLinuxEnforcer.UpdateSecurityPolicies(endPoint)
-	}
+	re.enforcer.UpdateSecurityPolicies(endPoint)
 }
 
 // UpdateHostSecurityPolicies Function
@@ -137,11 +128,26 @@ func (re *RuntimeEnforcer) UpdateHostSecurityPolicies(secPolicies []tp.HostSecur
 		return
 	}
 
-	if re.EnforcerType == "AppArmor" {
-		re.appArmorEnforcer.UpdateHostSecurityPolicies(secPolicies)
-	} else if re.EnforcerType == "SELinux" {
-		re.seLinuxEnforcer.UpdateHostSecurityPolicies(secPolicies)
+	re.enforcer.UpdateHostSecurityPolicies(secPolicies)
+}
+
+// GetHealthStatus Function
+func (re *RuntimeEnforcer) GetHealthStatus() tp.HealthStatus {
+	health := tp.HealthStatus{Name: "runtimeEnforcer", Healthy: true, Details: map[string]string{}}
+
+	// skip if runtime enforcer is not active
+	if re == nil {
+		health.Message = "disabled (no LSM is enabled)"
+		return health
+	}
+
+	for k, v := range re.enforcer.Status() {
+		health.Details[k] = v
 	}
+
+	health.Details["type"] = re.EnforcerType
+
+	return health
 }
 
 // DestroyRuntimeEnforcer Function
@@ -151,31 +157,12 @@ func (re *RuntimeEnforcer) DestroyRuntimeEnforcer() error {
 		return nil
 	}
 
-	errorLSM := false
-
-	if re.EnforcerType == "AppArmor" {
-		if re.appArmorEnforcer != nil {
-			if err := re.appArmorEnforcer.DestroyAppArmorEnforcer(); err != nil {
-				re.Logger.Err(err.Error())
-				errorLSM = true
-			} else {
-				re.Logger.Print("Destroyed AppArmor Enforcer")
-			}
-		}
-	} else if re.EnforcerType == "selinux" {
-		if re.seLinuxEnforcer != nil {
-			if err := re.seLinuxEnforcer.DestroySELinuxEnforcer(); err != nil {
-				re.Logger.Err(err.Error())
-				errorLSM = true
-			} else {
-				re.Logger.Print("Destroyed SELinux Enforcer")
-			}
-		}
-	}
-
-	if errorLSM {
+	if err := re.enforcer.Destroy(); err != nil {
+		re.Logger.Err(err.Error())
 		return fmt.Errorf("failed to destroy RuntimeEnforcer (%s)", re.EnforcerType)
 	}
 
+	re.Logger.Printf("Destroyed %s Enforcer", re.EnforcerType)
+
 	return nil
 }
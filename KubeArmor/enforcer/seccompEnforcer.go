@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// SeccompProfileRoot is the seccomp directory of kubelet (localhost/ profiles are relative to it)
+var SeccompProfileRoot = "/var/lib/kubelet/seccomp"
+
+// SeccompProfilePrefix is the prefix of the profiles managed by KubeArmor
+const SeccompProfilePrefix = "localhost/kubearmor/"
+
+// ====================== //
+// == Seccomp Enforcer == //
+// ====================== //
+
+// SeccompEnforcer Structure
+type SeccompEnforcer struct {
+	// host
+	HostName string
+	Arch     string
+
+	// logs
+	Logger *fd.Feeder
+
+	// profiles for containers (profile -> the number of pods)
+	SeccompProfiles     map[string]int
+	SeccompProfilesLock *sync.Mutex
+}
+
+// GetSeccompProfileName Function
+func GetSeccompProfileName(namespaceName, containerName string) string {
+	return SeccompProfilePrefix + namespaceName + "-" + containerName + ".json"
+}
+
+// getSeccompProfilePath Function
+func getSeccompProfilePath(profile string) string {
+	return filepath.Clean(SeccompProfileRoot + "/" + strings.TrimPrefix(profile, "localhost/"))
+}
+
+// Name Function
+func (se *SeccompEnforcer) Name() string {
+	return "Seccomp"
+}
+
+// Capabilities Function
+func (se *SeccompEnforcer) Capabilities() uint32 {
+	return EnforcerCapContainerPolicy | EnforcerCapSeccompProfiles
+}
+
+// IsEnforceable Function (processes, files, and the other rules are audited)
+func (se *SeccompEnforcer) IsEnforceable(rule interface{}) bool {
+	return isSeccompRuleEnforceable(rule)
+}
+
+// Init Function
+func (se *SeccompEnforcer) Init(node tp.Node, logger *fd.Feeder) error {
+	// seccomp profiles are only used if enabled (-enableSeccompEnforcer)
+	if !node.EnableSeccompEnforcer {
+		return ErrEnforcerDisabled
+	}
+
+	// host
+	se.HostName = node.NodeName
+	se.Arch = kl.GetArchitecture(node.Architecture)
+
+	// logs
+	se.Logger = logger
+
+	// profiles
+	se.SeccompProfiles = map[string]int{}
+	se.SeccompProfilesLock = &sync.Mutex{}
+
+	if _, ok := seccompArchitectures[se.Arch]; !ok {
+		return fmt.Errorf("unsupported architecture %s", se.Arch)
+	}
+
+	// seccomp profiles are loaded by kubelet
+	if !kl.IsK8sEnv() {
+		return errors.New("seccomp profiles need kubelet")
+	}
+
+	status, err := ioutil.ReadFile("/proc/self/status")
+	if err != nil {
+		return fmt.Errorf("failed to read /proc/self/status (%s)", err.Error())
+	}
+
+	if !strings.Contains(string(status), "Seccomp:") {
+		return errors.New("seccomp is not supported by the kernel")
+	}
+
+	// profiles of the last run are kept since containers load them whenever they (re)start
+	if err := os.MkdirAll(getSeccompProfilePath(SeccompProfilePrefix), 0750); err != nil {
+		return fmt.Errorf("failed to create %s (%s)", getSeccompProfilePath(SeccompProfilePrefix), err.Error())
+	}
+
+	return nil
+}
+
+// Destroy Function
+func (se *SeccompEnforcer) Destroy() error {
+	// skip if SeccompEnforcer is not active
+	if se == nil {
+		return nil
+	}
+
+	se.SeccompProfilesLock.Lock()
+	se.SeccompProfiles = map[string]int{}
+	se.SeccompProfilesLock.Unlock()
+
+	return nil
+}
+
+// ================================ //
+// == Seccomp Profile Management == //
+// ================================ //
+
+// writeSeccompProfile Function
+func (se *SeccompEnforcer) writeSeccompProfile(profile, content string) error {
+	return ioutil.WriteFile(getSeccompProfilePath(profile), []byte(content), 0600)
+}
+
+// RegisterSeccompProfile Function
+func (se *SeccompEnforcer) RegisterSeccompProfile(profile string) bool {
+	// skip if SeccompEnforcer is not active
+	if se == nil {
+		return true
+	}
+
+	se.SeccompProfilesLock.Lock()
+	defer se.SeccompProfilesLock.Unlock()
+
+	if _, ok := se.SeccompProfiles[profile]; ok {
+		se.SeccompProfiles[profile]++
+		return true
+	}
+
+	// containers cannot start without their profiles, so create a default one until policies are applied
+	if _, err := os.Stat(getSeccompProfilePath(profile)); err != nil {
+		_, defaultProfile, err := GenerateSeccompProfile([]tp.SecurityPolicy{}, se.Arch)
+		if err != nil {
+			se.Logger.Errf("Failed to generate a Seccomp profile (%s, %s)", profile, err.Error())
+			return false
+		}
+
+		if err := se.writeSeccompProfile(profile, defaultProfile); err != nil {
+			se.Logger.Errf("Failed to create a Seccomp profile (%s, %s)", profile, err.Error())
+			return false
+		}
+	}
+
+	se.SeccompProfiles[profile] = 1
+	se.Logger.Printf("Registered a Seccomp profile (%s)", profile)
+
+	return true
+}
+
+// UnregisterSeccompProfile Function
+func (se *SeccompEnforcer) UnregisterSeccompProfile(profile string) bool {
+	// skip if SeccompEnforcer is not active
+	if se == nil {
+		return true
+	}
+
+	se.SeccompProfilesLock.Lock()
+	defer se.SeccompProfilesLock.Unlock()
+
+	if _, ok := se.SeccompProfiles[profile]; !ok {
+		return true
+	}
+
+	se.SeccompProfiles[profile]--
+
+	if se.SeccompProfiles[profile] > 0 {
+		return true
+	}
+
+	// keep the profile file for the containers of the same workload that restart later
+	delete(se.SeccompProfiles, profile)
+
+	se.Logger.Printf("Unregistered a Seccomp profile (%s)", profile)
+
+	return true
+}
+
+// GetProfileCount Function
+func (se *SeccompEnforcer) GetProfileCount() int {
+	se.SeccompProfilesLock.Lock()
+	defer se.SeccompProfilesLock.Unlock()
+
+	return len(se.SeccompProfiles)
+}
+
+// RegisterEndpoint Function
+func (se *SeccompEnforcer) RegisterEndpoint(action string, endPoint Endpoint) {
+	for _, profile := range endPoint.SeccompProfiles {
+		if !strings.HasPrefix(profile, SeccompProfilePrefix) {
+			continue // out-of-control (e.g., runtime/default)
+		}
+
+		if action == "ADDED" {
+			if !se.RegisterSeccompProfile(profile) {
+				mt.EnforcerFailures.WithLabelValues(se.Name(), "register").Inc()
+			}
+		} else if action == "DELETED" {
+			if !se.UnregisterSeccompProfile(profile) {
+				mt.EnforcerFailures.WithLabelValues(se.Name(), "unregister").Inc()
+			}
+		}
+	}
+}
+
+// Status Function
+func (se *SeccompEnforcer) Status() map[string]string {
+	return map[string]string{"profiles": strconv.Itoa(se.GetProfileCount())}
+}
+
+// ================================= //
+// == Security Policy Enforcement == //
+// ================================= //
+
+// UpdateSeccompProfile Function
+func (se *SeccompEnforcer) UpdateSeccompProfile(endPoint tp.EndPoint, profile string, securityPolicies []tp.SecurityPolicy) {
+	policyCount, newProfile, err := GenerateSeccompProfile(securityPolicies, se.Arch)
+	if err != nil {
+		se.Logger.Err(err.Error())
+		return
+	}
+
+	se.SeccompProfilesLock.Lock()
+	defer se.SeccompProfilesLock.Unlock()
+
+	if oldProfile, err := ioutil.ReadFile(getSeccompProfilePath(profile)); err == nil && string(oldProfile) == newProfile {
+		return
+	}
+
+	if err := se.writeSeccompProfile(profile, newProfile); err != nil {
+		se.Logger.Printf("Failed to update %d security rules to %s/%s/%s (%s)", policyCount, endPoint.NamespaceName, endPoint.EndPointName, profile, err.Error())
+		return
+	}
+
+	// seccomp profiles are loaded when containers start
+	se.Logger.Printf("Updated %d security rules to %s/%s/%s (applied to new containers)", policyCount, endPoint.NamespaceName, endPoint.EndPointName, profile)
+}
+
+// UpdateSecurityPolicies Function
+func (se *SeccompEnforcer) UpdateSecurityPolicies(endPoint tp.EndPoint) {
+	// skip if SeccompEnforcer is not active
+	if se == nil {
+		return
+	}
+
+	seccompProfiles := []string{}
+
+	for _, profile := range endPoint.SeccompProfiles {
+		if !strings.HasPrefix(profile, SeccompProfilePrefix) {
+			continue
+		}
+
+		if !kl.ContainsElement(seccompProfiles, profile) {
+			seccompProfiles = append(seccompProfiles, profile)
+		}
+	}
+
+	if endPoint.PolicyEnabled == tp.KubeArmorPolicyEnabled {
+		for _, profile := range seccompProfiles {
+			se.UpdateSeccompProfile(endPoint, profile, endPoint.SecurityPolicies)
+		}
+	} else { // PolicyDisabled
+		for _, profile := range seccompProfiles {
+			se.UpdateSeccompProfile(endPoint, profile, []tp.SecurityPolicy{})
+		}
+	}
+}
+
+// UpdateHostSecurityPolicies Function
+func (se *SeccompEnforcer) UpdateHostSecurityPolicies(secPolicies []tp.HostSecurityPolicy) {
+	// host security policies are not enforced by seccomp
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func TestSeccompEnforcer(t *testing.T) {
+	// node
+	node := tp.Node{}
+	node.NodeName = "nodeName"
+	node.NodeIP = "nodeIP"
+
+	// create logger
+	logger := fd.NewFeeder("Default", &node, "32767", "none")
+	if logger == nil {
+		t.Log("[FAIL] Failed to create logger")
+		return
+	}
+	defer func() { _ = logger.DestroyFeeder() }()
+
+	// use a temporary kubelet directory
+	root, err := ioutil.TempDir("", "seccomp")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer func() { _ = os.RemoveAll(root) }()
+
+	prevRoot := SeccompProfileRoot
+	SeccompProfileRoot = root
+	defer func() { SeccompProfileRoot = prevRoot }()
+
+	if err := os.MkdirAll(getSeccompProfilePath(SeccompProfilePrefix), 0750); err != nil {
+		t.Errorf("[FAIL] Failed to create a profile directory (%s)", err.Error())
+		return
+	}
+
+	// seccomp is opt-in
+	if err := (&SeccompEnforcer{}).Init(node, logger); err != ErrEnforcerDisabled {
+		t.Errorf("[FAIL] Initialized the seccomp enforcer without enableSeccompEnforcer")
+		return
+	}
+	t.Log("[PASS] Skipped the seccomp enforcer without enableSeccompEnforcer")
+
+	se := &SeccompEnforcer{Arch: "amd64", Logger: logger, SeccompProfiles: map[string]int{}, SeccompProfilesLock: &sync.Mutex{}}
+
+	if !se.IsEnforceable(tp.SyscallMatchType{Syscalls: []string{"unshare"}, Action: "Block"}) ||
+		se.IsEnforceable(tp.SyscallMatchType{Syscalls: []string{"unlink"}, Path: "/etc/", Action: "Block"}) ||
+		se.IsEnforceable(tp.NetworkProtocolType{Protocol: "tcp", Action: "Block"}) ||
+		se.IsEnforceable(tp.ProcessPathType{Path: "/bin/sleep", Action: "Block"}) {
+		t.Errorf("[FAIL] Failed to tell the rules that seccomp can enforce")
+		return
+	}
+	t.Log("[PASS] Told the rules that seccomp can enforce")
+
+	profile := GetSeccompProfileName("multiubuntu", "ubuntu-1-container")
+	if profile != "localhost/kubearmor/multiubuntu-ubuntu-1-container.json" {
+		t.Errorf("[FAIL] Got an unexpected profile name (%s)", profile)
+		return
+	}
+
+	// two pods of the same deployment
+	endPoint := Endpoint{NamespaceName: "multiubuntu", SeccompProfiles: map[string]string{"ubuntu-1-container": profile, "sidecar": "runtime/default"}}
+	se.RegisterEndpoint("ADDED", endPoint)
+	se.RegisterEndpoint("ADDED", endPoint)
+
+	_, defaultProfile, _ := GenerateSeccompProfile([]tp.SecurityPolicy{}, "amd64")
+
+	if content, err := ioutil.ReadFile(getSeccompProfilePath(profile)); err != nil || string(content) != defaultProfile || se.GetProfileCount() != 1 {
+		t.Errorf("[FAIL] Failed to register a seccomp profile (%v)", se.SeccompProfiles)
+		return
+	}
+	t.Log("[PASS] Registered a seccomp profile")
+
+	secEndPoint := tp.EndPoint{
+		NamespaceName:    "multiubuntu",
+		EndPointName:     "ubuntu-1",
+		SeccompProfiles:  []string{profile, "runtime/default"},
+		SecurityPolicies: []tp.SecurityPolicy{newTestSeccompPolicy()},
+		PolicyEnabled:    tp.KubeArmorPolicyEnabled,
+	}
+	se.UpdateSecurityPolicies(secEndPoint)
+
+	_, policyProfile, _ := GenerateSeccompProfile(secEndPoint.SecurityPolicies, "amd64")
+
+	if content, err := ioutil.ReadFile(getSeccompProfilePath(profile)); err != nil || string(content) != policyProfile {
+		t.Errorf("[FAIL] Failed to update a seccomp profile")
+		return
+	}
+	t.Log("[PASS] Updated a seccomp profile")
+
+	secEndPoint.PolicyEnabled = tp.KubeArmorPolicyAudited
+	se.UpdateSecurityPolicies(secEndPoint)
+
+	if content, err := ioutil.ReadFile(getSeccompProfilePath(profile)); err != nil || string(content) != defaultProfile {
+		t.Errorf("[FAIL] Failed to reset a seccomp profile in the audit mode")
+		return
+	}
+	t.Log("[PASS] Reset a seccomp profile in the audit mode")
+
+	se.RegisterEndpoint("DELETED", endPoint)
+
+	if se.GetProfileCount() != 1 {
+		t.Errorf("[FAIL] Unregistered a seccomp profile used by another pod")
+		return
+	}
+
+	se.RegisterEndpoint("DELETED", endPoint)
+
+	if _, err := os.Stat(getSeccompProfilePath(profile)); err != nil || se.GetProfileCount() != 0 {
+		t.Errorf("[FAIL] Failed to unregister a seccomp profile (%v)", se.SeccompProfiles)
+		return
+	}
+	t.Log("[PASS] Unregistered a seccomp profile")
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Seccomp Actions
+const (
+	SeccompActAllow = "SCMP_ACT_ALLOW"
+	SeccompActErrno = "SCMP_ACT_ERRNO"
+)
+
+// Seccomp Operators
+const (
+	SeccompCmpEq       = "SCMP_CMP_EQ"
+	SeccompCmpMaskedEq = "SCMP_CMP_MASKED_EQ"
+)
+
+// the arguments of socket(domain, type, protocol)
+const (
+	afPacket     = 17
+	sockRaw      = 3
+	sockTypeMask = 0xf
+	ipProtoICMP  = 1
+	ipProtoICMP6 = 58
+)
+
+// GOARCH -> the native ABI (the syscalls of the other ABIs, e.g., x86 on amd64, are killed so that denied syscalls cannot be called through them)
+var seccompArchitectures = map[string]string{
+	"amd64": "SCMP_ARCH_X86_64",
+	"arm64": "SCMP_ARCH_AARCH64",
+}
+
+// syscalls denied in every profile (like "deny mount," in the AppArmor default profile)
+var seccompDefaultSyscalls = []string{"mount"}
+
+// protocol name -> the socket() arguments to deny (tcp and udp cannot be told from other stream and datagram sockets)
+var seccompProtocolArgs = map[string][][]SeccompArg{
+	"raw":    {{{Index: 1, Value: sockTypeMask, ValueTwo: sockRaw, Op: SeccompCmpMaskedEq}}},
+	"icmp":   {{{Index: 2, Value: ipProtoICMP, Op: SeccompCmpEq}}},
+	"icmpv6": {{{Index: 2, Value: ipProtoICMP6, Op: SeccompCmpEq}}},
+}
+
+// capability name -> the syscalls to deny
+var seccompCapabilitySyscalls = map[string][]string{
+	"chown":      {"chown", "fchown", "fchownat", "lchown"},
+	"setgid":     {"setfsgid", "setgid", "setgroups", "setregid", "setresgid"},
+	"setuid":     {"setfsuid", "setresuid", "setreuid", "setuid"},
+	"sys_admin":  {"mount", "pivot_root", "setdomainname", "sethostname", "swapoff", "swapon", "umount2"},
+	"sys_boot":   {"kexec_file_load", "kexec_load", "reboot"},
+	"sys_chroot": {"chroot"},
+	"sys_module": {"delete_module", "finit_module", "init_module"},
+	"sys_pacct":  {"acct"},
+	"sys_ptrace": {"process_vm_readv", "process_vm_writev", "ptrace"},
+	"sys_rawio":  {"ioperm", "iopl"},
+	"sys_time":   {"adjtimex", "clock_adjtime", "clock_settime", "settimeofday"},
+	"syslog":     {"syslog"},
+}
+
+// capability name -> the socket() arguments to deny
+var seccompCapabilityArgs = map[string][][]SeccompArg{
+	"net_raw": {
+		{{Index: 0, Value: afPacket, Op: SeccompCmpEq}},
+		{{Index: 1, Value: sockTypeMask, ValueTwo: sockRaw, Op: SeccompCmpMaskedEq}},
+	},
+}
+
+// ====================== //
+// == Seccomp Profiles == //
+// ====================== //
+
+// SeccompArg Structure
+type SeccompArg struct {
+	Index    uint   `json:"index"`
+	Value    uint64 `json:"value"`
+	ValueTwo uint64 `json:"valueTwo"`
+	Op       string `json:"op"`
+}
+
+// SeccompSyscall Structure
+type SeccompSyscall struct {
+	Names  []string     `json:"names"`
+	Action string       `json:"action"`
+	Args   []SeccompArg `json:"args,omitempty"`
+}
+
+// SeccompProfile Structure (the OCI seccomp format that kubelet loads from localhost/ profiles)
+type SeccompProfile struct {
+	DefaultAction string           `json:"defaultAction"`
+	Architectures []string         `json:"architectures"`
+	Syscalls      []SeccompSyscall `json:"syscalls,omitempty"`
+}
+
+// seccompRules Structure
+type seccompRules struct {
+	// syscall names denied without conditions
+	syscalls map[string]bool
+
+	// socket() arguments (in JSON) -> the arguments
+	socketArgs map[string][]SeccompArg
+}
+
+// addSyscall Function
+func (rules *seccompRules) addSyscall(name string) {
+	name = strings.TrimPrefix(strings.ToLower(name), "sys_")
+	if name != "" {
+		rules.syscalls[name] = true
+	}
+}
+
+// addSocketArgs Function
+func (rules *seccompRules) addSocketArgs(args []SeccompArg) {
+	key, err := json.Marshal(args)
+	if err != nil {
+		return
+	}
+	rules.socketArgs[string(key)] = args
+}
+
+// isSeccompRuleEnforceable Function
+func isSeccompRuleEnforceable(rule interface{}) bool {
+	// only blocked rules without fromSource (or paths for syscalls) can be expressed in seccomp
+	switch r := rule.(type) {
+	case tp.NetworkProtocolType:
+		_, ok := seccompProtocolArgs[strings.ToLower(r.Protocol)]
+		return r.Action == "Block" && len(r.FromSource) == 0 && ok
+	case tp.CapabilitiesCapabilityType:
+		capName := strings.TrimPrefix(strings.ToLower(r.Capability), "cap_")
+		_, ok1 := seccompCapabilitySyscalls[capName]
+		_, ok2 := seccompCapabilityArgs[capName]
+		return r.Action == "Block" && len(r.FromSource) == 0 && (ok1 || ok2)
+	case tp.SyscallMatchType:
+		return r.Action == "Block" && len(r.FromSource) == 0 && r.Path == ""
+	}
+
+	return false
+}
+
+// GenerateSeccompProfile Function
+func GenerateSeccompProfile(securityPolicies []tp.SecurityPolicy, arch string) (int, string, error) {
+	arch = kl.GetArchitecture(arch)
+
+	scmpArch, ok := seccompArchitectures[arch]
+	if !ok {
+		return 0, "", fmt.Errorf("unsupported architecture %s", arch)
+	}
+
+	rules := seccompRules{syscalls: map[string]bool{}, socketArgs: map[string][]SeccompArg{}}
+
+	for _, name := range seccompDefaultSyscalls {
+		rules.addSyscall(name)
+	}
+
+	count := 0
+
+	for _, secPolicy := range securityPolicies {
+		for _, proto := range secPolicy.Spec.Network.MatchProtocols {
+			if !isSeccompRuleEnforceable(proto) {
+				continue
+			}
+
+			for _, args := range seccompProtocolArgs[strings.ToLower(proto.Protocol)] {
+				rules.addSocketArgs(args)
+			}
+			count++
+		}
+
+		for _, cap := range secPolicy.Spec.Capabilities.MatchCapabilities {
+			if !isSeccompRuleEnforceable(cap) {
+				continue
+			}
+
+			capName := strings.TrimPrefix(strings.ToLower(cap.Capability), "cap_")
+
+			for _, name := range seccompCapabilitySyscalls[capName] {
+				rules.addSyscall(name)
+			}
+			for _, args := range seccompCapabilityArgs[capName] {
+				rules.addSocketArgs(args)
+			}
+			count++
+		}
+
+		for _, sys := range secPolicy.Spec.Syscalls.MatchSyscalls {
+			if !isSeccompRuleEnforceable(sys) {
+				continue
+			}
+
+			for _, name := range sys.Syscalls {
+				rules.addSyscall(name)
+			}
+			count++
+		}
+	}
+
+	profile := SeccompProfile{
+		DefaultAction: SeccompActAllow,
+		Architectures: []string{scmpArch},
+		Syscalls:      []SeccompSyscall{},
+	}
+
+	names := []string{}
+	for name := range rules.syscalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	profile.Syscalls = append(profile.Syscalls, SeccompSyscall{Names: names, Action: SeccompActErrno})
+
+	keys := []string{}
+	for key := range rules.socketArgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		profile.Syscalls = append(profile.Syscalls, SeccompSyscall{Names: []string{"socket"}, Action: SeccompActErrno, Args: rules.socketArgs[key]})
+	}
+
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return 0, "", err
+	}
+
+	return count, string(data) + "\n", nil
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package enforcer
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// go test ./enforcer -run TestGenerateSeccompProfile -update
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func newTestSeccompPolicy() tp.SecurityPolicy {
+	secPolicy := tp.SecurityPolicy{}
+
+	secPolicy.Spec.Network.MatchProtocols = []tp.NetworkProtocolType{
+		{Protocol: "raw", Action: "Block"},
+		{Protocol: "ICMP", Action: "Block"},
+		{Protocol: "tcp", Action: "Block"},
+		{Protocol: "icmpv6", Action: "Block", FromSource: []tp.MatchSourceType{{Path: "/bin/ping"}}},
+	}
+	secPolicy.Spec.Capabilities.MatchCapabilities = []tp.CapabilitiesCapabilityType{
+		{Capability: "net_raw", Action: "Block"},
+		{Capability: "CAP_SYS_PTRACE", Action: "Block"},
+		{Capability: "sys_admin", Action: "Allow"},
+		{Capability: "unknown", Action: "Block"},
+	}
+	secPolicy.Spec.Syscalls.MatchSyscalls = []tp.SyscallMatchType{
+		{Syscalls: []string{"ptrace", "SYS_MOUNT"}, Action: "Block"},
+		{Syscalls: []string{"unshare"}, Action: "Audit"},
+		{Syscalls: []string{"unlink"}, Path: "/etc/passwd", Action: "Block"},
+	}
+
+	return secPolicy
+}
+
+func TestGenerateSeccompProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		arch        string
+		secPolicies []tp.SecurityPolicy
+		count       int
+	}{
+		{name: "default", arch: "amd64", secPolicies: []tp.SecurityPolicy{}, count: 0},
+		{name: "policy-amd64", arch: "x86_64", secPolicies: []tp.SecurityPolicy{newTestSeccompPolicy()}, count: 5},
+		{name: "policy-arm64", arch: "arm64", secPolicies: []tp.SecurityPolicy{newTestSeccompPolicy()}, count: 5},
+	}
+
+	for _, test := range tests {
+		count, profile, err := GenerateSeccompProfile(test.secPolicies, test.arch)
+		if err != nil {
+			t.Errorf("[FAIL] Failed to generate a seccomp profile (%s, %s)", test.name, err.Error())
+			return
+		}
+
+		if count != test.count {
+			t.Errorf("[FAIL] Generated %d rules (%s, expected %d)", count, test.name, test.count)
+			return
+		}
+
+		golden := filepath.Join("testdata", "seccomp", test.name+".json")
+
+		if *updateGolden {
+			if err := ioutil.WriteFile(golden, []byte(profile), 0600); err != nil {
+				t.Errorf("[FAIL] Failed to update %s (%s)", golden, err.Error())
+				return
+			}
+		}
+
+		expected, err := ioutil.ReadFile(filepath.Clean(golden))
+		if err != nil {
+			t.Errorf("[FAIL] Failed to read %s (%s)", golden, err.Error())
+			return
+		}
+
+		if profile != string(expected) {
+			t.Errorf("[FAIL] Generated an unexpected seccomp profile (%s)\n%s", test.name, profile)
+			return
+		}
+		t.Logf("[PASS] Generated a seccomp profile (%s)", test.name)
+	}
+
+	if _, _, err := GenerateSeccompProfile([]tp.SecurityPolicy{}, "riscv64"); err == nil {
+		t.Errorf("[FAIL] Generated a seccomp profile for an unsupported architecture")
+		return
+	}
+	t.Log("[PASS] Rejected an unsupported architecture")
+}
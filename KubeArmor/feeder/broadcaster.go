@@ -0,0 +1,426 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Slow Subscriber Policies
+const (
+	// drop the oldest buffered event to make room for a new one
+	PolicyDropOldest = "drop-oldest"
+
+	// disconnect the subscriber once its buffer overflows
+	PolicyDisconnect = "disconnect"
+)
+
+// Default Subscriber Options
+const (
+	DefaultSubscriberBufferSize = 1024
+	DefaultSlowSubscriberPolicy = PolicyDropOldest
+)
+
+// IsValidSlowSubscriberPolicy Function
+func IsValidSlowSubscriberPolicy(policy string) bool {
+	return policy == PolicyDropOldest || policy == PolicyDisconnect
+}
+
+// ================ //
+// == Subscriber == //
+// ================ //
+
+// Subscriber Structure
+type Subscriber struct {
+	UID    string
+	Filter *Filter
+
+	// stream name (for metrics)
+	stream string
+
+	// slow consumer policy
+	Policy string
+
+	// replayed items (sent before the ones in the ring buffer)
+	backlog []interface{}
+
+	// ring buffer
+	buffer []interface{}
+	head   int
+	count  int
+	lock   *sync.Mutex
+
+	// counters
+	Received uint64
+	Filtered uint64
+	Sent     uint64
+	Dropped  uint64
+
+	// signals
+	notify chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// newSubscriber Function
+func newSubscriber(uid string, filter *Filter, bufferSize int, policy string) *Subscriber {
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriberBufferSize
+	}
+
+	if !IsValidSlowSubscriberPolicy(policy) {
+		policy = DefaultSlowSubscriberPolicy
+	}
+
+	return &Subscriber{
+		UID:    uid,
+		Filter: filter,
+		Policy: policy,
+		buffer: make([]interface{}, bufferSize),
+		lock:   &sync.Mutex{},
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// push Function
+func (sub *Subscriber) push(item interface{}) {
+	// skip the ones that the subscriber is not interested in
+	if !sub.Filter.Match(item) {
+		atomic.AddUint64(&sub.Filtered, 1)
+		return
+	}
+
+	sub.lock.Lock()
+
+	if sub.closed {
+		sub.lock.Unlock()
+		return
+	}
+
+	atomic.AddUint64(&sub.Received, 1)
+
+	if sub.count == len(sub.buffer) {
+		atomic.AddUint64(&sub.Dropped, 1)
+		mt.SubscriberDroppedEvents.WithLabelValues(sub.stream).Inc()
+
+		if sub.Policy == PolicyDisconnect {
+			sub.closeLocked()
+			sub.lock.Unlock()
+			return
+		}
+
+		// drop the oldest one
+		sub.buffer[sub.head] = nil
+		sub.head = (sub.head + 1) % len(sub.buffer)
+		sub.count--
+	}
+
+	sub.buffer[(sub.head+sub.count)%len(sub.buffer)] = item
+	sub.count++
+
+	sub.lock.Unlock()
+
+	// wake up the sender without blocking the dispatcher
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// popLocked Function
+func (sub *Subscriber) popLocked() (interface{}, bool) {
+	if len(sub.backlog) > 0 {
+		item := sub.backlog[0]
+		sub.backlog[0] = nil
+		sub.backlog = sub.backlog[1:]
+		return item, true
+	}
+
+	if sub.count > 0 {
+		item := sub.buffer[sub.head]
+		sub.buffer[sub.head] = nil
+		sub.head = (sub.head + 1) % len(sub.buffer)
+		sub.count--
+		return item, true
+	}
+
+	return nil, false
+}
+
+// Next Function
+func (sub *Subscriber) Next(ctx context.Context) (interface{}, bool) {
+	for {
+		sub.lock.Lock()
+
+		if sub.closed {
+			sub.lock.Unlock()
+			return nil, false
+		}
+
+		if item, ok := sub.popLocked(); ok {
+			sub.lock.Unlock()
+
+			atomic.AddUint64(&sub.Sent, 1)
+			return item, true
+		}
+
+		sub.lock.Unlock()
+
+		select {
+		case <-sub.notify:
+		case <-sub.done:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// NextBatch Function
+func (sub *Subscriber) NextBatch(ctx context.Context, max int) ([]interface{}, bool) {
+	item, ok := sub.Next(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	items := []interface{}{item}
+
+	sub.lock.Lock()
+	for len(items) < max {
+		item, ok := sub.popLocked()
+		if !ok {
+			break
+		}
+		items = append(items, item)
+	}
+	sub.lock.Unlock()
+
+	atomic.AddUint64(&sub.Sent, uint64(len(items)-1))
+
+	return items, true
+}
+
+// Len Function
+func (sub *Subscriber) Len() int {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	return len(sub.backlog) + sub.count
+}
+
+// IsDisconnected Function
+func (sub *Subscriber) IsDisconnected() bool {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	return sub.closed
+}
+
+// closeLocked Function
+func (sub *Subscriber) closeLocked() {
+	if !sub.closed {
+		sub.closed = true
+		close(sub.done)
+	}
+}
+
+// Close Function
+func (sub *Subscriber) Close() {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	sub.closeLocked()
+}
+
+// ================= //
+// == Broadcaster == //
+// ================= //
+
+// Broadcaster Structure
+type Broadcaster struct {
+	// stream name (for metrics)
+	Stream string
+
+	// uid -> subscriber
+	Subscribers     map[string]*Subscriber
+	SubscribersLock *sync.RWMutex
+
+	// options for new subscribers
+	BufferSize int
+	Policy     string
+
+	// the latest sequence number
+	Sequence uint64
+
+	// recent items for resumed subscribers
+	Replay *ReplayBuffer
+}
+
+// NewBroadcaster Function
+func NewBroadcaster(bufferSize int, policy string) *Broadcaster {
+	bc := &Broadcaster{}
+
+	bc.Subscribers = map[string]*Subscriber{}
+	bc.SubscribersLock = new(sync.RWMutex)
+
+	bc.SetOptions(bufferSize, policy)
+
+	return bc
+}
+
+// SetOptions Function
+func (bc *Broadcaster) SetOptions(bufferSize int, policy string) {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriberBufferSize
+	}
+
+	if !IsValidSlowSubscriberPolicy(policy) {
+		policy = DefaultSlowSubscriberPolicy
+	}
+
+	bc.BufferSize = bufferSize
+	bc.Policy = policy
+}
+
+// Subscribe Function
+func (bc *Broadcaster) Subscribe(uid string, filter *Filter) *Subscriber {
+	bc.SubscribersLock.RLock()
+	bufferSize, policy := bc.BufferSize, bc.Policy
+	bc.SubscribersLock.RUnlock()
+
+	return bc.SubscribeWith(uid, filter, bufferSize, policy)
+}
+
+// SubscribeWith Function
+func (bc *Broadcaster) SubscribeWith(uid string, filter *Filter, bufferSize int, policy string) *Subscriber {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	sub := newSubscriber(uid, filter, bufferSize, policy)
+	sub.stream = bc.Stream
+	bc.Subscribers[uid] = sub
+
+	return sub
+}
+
+// SubscribeFrom Function
+func (bc *Broadcaster) SubscribeFrom(uid string, filter *Filter, from uint64) (*Subscriber, error) {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	backlog := []interface{}{}
+
+	if bc.Replay != nil {
+		items, err := bc.Replay.Since(from)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if filter.Match(item) {
+				backlog = append(backlog, item)
+			}
+		}
+	} else if latest := atomic.LoadUint64(&bc.Sequence); from != 0 && from != latest+1 {
+		return nil, &ReplayGapError{Requested: from, Oldest: latest + 1, Latest: latest}
+	}
+
+	sub := newSubscriber(uid, filter, bc.BufferSize, bc.Policy)
+	sub.stream = bc.Stream
+	sub.backlog = backlog
+	bc.Subscribers[uid] = sub
+
+	return sub, nil
+}
+
+// Unsubscribe Function
+func (bc *Broadcaster) Unsubscribe(uid string) *Subscriber {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	sub, ok := bc.Subscribers[uid]
+	if !ok {
+		return nil
+	}
+
+	sub.Close()
+	delete(bc.Subscribers, uid)
+
+	return sub
+}
+
+// SetReplayBuffer Function
+func (bc *Broadcaster) SetReplayBuffer(rb *ReplayBuffer) {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	// continue from the latest sequence number in the replay buffer
+	for rb != nil {
+		current := atomic.LoadUint64(&bc.Sequence)
+		if rb.Latest() <= current || atomic.CompareAndSwapUint64(&bc.Sequence, current, rb.Latest()) {
+			break
+		}
+	}
+
+	if bc.Replay != nil {
+		bc.Replay.Close()
+	}
+
+	bc.Replay = rb
+}
+
+// NextSequence Function
+func (bc *Broadcaster) NextSequence() uint64 {
+	return atomic.AddUint64(&bc.Sequence, 1)
+}
+
+// Broadcast Function
+func (bc *Broadcaster) Broadcast(item interface{}) {
+	bc.SubscribersLock.RLock()
+	defer bc.SubscribersLock.RUnlock()
+
+	if bc.Replay != nil {
+		if seq, ok := item.(sequenced); ok {
+			bc.Replay.Append(seq)
+		}
+	}
+
+	for _, sub := range bc.Subscribers {
+		sub.push(item)
+	}
+}
+
+// GetSubscriberCount Function
+func (bc *Broadcaster) GetSubscriberCount() int {
+	bc.SubscribersLock.RLock()
+	defer bc.SubscribersLock.RUnlock()
+
+	return len(bc.Subscribers)
+}
+
+// CloseAll Function
+func (bc *Broadcaster) CloseAll() {
+	bc.SubscribersLock.Lock()
+	defer bc.SubscribersLock.Unlock()
+
+	for _, sub := range bc.Subscribers {
+		sub.Close()
+	}
+
+	if bc.Replay != nil {
+		bc.Replay.Close()
+	}
+}
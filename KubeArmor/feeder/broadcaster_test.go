@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBroadcasterFanOut(t *testing.T) {
+	bc := NewBroadcaster(8, PolicyDropOldest)
+
+	// two subscribers
+	sub1 := bc.Subscribe("sub1", nil)
+	sub2 := bc.Subscribe("sub2", nil)
+
+	for i := 0; i < 4; i++ {
+		bc.Broadcast(i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, sub := range []*Subscriber{sub1, sub2} {
+		for i := 0; i < 4; i++ {
+			item, ok := sub.Next(ctx)
+			if !ok || item.(int) != i {
+				t.Errorf("[FAIL] %s got %v (expected %d)", sub.UID, item, i)
+				return
+			}
+		}
+	}
+	t.Log("[PASS] Delivered every event to every subscriber")
+
+	bc.Unsubscribe("sub1")
+	bc.Unsubscribe("sub2")
+
+	if bc.GetSubscriberCount() != 0 {
+		t.Error("[FAIL] Failed to unsubscribe")
+		return
+	}
+	t.Log("[PASS] Unsubscribed")
+}
+
+func TestBroadcasterDropOldest(t *testing.T) {
+	bc := NewBroadcaster(4, PolicyDropOldest)
+	sub := bc.Subscribe("slow", nil)
+
+	for i := 0; i < 10; i++ {
+		bc.Broadcast(i)
+	}
+
+	if sub.Dropped != 6 {
+		t.Errorf("[FAIL] Dropped %d events (expected 6)", sub.Dropped)
+		return
+	}
+
+	item, ok := sub.Next(context.Background())
+	if !ok || item.(int) != 6 {
+		t.Errorf("[FAIL] Got %v as the oldest event (expected 6)", item)
+		return
+	}
+	t.Log("[PASS] Dropped the oldest events")
+}
+
+func TestBroadcasterDisconnect(t *testing.T) {
+	bc := NewBroadcaster(4, PolicyDisconnect)
+	slow := bc.Subscribe("slow", nil)
+	fast := bc.Subscribe("fast", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 5; i++ {
+		bc.Broadcast(i)
+
+		// keep the fast subscriber drained
+		if _, ok := fast.Next(ctx); !ok {
+			t.Error("[FAIL] Disconnected the fast subscriber")
+			return
+		}
+	}
+
+	if !slow.IsDisconnected() {
+		t.Error("[FAIL] Failed to disconnect the slow subscriber")
+		return
+	}
+
+	if _, ok := slow.Next(ctx); ok {
+		t.Error("[FAIL] Delivered an event to a disconnected subscriber")
+		return
+	}
+	t.Log("[PASS] Disconnected the slow subscriber only")
+}
@@ -14,15 +14,20 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
 	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 
 	"github.com/google/uuid"
 	pb "github.com/kubearmor/KubeArmor/protobuf"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // ============ //
@@ -33,228 +38,355 @@ import (
 var Running bool
 
 // MsgQueue for Messages
-var MsgQueue chan pb.Message
+var MsgQueue chan *pb.Message
 
 // AlertQueue for alerts
-var AlertQueue chan pb.Alert
+var AlertQueue chan *pb.Alert
 
 // LogQueue for Logs
-var LogQueue chan pb.Log
+var LogQueue chan *pb.Log
 
 func init() {
 	Running = true
 
-	MsgQueue = make(chan pb.Message, 1024)
-	AlertQueue = make(chan pb.Alert, 4096)
-	LogQueue = make(chan pb.Log, 32768)
+	MsgQueue = make(chan *pb.Message, 1024)
+	AlertQueue = make(chan *pb.Alert, 4096)
+	LogQueue = make(chan *pb.Log, 32768)
+
+	mt.RegisterQueue("message", func() int { return len(MsgQueue) }, func() int { return cap(MsgQueue) })
+	mt.RegisterQueue("alert", func() int { return len(AlertQueue) }, func() int { return cap(AlertQueue) })
+	mt.RegisterQueue("log", func() int { return len(LogQueue) }, func() int { return cap(LogQueue) })
+}
+
+// SetQueueSizes Function (should be called before creating a feeder)
+func SetQueueSizes(msgSize, alertSize, logSize int) {
+	if msgSize > 0 && msgSize != cap(MsgQueue) {
+		MsgQueue = make(chan *pb.Message, msgSize)
+	}
+
+	if alertSize > 0 && alertSize != cap(AlertQueue) {
+		AlertQueue = make(chan *pb.Alert, alertSize)
+	}
+
+	if logSize > 0 && logSize != cap(LogQueue) {
+		LogQueue = make(chan *pb.Log, logSize)
+	}
 }
 
 // ========== //
 // == gRPC == //
 // ========== //
 
-// MsgStruct Structure
-type MsgStruct struct {
-	Client pb.LogService_WatchMessagesServer
-	Filter string
-}
+// LogService Structure
+type LogService struct {
+	// one broadcaster per queue
+	MsgBroadcaster   *Broadcaster
+	AlertBroadcaster *Broadcaster
+	LogBroadcaster   *Broadcaster
 
-// AlertStruct Structure
-type AlertStruct struct {
-	Client pb.LogService_WatchAlertsServer
-	Filter string
-}
+	// name -> output sink
+	Sinks     map[string]*SinkRunner
+	SinksLock *sync.RWMutex
+
+	// stop dispatchers
+	StopChan chan struct{}
 
-// LogStruct Structure
-type LogStruct struct {
-	Client pb.LogService_WatchLogsServer
-	Filter string
+	// health of all subsystems (set before serving)
+	HealthProvider func() []tp.HealthStatus
 }
 
-// LogService Structure
-type LogService struct {
-	MsgStructs map[string]MsgStruct
-	MsgLock    *sync.Mutex
+// NewLogService Function
+func NewLogService(bufferSize int, policy string) *LogService {
+	ls := &LogService{}
+
+	ls.MsgBroadcaster = NewBroadcaster(bufferSize, policy)
+	ls.AlertBroadcaster = NewBroadcaster(bufferSize, policy)
+	ls.LogBroadcaster = NewBroadcaster(bufferSize, policy)
+
+	ls.MsgBroadcaster.Stream = StreamMessage
+	ls.AlertBroadcaster.Stream = StreamAlert
+	ls.LogBroadcaster.Stream = StreamLog
+
+	mt.RegisterSubscribers(StreamMessage, ls.MsgBroadcaster.GetSubscriberCount)
+	mt.RegisterSubscribers(StreamAlert, ls.AlertBroadcaster.GetSubscriberCount)
+	mt.RegisterSubscribers(StreamLog, ls.LogBroadcaster.GetSubscriberCount)
+
+	ls.MsgBroadcaster.SetReplayBuffer(NewReplayBuffer(DefaultReplayBufferSize))
+	ls.AlertBroadcaster.SetReplayBuffer(NewReplayBuffer(DefaultReplayBufferSize))
+	ls.LogBroadcaster.SetReplayBuffer(NewReplayBuffer(DefaultReplayBufferSize))
+
+	ls.Sinks = map[string]*SinkRunner{}
+	ls.SinksLock = new(sync.RWMutex)
 
-	AlertStructs map[string]AlertStruct
-	AlertLock    *sync.Mutex
+	ls.StopChan = make(chan struct{})
 
-	LogStructs map[string]LogStruct
-	LogLock    *sync.Mutex
+	return ls
 }
 
 // HealthCheck Function
 func (ls *LogService) HealthCheck(ctx context.Context, nonce *pb.NonceMessage) (*pb.ReplyMessage, error) {
-	replyMessage := pb.ReplyMessage{Retval: nonce.Nonce}
+	replyMessage := pb.ReplyMessage{Retval: nonce.Nonce, Status: "OK"}
+
+	// only the feeder itself if no provider is set
+	statuses := []tp.HealthStatus{ls.GetHealthStatus()}
+	if ls.HealthProvider != nil {
+		statuses = ls.HealthProvider()
+	}
+
+	for _, health := range statuses {
+		if !health.Healthy {
+			replyMessage.Status = "Unhealthy"
+		}
+
+		replyMessage.Subsystems = append(replyMessage.Subsystems, &pb.SubsystemHealth{
+			Name:    health.Name,
+			Healthy: health.Healthy,
+			Message: health.Message,
+			Details: health.Details,
+		})
+	}
+
 	return &replyMessage, nil
 }
 
-// addMsgStruct Function
-func (ls *LogService) addMsgStruct(uid string, srv pb.LogService_WatchMessagesServer, filter string) {
-	ls.MsgLock.Lock()
-	defer ls.MsgLock.Unlock()
+// GetHealthStatus Function
+func (ls *LogService) GetHealthStatus() tp.HealthStatus {
+	health := tp.HealthStatus{Name: "feeder", Healthy: true, Details: map[string]string{}}
+
+	select {
+	case <-ls.StopChan:
+		health.Healthy = false
+		health.Message = "stopped"
+	default:
+	}
 
-	msgStruct := MsgStruct{}
-	msgStruct.Client = srv
-	msgStruct.Filter = filter
+	health.Details["messageSubscribers"] = fmt.Sprintf("%d", ls.MsgBroadcaster.GetSubscriberCount())
+	health.Details["alertSubscribers"] = fmt.Sprintf("%d", ls.AlertBroadcaster.GetSubscriberCount())
+	health.Details["logSubscribers"] = fmt.Sprintf("%d", ls.LogBroadcaster.GetSubscriberCount())
 
-	ls.MsgStructs[uid] = msgStruct
-}
+	health.Details["messageQueue"] = fmt.Sprintf("%d/%d", len(MsgQueue), cap(MsgQueue))
+	health.Details["alertQueue"] = fmt.Sprintf("%d/%d", len(AlertQueue), cap(AlertQueue))
+	health.Details["logQueue"] = fmt.Sprintf("%d/%d", len(LogQueue), cap(LogQueue))
 
-// removeMsgStruct Function
-func (ls *LogService) removeMsgStruct(uid string) {
-	ls.MsgLock.Lock()
-	defer ls.MsgLock.Unlock()
+	ls.SinksLock.RLock()
+	health.Details["outputSinks"] = fmt.Sprintf("%d", len(ls.Sinks))
+	ls.SinksLock.RUnlock()
 
-	delete(ls.MsgStructs, uid)
+	return health
 }
 
-// getMsgStructs Function
-func (ls *LogService) getMsgStructs() []MsgStruct {
-	msgStructs := []MsgStruct{}
+// DispatchMessages Function
+func (ls *LogService) DispatchMessages() {
+	for {
+		select {
+		case <-ls.StopChan:
+			return
+		case msg := <-MsgQueue:
+			msg.SequenceNumber = ls.MsgBroadcaster.NextSequence()
+			ls.MsgBroadcaster.Broadcast(msg)
+		}
+	}
+}
 
-	ls.MsgLock.Lock()
-	defer ls.MsgLock.Unlock()
+// DispatchAlerts Function
+func (ls *LogService) DispatchAlerts() {
+	for {
+		select {
+		case <-ls.StopChan:
+			return
+		case alert := <-AlertQueue:
+			alert.SequenceNumber = ls.AlertBroadcaster.NextSequence()
+			ls.AlertBroadcaster.Broadcast(alert)
+		}
+	}
+}
 
-	for _, mgs := range ls.MsgStructs {
-		msgStructs = append(msgStructs, mgs)
+// DispatchLogs Function
+func (ls *LogService) DispatchLogs() {
+	for {
+		select {
+		case <-ls.StopChan:
+			return
+		case log := <-LogQueue:
+			log.SequenceNumber = ls.LogBroadcaster.NextSequence()
+			ls.LogBroadcaster.Broadcast(log)
+		}
 	}
+}
 
-	return msgStructs
+// reportSubscriber Function
+func reportSubscriber(kind string, sub *Subscriber) {
+	if sub == nil {
+		return
+	}
+
+	sent := atomic.LoadUint64(&sub.Sent)
+	dropped := atomic.LoadUint64(&sub.Dropped)
+
+	if dropped > 0 && sub.Policy == PolicyDisconnect {
+		kg.Warnf("Disconnected a slow %s subscriber (%s, sent=%d, dropped=%d)", kind, sub.UID, sent, dropped)
+	} else if dropped > 0 {
+		kg.Warnf("Removed a %s subscriber (%s, sent=%d, dropped=%d)", kind, sub.UID, sent, dropped)
+	}
 }
 
 // WatchMessages Function
 func (ls *LogService) WatchMessages(req *pb.RequestMessage, svr pb.LogService_WatchMessagesServer) error {
+	filter, err := CompileFilter(req.Filter, (&pb.Message{}).ProtoReflect().Descriptor())
+	if err != nil {
+		kg.Warnf("Rejected a message subscriber with an invalid filter (%s)", err.Error())
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %s", err.Error())
+	}
+
 	uid := uuid.Must(uuid.NewRandom()).String()
 
-	ls.addMsgStruct(uid, svr, req.Filter)
-	defer ls.removeMsgStruct(uid)
+	sub, err := ls.MsgBroadcaster.SubscribeFrom(uid, filter, req.ResumeFrom)
+	if err != nil {
+		kg.Warnf("Failed to resume a message subscriber (%s)", err.Error())
+		return status.Errorf(codes.OutOfRange, "%s", err.Error())
+	}
+	defer func() { reportSubscriber("message", ls.MsgBroadcaster.Unsubscribe(uid)) }()
 
 	for Running {
-		//nolint
-		msg := <-MsgQueue
+		item, ok := sub.Next(svr.Context())
+		if !ok {
+			break
+		}
 
-		msgStructs := ls.getMsgStructs()
-		for _, mgs := range msgStructs {
-			if err := mgs.Client.Send(&msg); err != nil {
-				kg.Err("Failed to send a message")
-			}
+		if err := svr.Send(item.(*pb.Message)); err != nil {
+			kg.Err("Failed to send a message")
+			return err
 		}
 	}
 
 	return nil
 }
 
-// addAlertStruct Function
-func (ls *LogService) addAlertStruct(uid string, srv pb.LogService_WatchAlertsServer, filter string) {
-	ls.AlertLock.Lock()
-	defer ls.AlertLock.Unlock()
-
-	alertStruct := AlertStruct{}
-	alertStruct.Client = srv
-	alertStruct.Filter = filter
-
-	ls.AlertStructs[uid] = alertStruct
-}
-
-// removeAlertStruct Function
-func (ls *LogService) removeAlertStruct(uid string) {
-	ls.AlertLock.Lock()
-	defer ls.AlertLock.Unlock()
+// WatchAlerts Function
+func (ls *LogService) WatchAlerts(req *pb.RequestMessage, svr pb.LogService_WatchAlertsServer) error {
+	filter, err := CompileFilter(req.Filter, (&pb.Alert{}).ProtoReflect().Descriptor())
+	if err != nil {
+		kg.Warnf("Rejected a alert subscriber with an invalid filter (%s)", err.Error())
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %s", err.Error())
+	}
 
-	delete(ls.AlertStructs, uid)
-}
+	uid := uuid.Must(uuid.NewRandom()).String()
 
-// getAlertStructs Function
-func (ls *LogService) getAlertStructs() []AlertStruct {
-	alertStructs := []AlertStruct{}
+	sub, err := ls.AlertBroadcaster.SubscribeFrom(uid, filter, req.ResumeFrom)
+	if err != nil {
+		kg.Warnf("Failed to resume a alert subscriber (%s)", err.Error())
+		return status.Errorf(codes.OutOfRange, "%s", err.Error())
+	}
+	defer func() { reportSubscriber("alert", ls.AlertBroadcaster.Unsubscribe(uid)) }()
 
-	ls.AlertLock.Lock()
-	defer ls.AlertLock.Unlock()
+	for Running {
+		item, ok := sub.Next(svr.Context())
+		if !ok {
+			break
+		}
 
-	for _, als := range ls.AlertStructs {
-		alertStructs = append(alertStructs, als)
+		if err := svr.Send(item.(*pb.Alert)); err != nil {
+			kg.Err("Failed to send an alert")
+			return err
+		}
 	}
 
-	return alertStructs
+	return nil
 }
 
-// WatchAlerts Function
-func (ls *LogService) WatchAlerts(req *pb.RequestMessage, svr pb.LogService_WatchAlertsServer) error {
+// WatchLogs Function
+func (ls *LogService) WatchLogs(req *pb.RequestMessage, svr pb.LogService_WatchLogsServer) error {
+	filter, err := CompileFilter(req.Filter, (&pb.Log{}).ProtoReflect().Descriptor())
+	if err != nil {
+		kg.Warnf("Rejected a log subscriber with an invalid filter (%s)", err.Error())
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %s", err.Error())
+	}
+
 	uid := uuid.Must(uuid.NewRandom()).String()
 
-	ls.addAlertStruct(uid, svr, req.Filter)
-	defer ls.removeAlertStruct(uid)
+	sub, err := ls.LogBroadcaster.SubscribeFrom(uid, filter, req.ResumeFrom)
+	if err != nil {
+		kg.Warnf("Failed to resume a log subscriber (%s)", err.Error())
+		return status.Errorf(codes.OutOfRange, "%s", err.Error())
+	}
+	defer func() { reportSubscriber("log", ls.LogBroadcaster.Unsubscribe(uid)) }()
 
 	for Running {
-		//nolint
-		alert := <-AlertQueue
+		item, ok := sub.Next(svr.Context())
+		if !ok {
+			break
+		}
 
-		alertStructs := ls.getAlertStructs()
-		for _, als := range alertStructs {
-			if err := als.Client.Send(&alert); err != nil {
-				kg.Err("Failed to send an alert")
-			}
+		if err := svr.Send(item.(*pb.Log)); err != nil {
+			kg.Err("Failed to send a log")
+			return err
 		}
 	}
 
 	return nil
 }
 
-// addLogStruct Function
-func (ls *LogService) addLogStruct(uid string, srv pb.LogService_WatchLogsServer, filter string) {
-	ls.LogLock.Lock()
-	defer ls.LogLock.Unlock()
-
-	logStruct := LogStruct{}
-	logStruct.Client = srv
-	logStruct.Filter = filter
-
-	ls.LogStructs[uid] = logStruct
+// SetSubscriberOptions Function
+func (ls *LogService) SetSubscriberOptions(bufferSize int, policy string) {
+	ls.MsgBroadcaster.SetOptions(bufferSize, policy)
+	ls.AlertBroadcaster.SetOptions(bufferSize, policy)
+	ls.LogBroadcaster.SetOptions(bufferSize, policy)
 }
 
-// removeLogStruct Function
-func (ls *LogService) removeLogStruct(uid string) {
-	ls.LogLock.Lock()
-	defer ls.LogLock.Unlock()
+// SetReplayOptions Function
+func (ls *LogService) SetReplayOptions(size int, dir string) error {
+	// disable replay
+	if size <= 0 {
+		ls.MsgBroadcaster.SetReplayBuffer(nil)
+		ls.AlertBroadcaster.SetReplayBuffer(nil)
+		ls.LogBroadcaster.SetReplayBuffer(nil)
+		return nil
+	}
 
-	delete(ls.LogStructs, uid)
-}
+	// in-memory replay
+	if dir == "" {
+		ls.MsgBroadcaster.SetReplayBuffer(NewReplayBuffer(size))
+		ls.AlertBroadcaster.SetReplayBuffer(NewReplayBuffer(size))
+		ls.LogBroadcaster.SetReplayBuffer(NewReplayBuffer(size))
+		return nil
+	}
 
-// getLogStructs Function
-func (ls *LogService) getLogStructs() []LogStruct {
-	logStructs := []LogStruct{}
+	// on-disk replay
 
-	ls.LogLock.Lock()
-	defer ls.LogLock.Unlock()
+	msgReplay, err := NewPersistentReplayBuffer(size, filepath.Join(dir, "messages.replay"), func() proto.Message { return &pb.Message{} })
+	if err != nil {
+		return err
+	}
 
-	for _, lgs := range ls.LogStructs {
-		logStructs = append(logStructs, lgs)
+	alertReplay, err := NewPersistentReplayBuffer(size, filepath.Join(dir, "alerts.replay"), func() proto.Message { return &pb.Alert{} })
+	if err != nil {
+		msgReplay.Close()
+		return err
 	}
 
-	return logStructs
-}
+	logReplay, err := NewPersistentReplayBuffer(size, filepath.Join(dir, "logs.replay"), func() proto.Message { return &pb.Log{} })
+	if err != nil {
+		msgReplay.Close()
+		alertReplay.Close()
+		return err
+	}
 
-// WatchLogs Function
-func (ls *LogService) WatchLogs(req *pb.RequestMessage, svr pb.LogService_WatchLogsServer) error {
-	uid := uuid.Must(uuid.NewRandom()).String()
+	ls.MsgBroadcaster.SetReplayBuffer(msgReplay)
+	ls.AlertBroadcaster.SetReplayBuffer(alertReplay)
+	ls.LogBroadcaster.SetReplayBuffer(logReplay)
 
-	ls.addLogStruct(uid, svr, req.Filter)
-	defer ls.removeLogStruct(uid)
+	return nil
+}
 
-	for Running {
-		//nolint
-		log := <-LogQueue
+// Stop Function
+func (ls *LogService) Stop() {
+	close(ls.StopChan)
 
-		logStructs := ls.getLogStructs()
-		for _, lgs := range logStructs {
-			if err := lgs.Client.Send(&log); err != nil {
-				kg.Err("Failed to send a log")
-			}
-		}
-	}
+	ls.StopOutputSinks()
 
-	return nil
+	ls.MsgBroadcaster.CloseAll()
+	ls.AlertBroadcaster.CloseAll()
+	ls.LogBroadcaster.CloseAll()
 }
 
 // ============ //
@@ -267,20 +399,23 @@ type Feeder struct {
 	ClusterName string
 
 	// node
-	Node *tp.Node
+	Node     *tp.Node
+	NodeLock *sync.RWMutex
 
 	// port
 	Port string
 
 	// output
-	Output  string
-	LogFile *os.File
+	Output     string
+	LogFile    *os.File
+	OutputLock *sync.Mutex
 
 	// gRPC listener
 	Listener net.Listener
 
 	// log server
-	LogServer *grpc.Server
+	LogServer  *grpc.Server
+	LogService *LogService
 
 	// wait group
 	WgServer sync.WaitGroup
@@ -291,6 +426,9 @@ type Feeder struct {
 
 	// GKE
 	IsGKE bool
+
+	// rules that the enforcer in use can enforce (nil if it enforces every rule)
+	IsEnforceable func(rule interface{}) bool
 }
 
 // NewFeeder Function
@@ -302,40 +440,23 @@ func NewFeeder(clusterName string, node *tp.Node, port, output string) *Feeder {
 
 	// node
 	fd.Node = node
+	fd.NodeLock = new(sync.RWMutex)
 
 	// gRPC configuration
 	fd.Port = fmt.Sprintf(":%s", port)
 
 	// output
 	fd.Output = output
+	fd.OutputLock = new(sync.Mutex)
 
 	// output mode
 	if fd.Output != "stdout" && fd.Output != "none" {
-		// get the directory part from the path
-		dirLog := filepath.Dir(fd.Output)
-
-		// create directories
-		if err := os.MkdirAll(filepath.Clean(dirLog), 0750); err != nil {
-			kg.Errf("Failed to create a target directory (%s, %s)", dirLog, err.Error())
-			return nil
-		}
-
-		// create target file
-		targetFile, err := os.Create(filepath.Clean(fd.Output))
-		if err != nil {
-			kg.Errf("Failed to create a target file (%s, %s)", fd.Output, err.Error())
-			return nil
-		}
-		if err := targetFile.Close(); err != nil {
-			kg.Err(err.Error())
-		}
-
-		// open the file with the append mode
-		fd.LogFile, err = os.OpenFile(filepath.Clean(fd.Output), os.O_WRONLY|os.O_APPEND, 0600)
+		logFile, err := openLogFile(fd.Output, true)
 		if err != nil {
 			kg.Err(err.Error())
 			return nil
 		}
+		fd.LogFile = logFile
 	}
 
 	// listen to gRPC port
@@ -350,19 +471,17 @@ func NewFeeder(clusterName string, node *tp.Node, port, output string) *Feeder {
 	fd.LogServer = grpc.NewServer()
 
 	// register a log service
-	logService := &LogService{
-		MsgStructs:   make(map[string]MsgStruct),
-		MsgLock:      &sync.Mutex{},
-		AlertStructs: make(map[string]AlertStruct),
-		AlertLock:    &sync.Mutex{},
-		LogStructs:   make(map[string]LogStruct),
-		LogLock:      &sync.Mutex{},
-	}
-	pb.RegisterLogServiceServer(fd.LogServer, logService)
+	fd.LogService = NewLogService(DefaultSubscriberBufferSize, DefaultSlowSubscriberPolicy)
+	pb.RegisterLogServiceServer(fd.LogServer, fd.LogService)
 
 	// set wait group
 	fd.WgServer = sync.WaitGroup{}
 
+	// start dispatchers
+	go fd.LogService.DispatchMessages()
+	go fd.LogService.DispatchAlerts()
+	go fd.LogService.DispatchLogs()
+
 	// initialize security policies
 	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
 	fd.SecurityPoliciesLock = new(sync.RWMutex)
@@ -380,11 +499,43 @@ func NewFeeder(clusterName string, node *tp.Node, port, output string) *Feeder {
 	return fd
 }
 
+// getNode Function
+func (fd *Feeder) getNode() tp.Node {
+	fd.NodeLock.RLock()
+	defer fd.NodeLock.RUnlock()
+
+	return *fd.Node
+}
+
+// NewPolicyMatcher Function
+func NewPolicyMatcher(node *tp.Node) *Feeder {
+	fd := &Feeder{}
+
+	// node
+	fd.Node = node
+	fd.NodeLock = new(sync.RWMutex)
+
+	// no output, only for matching logs with security policies
+	fd.Output = "none"
+	fd.OutputLock = new(sync.Mutex)
+
+	// initialize security policies
+	fd.SecurityPolicies = map[string]tp.MatchPolicies{}
+	fd.SecurityPoliciesLock = new(sync.RWMutex)
+
+	return fd
+}
+
 // DestroyFeeder Function
 func (fd *Feeder) DestroyFeeder() error {
 	// stop gRPC service
 	Running = false
 
+	// stop dispatchers and release subscribers
+	if fd.LogService != nil {
+		fd.LogService.Stop()
+	}
+
 	// wait for a while
 	time.Sleep(time.Second * 1)
 
@@ -397,12 +548,14 @@ func (fd *Feeder) DestroyFeeder() error {
 	}
 
 	// close LogFile
+	fd.OutputLock.Lock()
 	if fd.LogFile != nil {
 		if err := fd.LogFile.Close(); err != nil {
 			kg.Err(err.Error())
 		}
 		fd.LogFile = nil
 	}
+	fd.OutputLock.Unlock()
 
 	// wait for other routines
 	fd.WgServer.Wait()
@@ -410,6 +563,94 @@ func (fd *Feeder) DestroyFeeder() error {
 	return nil
 }
 
+// SetSubscriberOptions Function
+func (fd *Feeder) SetSubscriberOptions(bufferSize int, policy string) {
+	fd.LogService.SetSubscriberOptions(bufferSize, policy)
+}
+
+// SetReplayOptions Function
+func (fd *Feeder) SetReplayOptions(size int, dir string) error {
+	return fd.LogService.SetReplayOptions(size, dir)
+}
+
+// SetHealthProvider Function
+func (fd *Feeder) SetHealthProvider(provider func() []tp.HealthStatus) {
+	fd.LogService.HealthProvider = provider
+}
+
+// GetHealthStatus Function
+func (fd *Feeder) GetHealthStatus() tp.HealthStatus {
+	return fd.LogService.GetHealthStatus()
+}
+
+// SetOutputSinks Function
+func (fd *Feeder) SetOutputSinks(configPath string, sinks []SinkConfig) error {
+	configs := []SinkConfig{}
+
+	if configPath != "" {
+		config, err := LoadOutputSinksConfig(configPath)
+		if err != nil {
+			return err
+		}
+		configs = append(configs, config.Sinks...)
+	}
+
+	configs = append(configs, sinks...)
+
+	return fd.LogService.UpdateOutputSinks(configs)
+}
+
+// SetOutput Function
+func (fd *Feeder) SetOutput(output string) error {
+	var logFile *os.File
+
+	if output != "stdout" && output != "none" {
+		file, err := openLogFile(output, false)
+		if err != nil {
+			return err
+		}
+		logFile = file
+	}
+
+	fd.OutputLock.Lock()
+	defer fd.OutputLock.Unlock()
+
+	if fd.LogFile != nil {
+		if err := fd.LogFile.Close(); err != nil {
+			kg.Err(err.Error())
+		}
+	}
+
+	fd.Output = output
+	fd.LogFile = logFile
+
+	return nil
+}
+
+// openLogFile Function
+func openLogFile(path string, truncate bool) (*os.File, error) {
+	// get the directory part from the path
+	dirLog := filepath.Dir(path)
+
+	// create directories
+	if err := os.MkdirAll(filepath.Clean(dirLog), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create a target directory (%s, %s)", dirLog, err.Error())
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if truncate {
+		flags |= os.O_TRUNC
+	}
+
+	// open the file with the append mode
+	logFile, err := os.OpenFile(filepath.Clean(path), flags, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a target file (%s, %s)", path, err.Error())
+	}
+
+	return logFile, nil
+}
+
 // StrToFile Function
 func (fd *Feeder) StrToFile(str string) {
 	if fd.LogFile != nil {
@@ -511,27 +752,72 @@ func (fd *Feeder) PushMessage(level, message string) {
 
 	pbMsg.ClusterName = fd.ClusterName
 
-	pbMsg.HostName = fd.Node.NodeName
-	pbMsg.HostIP = fd.Node.NodeIP
+	pbMsg.HostName = fd.getNode().NodeName
+	pbMsg.HostIP = fd.getNode().NodeIP
 
 	pbMsg.Type = "Message"
 
 	pbMsg.Level = level
 	pbMsg.Message = message
 
-	MsgQueue <- pbMsg
+	MsgQueue <- &pbMsg
+}
+
+// toFileEvent Function
+func toFileEvent(event *tp.FileEvent) *pb.FileEvent {
+	if event == nil {
+		return nil
+	}
+
+	return &pb.FileEvent{Path: event.Path, Flags: event.Flags, FD: event.FD}
+}
+
+// toNetworkEvent Function
+func toNetworkEvent(event *tp.NetworkEvent) *pb.NetworkEvent {
+	if event == nil {
+		return nil
+	}
+
+	return &pb.NetworkEvent{Family: event.Family, Addr: event.Addr, Port: event.Port, Protocol: event.Protocol,
+		SockType: event.SockType, FD: event.FD, Path: event.Path}
+}
+
+// toProcessEvent Function
+func toProcessEvent(event *tp.ProcessEvent) *pb.ProcessEvent {
+	if event == nil {
+		return nil
+	}
+
+	return &pb.ProcessEvent{ExecPath: event.ExecPath, Args: event.Args, Comm: event.Comm, Flags: event.Flags, FD: event.FD}
+}
+
+// toProcessAncestors Function
+func toProcessAncestors(ancestors []tp.ProcessAncestor) []*pb.ProcessAncestor {
+	if len(ancestors) == 0 {
+		return nil
+	}
+
+	res := make([]*pb.ProcessAncestor, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		res = append(res, &pb.ProcessAncestor{HostPID: ancestor.HostPID, PID: ancestor.PID, Comm: ancestor.Comm,
+			ExecPath: ancestor.ExecPath, Exited: ancestor.Exited})
+	}
+
+	return res
 }
 
 // PushLog Function
 func (fd *Feeder) PushLog(log tp.Log) {
+	matchStart := time.Now()
 	log = fd.UpdateMatchedPolicy(log)
+	mt.PolicyMatchDuration.WithLabelValues(log.Operation).Observe(time.Since(matchStart).Seconds())
 
 	if log.UpdatedTime == "" {
 		return
 	}
 
 	// set hostname
-	log.HostName = fd.Node.NodeName
+	log.HostName = fd.getNode().NodeName
 
 	// remove flags
 	log.PolicyEnabled = 0
@@ -541,6 +827,7 @@ func (fd *Feeder) PushLog(log tp.Log) {
 	log.CapabilitiesVisibilityEnabled = false
 
 	// standard output / file output
+	fd.OutputLock.Lock()
 	if fd.Output == "stdout" {
 		arr, _ := json.Marshal(log)
 		fmt.Println(string(arr))
@@ -548,6 +835,7 @@ func (fd *Feeder) PushLog(log tp.Log) {
 		arr, _ := json.Marshal(log)
 		fd.StrToFile(string(arr))
 	}
+	fd.OutputLock.Unlock()
 
 	// gRPC output
 	if log.Type == "MatchedPolicy" || log.Type == "MatchedHostPolicy" || log.Type == "MatchedNativePolicy" {
@@ -557,7 +845,7 @@ func (fd *Feeder) PushLog(log tp.Log) {
 		pbAlert.UpdatedTime = log.UpdatedTime
 
 		pbAlert.ClusterName = fd.ClusterName
-		pbAlert.HostName = fd.Node.NodeName
+		pbAlert.HostName = fd.getNode().NodeName
 
 		pbAlert.NamespaceName = log.NamespaceName
 		pbAlert.PodName = log.PodName
@@ -600,7 +888,15 @@ func (fd *Feeder) PushLog(log tp.Log) {
 
 		pbAlert.Result = log.Result
 
-		AlertQueue <- pbAlert
+		pbAlert.File = toFileEvent(log.File)
+		pbAlert.Network = toNetworkEvent(log.Network)
+		pbAlert.Process = toProcessEvent(log.Process)
+
+		pbAlert.Ancestors = toProcessAncestors(log.Ancestors)
+
+		mt.Alerts.WithLabelValues(log.NamespaceName, log.PolicyName, log.Operation, log.Result).Inc()
+
+		AlertQueue <- &pbAlert
 	} else { // ContainerLog
 		pbLog := pb.Log{}
 
@@ -608,7 +904,7 @@ func (fd *Feeder) PushLog(log tp.Log) {
 		pbLog.UpdatedTime = log.UpdatedTime
 
 		pbLog.ClusterName = fd.ClusterName
-		pbLog.HostName = fd.Node.NodeName
+		pbLog.HostName = fd.getNode().NodeName
 
 		pbLog.NamespaceName = log.NamespaceName
 		pbLog.PodName = log.PodName
@@ -631,6 +927,14 @@ func (fd *Feeder) PushLog(log tp.Log) {
 
 		pbLog.Result = log.Result
 
-		LogQueue <- pbLog
+		pbLog.File = toFileEvent(log.File)
+		pbLog.Network = toNetworkEvent(log.Network)
+		pbLog.Process = toProcessEvent(log.Process)
+
+		pbLog.Ancestors = toProcessAncestors(log.Ancestors)
+
+		mt.Logs.WithLabelValues(log.NamespaceName, log.Operation, log.Result).Inc()
+
+		LogQueue <- &pbLog
 	}
 }
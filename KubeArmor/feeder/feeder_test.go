@@ -4,9 +4,11 @@
 package feeder
 
 import (
+	"context"
 	"testing"
 
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+	pb "github.com/kubearmor/KubeArmor/protobuf"
 )
 
 func TestFeeder(t *testing.T) {
@@ -30,3 +32,33 @@ func TestFeeder(t *testing.T) {
 	}
 	t.Log("[PASS] Destroyed logger")
 }
+
+func TestHealthCheck(t *testing.T) {
+	ls := NewLogService(DefaultSubscriberBufferSize, DefaultSlowSubscriberPolicy)
+
+	sub := ls.AlertBroadcaster.Subscribe("test", nil)
+	defer ls.AlertBroadcaster.Unsubscribe(sub.UID)
+
+	reply, err := ls.HealthCheck(context.Background(), &pb.NonceMessage{Nonce: 42})
+	if err != nil || reply.Retval != 42 || reply.Status != "OK" {
+		t.Error("[FAIL] Failed to check the health of the feeder")
+		return
+	}
+
+	if len(reply.Subsystems) != 1 || reply.Subsystems[0].Details["alertSubscribers"] != "1" {
+		t.Errorf("[FAIL] Got unexpected subsystems (%v)", reply.Subsystems)
+		return
+	}
+	t.Log("[PASS] Reported the health of the feeder")
+
+	ls.HealthProvider = func() []tp.HealthStatus {
+		return []tp.HealthStatus{ls.GetHealthStatus(), {Name: "systemMonitor", Healthy: false, Message: "no probes attached"}}
+	}
+
+	reply, err = ls.HealthCheck(context.Background(), &pb.NonceMessage{Nonce: 1})
+	if err != nil || reply.Status != "Unhealthy" || len(reply.Subsystems) != 2 {
+		t.Error("[FAIL] Failed to report an unhealthy subsystem")
+		return
+	}
+	t.Log("[PASS] Reported an unhealthy subsystem")
+}
@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+)
+
+// =============== //
+// == File Sink == //
+// =============== //
+
+// FileSinkConfig Structure
+type FileSinkConfig struct {
+	// path to the JSON-lines file
+	Path string `json:"path"`
+
+	// rotate the file once it grows beyond maxSize (MB, 0: no limit)
+	MaxSize int `json:"maxSize,omitempty"`
+
+	// rotate the file once it gets older than maxAge (e.g., 24h, empty: no limit)
+	MaxAge string `json:"maxAge,omitempty"`
+
+	// number of rotated files to keep (0: keep all)
+	MaxBackups int `json:"maxBackups,omitempty"`
+
+	// gzip rotated files
+	Compress bool `json:"compress,omitempty"`
+}
+
+// FileSink Structure
+type FileSink struct {
+	Config FileSinkConfig
+
+	maxSize int64
+	maxAge  time.Duration
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	fileLock *sync.Mutex
+
+	// compression of rotated files
+	wg sync.WaitGroup
+}
+
+// NewFileSink Function
+func NewFileSink(config SinkConfig) (OutputSink, error) {
+	if config.File == nil {
+		return nil, errors.New("no file configuration")
+	}
+
+	sink := &FileSink{Config: *config.File, fileLock: &sync.Mutex{}}
+
+	if sink.Config.Path == "" {
+		return nil, errors.New("no file path")
+	}
+
+	sink.maxSize = int64(sink.Config.MaxSize) * 1024 * 1024
+
+	if sink.Config.MaxAge != "" {
+		maxAge, err := time.ParseDuration(sink.Config.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxAge %q", sink.Config.MaxAge)
+		}
+		sink.maxAge = maxAge
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sink.Config.Path), 0750); err != nil {
+		return nil, err
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+// open Function
+func (sink *FileSink) open() error {
+	file, err := os.OpenFile(filepath.Clean(sink.Config.Path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	sink.file = file
+	sink.size = info.Size()
+	sink.openedAt = time.Now()
+
+	return nil
+}
+
+// Write Function
+func (sink *FileSink) Write(ctx context.Context, events []SinkEvent) error {
+	sink.fileLock.Lock()
+	defer sink.fileLock.Unlock()
+
+	if sink.file == nil {
+		if err := sink.open(); err != nil {
+			return err
+		}
+	}
+
+	if sink.maxAge > 0 && time.Since(sink.openedAt) >= sink.maxAge && sink.size > 0 {
+		if err := sink.rotate(); err != nil {
+			return err
+		}
+	}
+
+	writer := bufio.NewWriter(sink.file)
+
+	for _, event := range events {
+		data, err := sinkEventJSON(event)
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if sink.maxSize > 0 && sink.size > 0 && sink.size+int64(len(data)) > sink.maxSize {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+
+			if err := sink.rotate(); err != nil {
+				return err
+			}
+
+			writer = bufio.NewWriter(sink.file)
+		}
+
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+
+		sink.size += int64(len(data))
+	}
+
+	return writer.Flush()
+}
+
+// rotate Function
+func (sink *FileSink) rotate() error {
+	if err := sink.file.Close(); err != nil {
+		return err
+	}
+	sink.file = nil
+
+	rotated := fmt.Sprintf("%s.%s", sink.Config.Path, time.Now().UTC().Format("20060102T150405.000000000"))
+
+	if err := os.Rename(sink.Config.Path, rotated); err != nil {
+		return err
+	}
+
+	if sink.Config.Compress {
+		sink.wg.Add(1)
+		go func() {
+			defer sink.wg.Done()
+
+			if err := gzipFile(rotated); err != nil {
+				kg.Warnf("Failed to compress %s (%s)", rotated, err.Error())
+			}
+
+			sink.removeOldBackups()
+		}()
+	} else {
+		sink.removeOldBackups()
+	}
+
+	return sink.open()
+}
+
+// removeOldBackups Function
+func (sink *FileSink) removeOldBackups() {
+	if sink.Config.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(sink.Config.Path + ".*")
+	if err != nil {
+		return
+	}
+
+	// skip the files being compressed
+	filtered := []string{}
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, ".tmp") {
+			filtered = append(filtered, backup)
+		}
+	}
+
+	// the names contain timestamps, so the oldest ones come first
+	sort.Strings(filtered)
+
+	for len(filtered) > sink.Config.MaxBackups {
+		if err := os.Remove(filtered[0]); err != nil && !os.IsNotExist(err) {
+			kg.Warnf("Failed to remove %s (%s)", filtered[0], err.Error())
+		}
+		filtered = filtered[1:]
+	}
+}
+
+// gzipFile Function
+func gzipFile(path string) error {
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmpPath := path + ".gz.tmp"
+
+	dst, err := os.OpenFile(filepath.Clean(tmpPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path+".gz"); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Close Function
+func (sink *FileSink) Close() error {
+	sink.fileLock.Lock()
+	defer sink.fileLock.Unlock()
+
+	// wait for compression
+	sink.wg.Wait()
+
+	if sink.file == nil {
+		return nil
+	}
+
+	err := sink.file.Close()
+	sink.file = nil
+
+	return err
+}
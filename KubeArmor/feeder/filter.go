@@ -0,0 +1,634 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ================= //
+// == Filter Expr == //
+// ================= //
+
+// A filter expression is evaluated against every message, alert or log before it is sent to a subscriber.
+//
+//   expr       := orExpr
+//   orExpr     := andExpr ( "||" andExpr )*
+//   andExpr    := unaryExpr ( "&&" unaryExpr )*
+//   unaryExpr  := "!" unaryExpr | "(" expr ")" | comparison
+//   comparison := field ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) value
+//               | field "=~" string
+//               | field [ "not" ] "in" "[" value ( "," value )* "]"
+//   value      := string | number | "true" | "false"
+//
+// Field names are matched case-insensitively against the protobuf fields (e.g., namespaceName, operation, severity),
+// and nested fields can be referred to with dots. Numeric comparisons are applied to string fields (e.g., severity)
+// if their values can be parsed as numbers.
+//
+// ex) namespaceName == "prod" && operation in ["Process","Network"] && severity >= 5
+
+// legacy filters of the log client (-logFilter={policy|system|all}), which selected streams rather than items
+var legacyFilters = map[string]bool{
+	"all":    true,
+	"policy": true,
+	"system": true,
+}
+
+// Filter Structure
+type Filter struct {
+	Expr string
+	root filterNode
+}
+
+// filterNode Interface
+type filterNode interface {
+	eval(msg protoreflect.Message) bool
+}
+
+// CompileFilter Function
+func CompileFilter(expr string, desc protoreflect.MessageDescriptor) (*Filter, error) {
+	trimmed := strings.TrimSpace(expr)
+
+	// no filter (or a legacy filter for backward compatibility)
+	if trimmed == "" || legacyFilters[strings.ToLower(trimmed)] {
+		return nil, nil
+	}
+
+	tokens, err := tokenizeFilter(trimmed)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, desc: desc}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	return &Filter{Expr: trimmed, root: root}, nil
+}
+
+// Match Function
+func (f *Filter) Match(item interface{}) bool {
+	if f == nil {
+		return true
+	}
+
+	msg, ok := item.(proto.Message)
+	if !ok {
+		return false
+	}
+
+	return f.root.eval(msg.ProtoReflect())
+}
+
+// ============ //
+// == Lexer == //
+// ============ //
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// tokenizeFilter Function
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	tokens := []filterToken{}
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, filterToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{tokRParen, ")", i})
+			i++
+		case c == '[':
+			tokens = append(tokens, filterToken{tokLBracket, "[", i})
+			i++
+		case c == ']':
+			tokens = append(tokens, filterToken{tokRBracket, "]", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, filterToken{tokComma, ",", i})
+			i++
+
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(expr) && expr[end] != c {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+
+			raw := expr[i : end+1]
+			if c == '\'' {
+				raw = `"` + strings.ReplaceAll(expr[i+1:end], `"`, `\"`) + `"`
+			}
+
+			str, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", i)
+			}
+
+			tokens = append(tokens, filterToken{tokString, str, i})
+			i = end + 1
+
+		case strings.ContainsRune("=!<>&|", rune(c)):
+			op := ""
+			if i+1 < len(expr) {
+				switch two := expr[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "=~", "&&", "||":
+					op = two
+				}
+			}
+			if op == "" {
+				switch c {
+				case '<', '>', '!':
+					op = string(c)
+				default:
+					return nil, fmt.Errorf("unexpected %q at position %d", string(c), i)
+				}
+			}
+
+			tokens = append(tokens, filterToken{tokOp, op, i})
+			i += len(op)
+
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(expr) && (expr[end] == '.' || (expr[end] >= '0' && expr[end] <= '9')) {
+				end++
+			}
+			if _, err := strconv.ParseFloat(expr[i:end], 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", expr[i:end], i)
+			}
+
+			tokens = append(tokens, filterToken{tokNumber, expr[i:end], i})
+			i = end
+
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(expr) && (expr[end] == '_' || expr[end] == '.' || unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+
+			tokens = append(tokens, filterToken{tokIdent, expr[i:end], i})
+			i = end
+
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", string(c), i)
+		}
+	}
+
+	tokens = append(tokens, filterToken{tokEOF, "end of filter", len(expr)})
+
+	return tokens, nil
+}
+
+// ============ //
+// == Parser == //
+// ============ //
+
+type filterParser struct {
+	tokens []filterToken
+	cur    int
+	desc   protoreflect.MessageDescriptor
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.cur]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.cur]
+	if tok.kind != tokEOF {
+		p.cur++
+	}
+	return tok
+}
+
+func (p *filterParser) expect(kind filterTokenKind, text string) (filterToken, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("expected %s but got %q at position %d", text, tok.text, tok.pos)
+	}
+	return tok, nil
+}
+
+// parseOr Function
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok := p.peek(); tok.kind == tokOp && tok.text == "||"; tok = p.peek() {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd Function
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok := p.peek(); tok.kind == tokOp && tok.text == "&&"; tok = p.peek() {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary Function
+func (p *filterParser) parseUnary() (filterNode, error) {
+	tok := p.peek()
+
+	if tok.kind == tokOp && tok.text == "!" {
+		p.next()
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notNode{node: node}, nil
+	}
+
+	if tok.kind == tokLParen {
+		p.next()
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison Function
+func (p *filterParser) parseComparison() (filterNode, error) {
+	tok, err := p.expect(tokIdent, "a field name")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resolveFilterField(p.desc, tok.text)
+	if err != nil {
+		return nil, fmt.Errorf("%s at position %d", err.Error(), tok.pos)
+	}
+
+	op := p.next()
+
+	// [not] in [ ... ]
+	if op.kind == tokIdent && (strings.EqualFold(op.text, "in") || strings.EqualFold(op.text, "not")) {
+		negate := false
+
+		if strings.EqualFold(op.text, "not") {
+			if in := p.next(); in.kind != tokIdent || !strings.EqualFold(in.text, "in") {
+				return nil, fmt.Errorf("expected 'in' but got %q at position %d", in.text, in.pos)
+			}
+			negate = true
+		}
+
+		if _, err := p.expect(tokLBracket, "'['"); err != nil {
+			return nil, err
+		}
+
+		values := []filterValue{}
+
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+
+			sep := p.next()
+			if sep.kind == tokRBracket {
+				break
+			} else if sep.kind != tokComma {
+				return nil, fmt.Errorf("expected ',' or ']' but got %q at position %d", sep.text, sep.pos)
+			}
+		}
+
+		var node filterNode = &inNode{path: path, values: values}
+		if negate {
+			node = &notNode{node: node}
+		}
+
+		return node, nil
+	}
+
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator but got %q at position %d", op.text, op.pos)
+	}
+
+	switch op.text {
+	case "=~":
+		pattern, err := p.expect(tokString, "a regular expression")
+		if err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at position %d (%s)", pattern.pos, err.Error())
+		}
+
+		return &regexNode{path: path, re: re}, nil
+
+	case "==", "!=", "<", "<=", ">", ">=":
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if op.text != "==" && op.text != "!=" && !value.isNum {
+			return nil, fmt.Errorf("expected a number after %q at position %d", op.text, op.pos)
+		}
+
+		return &compareNode{path: path, op: op.text, value: value}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", op.text, op.pos)
+}
+
+// parseValue Function
+func (p *filterParser) parseValue() (filterValue, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokString:
+		return newFilterValue(tok.text), nil
+	case tokNumber:
+		return newFilterValue(tok.text), nil
+	case tokIdent:
+		if strings.EqualFold(tok.text, "true") || strings.EqualFold(tok.text, "false") {
+			return newFilterValue(strings.ToLower(tok.text)), nil
+		}
+	}
+
+	return filterValue{}, fmt.Errorf("expected a value but got %q at position %d", tok.text, tok.pos)
+}
+
+// resolveFilterField Function
+func resolveFilterField(desc protoreflect.MessageDescriptor, name string) ([]protoreflect.FieldDescriptor, error) {
+	path := []protoreflect.FieldDescriptor{}
+
+	for idx, part := range strings.Split(name, ".") {
+		if desc == nil {
+			return nil, fmt.Errorf("%q is not a message field", strings.Join(strings.Split(name, ".")[:idx], "."))
+		}
+
+		var field protoreflect.FieldDescriptor
+
+		fields := desc.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if strings.EqualFold(string(fields.Get(i).Name()), part) || strings.EqualFold(fields.Get(i).JSONName(), part) {
+				field = fields.Get(i)
+				break
+			}
+		}
+
+		if field == nil || field.IsMap() {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+
+		path = append(path, field)
+		desc = field.Message()
+	}
+
+	if desc != nil {
+		return nil, fmt.Errorf("%q is not a scalar field", name)
+	}
+
+	return path, nil
+}
+
+// ============ //
+// == Values == //
+// ============ //
+
+type filterValue struct {
+	str   string
+	num   float64
+	isNum bool
+}
+
+// newFilterValue Function
+func newFilterValue(str string) filterValue {
+	value := filterValue{str: str}
+
+	if num, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+		value.num = num
+		value.isNum = true
+	}
+
+	return value
+}
+
+// equals Function
+func (v filterValue) equals(o filterValue) bool {
+	if v.isNum && o.isNum {
+		return v.num == o.num
+	}
+	return v.str == o.str
+}
+
+// getFilterValues Function
+func getFilterValues(msg protoreflect.Message, path []protoreflect.FieldDescriptor) []filterValue {
+	for _, field := range path[:len(path)-1] {
+		if field.IsList() {
+			return nil
+		}
+		msg = msg.Get(field).Message()
+	}
+
+	field := path[len(path)-1]
+
+	if field.IsList() {
+		list := msg.Get(field).List()
+
+		values := make([]filterValue, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, scalarFilterValue(field, list.Get(i)))
+		}
+
+		return values
+	}
+
+	return []filterValue{scalarFilterValue(field, msg.Get(field))}
+}
+
+// scalarFilterValue Function
+func scalarFilterValue(field protoreflect.FieldDescriptor, value protoreflect.Value) filterValue {
+	if field.Kind() == protoreflect.EnumKind {
+		if enum := field.Enum().Values().ByNumber(value.Enum()); enum != nil {
+			return newFilterValue(string(enum.Name()))
+		}
+	}
+
+	return newFilterValue(value.String())
+}
+
+// =========== //
+// == Nodes == //
+// =========== //
+
+type orNode struct {
+	left, right filterNode
+}
+
+func (n *orNode) eval(msg protoreflect.Message) bool {
+	return n.left.eval(msg) || n.right.eval(msg)
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+func (n *andNode) eval(msg protoreflect.Message) bool {
+	return n.left.eval(msg) && n.right.eval(msg)
+}
+
+type notNode struct {
+	node filterNode
+}
+
+func (n *notNode) eval(msg protoreflect.Message) bool {
+	return !n.node.eval(msg)
+}
+
+type compareNode struct {
+	path  []protoreflect.FieldDescriptor
+	op    string
+	value filterValue
+}
+
+func (n *compareNode) eval(msg protoreflect.Message) bool {
+	for _, v := range getFilterValues(msg, n.path) {
+		if n.compare(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *compareNode) compare(v filterValue) bool {
+	switch n.op {
+	case "==":
+		return v.equals(n.value)
+	case "!=":
+		return !v.equals(n.value)
+	}
+
+	if !v.isNum {
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return v.num < n.value.num
+	case "<=":
+		return v.num <= n.value.num
+	case ">":
+		return v.num > n.value.num
+	case ">=":
+		return v.num >= n.value.num
+	}
+
+	return false
+}
+
+type inNode struct {
+	path   []protoreflect.FieldDescriptor
+	values []filterValue
+}
+
+func (n *inNode) eval(msg protoreflect.Message) bool {
+	for _, v := range getFilterValues(msg, n.path) {
+		for _, value := range n.values {
+			if v.equals(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type regexNode struct {
+	path []protoreflect.FieldDescriptor
+	re   *regexp.Regexp
+}
+
+func (n *regexNode) eval(msg protoreflect.Message) bool {
+	for _, v := range getFilterValues(msg, n.path) {
+		if n.re.MatchString(v.str) {
+			return true
+		}
+	}
+	return false
+}
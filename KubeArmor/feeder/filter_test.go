@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"testing"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+)
+
+func TestCompileFilter(t *testing.T) {
+	alertDesc := (&pb.Alert{}).ProtoReflect().Descriptor()
+
+	// empty filters
+
+	for _, expr := range []string{"", " ", "all"} {
+		if filter, err := CompileFilter(expr, alertDesc); err != nil || filter != nil {
+			t.Errorf("[FAIL] Failed to accept %q as an empty filter", expr)
+			return
+		}
+	}
+	t.Log("[PASS] Accepted empty filters")
+
+	// legacy filters of the log client (-logFilter={policy|system|all})
+
+	logDesc := (&pb.Log{}).ProtoReflect().Descriptor()
+
+	for _, expr := range []string{"policy", "system", "all", "Policy"} {
+		alertFilter, err1 := CompileFilter(expr, alertDesc)
+		logFilter, err2 := CompileFilter(expr, logDesc)
+
+		if err1 != nil || err2 != nil || alertFilter != nil || logFilter != nil {
+			t.Errorf("[FAIL] Failed to accept a legacy filter (%s)", expr)
+			return
+		}
+
+		if !alertFilter.Match(&pb.Alert{PolicyName: "test"}) || !logFilter.Match(&pb.Log{Type: "ContainerLog"}) {
+			t.Errorf("[FAIL] Filtered items with a legacy filter (%s)", expr)
+			return
+		}
+		t.Logf("[PASS] Accepted a legacy filter (%s)", expr)
+	}
+
+	// invalid filters
+
+	invalid := []string{
+		`namespaceName ==`,
+		`namespaceName = "prod"`,
+		`unknownField == "prod"`,
+		`namespaceName == "prod" &&`,
+		`(namespaceName == "prod"`,
+		`operation in ["Process", "Network"`,
+		`operation in []`,
+		`severity >= "high"`,
+		`resource =~ "("`,
+		`namespaceName == "prod`,
+		`namespaceName`,
+	}
+
+	for _, expr := range invalid {
+		if _, err := CompileFilter(expr, alertDesc); err == nil {
+			t.Errorf("[FAIL] Failed to reject %q", expr)
+			return
+		}
+	}
+	t.Log("[PASS] Rejected invalid filters")
+}
+
+func TestFilterMatch(t *testing.T) {
+	alert := &pb.Alert{
+		NamespaceName: "prod",
+		PodName:       "nginx-7d8b49557c-5kgvw",
+		HostPID:       1234,
+		Severity:      "7",
+		Operation:     "Process",
+		Resource:      "/bin/bash",
+		Action:        "Block",
+	}
+
+	alertDesc := alert.ProtoReflect().Descriptor()
+
+	cases := map[string]bool{
+		`namespaceName == "prod"`: true,
+		`NamespaceName == 'prod'`: true,
+		`namespaceName != "prod"`: false,
+		`namespaceName == "prod" && operation in ["Process","Network"] && severity >= 5`: true,
+		`namespaceName == "prod" && operation in ["File","Network"]`:                     false,
+		`operation not in ["File","Network"]`:                                            true,
+		`namespaceName == "dev" || action == "Block"`:                                    true,
+		`!(namespaceName == "prod")`:                                                     false,
+		`severity > 7`:                                                                   false,
+		`severity == 7.0`:                                                                true,
+		`hostPID < 2000 && hostPID >= 1234`:                                              true,
+		`resource =~ "^/bin/(ba)?sh$"`:                                                   true,
+		`podName =~ "^redis-"`:                                                           false,
+		`containerName >= 1`:                                                             false,
+	}
+
+	for expr, expected := range cases {
+		filter, err := CompileFilter(expr, alertDesc)
+		if err != nil {
+			t.Errorf("[FAIL] Failed to compile %q (%s)", expr, err.Error())
+			return
+		}
+
+		if filter.Match(alert) != expected {
+			t.Errorf("[FAIL] %q should return %v", expr, expected)
+			return
+		}
+	}
+	t.Log("[PASS] Matched alerts")
+
+	// log filters
+
+	logDesc := (&pb.Log{}).ProtoReflect().Descriptor()
+
+	if _, err := CompileFilter(`action == "Block"`, logDesc); err == nil {
+		t.Error("[FAIL] Failed to reject an alert-only field in a log filter")
+		return
+	}
+
+	filter, err := CompileFilter(`operation == "Network" && result != "Passed"`, logDesc)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to compile a log filter (%s)", err.Error())
+		return
+	}
+
+	if !filter.Match(&pb.Log{Operation: "Network", Result: "Permission denied"}) {
+		t.Error("[FAIL] Failed to match a log")
+		return
+	}
+
+	if filter.Match(&pb.Log{Operation: "Network", Result: "Passed"}) {
+		t.Error("[FAIL] Matched an unexpected log")
+		return
+	}
+	t.Log("[PASS] Matched logs")
+
+	// structured fields
+
+	filter, err = CompileFilter(`network.port == 443 && network.family == "AF_INET"`, logDesc)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to compile a filter with structured fields (%s)", err.Error())
+		return
+	}
+
+	if !filter.Match(&pb.Log{Operation: "Network", Network: &pb.NetworkEvent{Family: "AF_INET", Addr: "10.0.0.1", Port: 443}}) {
+		t.Error("[FAIL] Failed to match a log with structured fields")
+		return
+	}
+
+	if filter.Match(&pb.Log{Operation: "File", File: &pb.FileEvent{Path: "/etc/passwd"}}) {
+		t.Error("[FAIL] Matched a log without the structured fields")
+		return
+	}
+	t.Log("[PASS] Matched logs with structured fields")
+}
+
+func TestBroadcasterFilter(t *testing.T) {
+	filter, err := CompileFilter(`namespaceName == "prod"`, (&pb.Log{}).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to compile a filter (%s)", err.Error())
+		return
+	}
+
+	bc := NewBroadcaster(4, PolicyDisconnect)
+	sub := bc.Subscribe("prod", filter)
+
+	// unrelated logs should not fill up the buffer
+	for i := 0; i < 10; i++ {
+		bc.Broadcast(&pb.Log{NamespaceName: "dev"})
+	}
+	bc.Broadcast(&pb.Log{NamespaceName: "prod"})
+
+	if sub.IsDisconnected() || sub.Len() != 1 || sub.Filtered != 10 {
+		t.Errorf("[FAIL] Buffered %d logs and filtered %d logs (expected 1 and 10)", sub.Len(), sub.Filtered)
+		return
+	}
+	t.Log("[PASS] Filtered logs before buffering them")
+}
@@ -0,0 +1,456 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"sigs.k8s.io/yaml"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Streams
+const (
+	StreamMessage = "message"
+	StreamAlert   = "alert"
+	StreamLog     = "log"
+)
+
+// Default Sink Options
+const (
+	DefaultSinkBufferSize = 4096
+	DefaultSinkBatchSize  = 100
+)
+
+// ================= //
+// == Sink Config == //
+// ================= //
+
+// OutputSinksConfig Structure
+type OutputSinksConfig struct {
+	Sinks []SinkConfig `json:"sinks"`
+}
+
+// SinkConfig Structure
+type SinkConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// streams to export, {message|alert|log} (default: alert, log)
+	Streams []string `json:"streams,omitempty"`
+
+	// filter expression (see filter.go)
+	Filter string `json:"filter,omitempty"`
+
+	// number of events buffered for the sink (the oldest ones are dropped once full)
+	BufferSize int `json:"bufferSize,omitempty"`
+
+	// maximum number of events handed to the sink at once
+	BatchSize int `json:"batchSize,omitempty"`
+
+	Syslog  *SyslogSinkConfig  `json:"syslog,omitempty"`
+	File    *FileSinkConfig    `json:"file,omitempty"`
+	Webhook *WebhookSinkConfig `json:"webhook,omitempty"`
+}
+
+// LoadOutputSinksConfig Function
+func LoadOutputSinksConfig(path string) (OutputSinksConfig, error) {
+	config := OutputSinksConfig{}
+
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// ================= //
+// == Output Sink == //
+// ================= //
+
+// SinkEvent Structure
+type SinkEvent struct {
+	Stream string
+	Item   proto.Message
+}
+
+// OutputSink Interface
+type OutputSink interface {
+	// Write a batch of events (*pb.Message, *pb.Alert or *pb.Log), ctx is canceled when the sink is removed
+	Write(ctx context.Context, events []SinkEvent) error
+
+	// Close the sink
+	Close() error
+}
+
+// OutputSinkFactory Function Type
+type OutputSinkFactory func(config SinkConfig) (OutputSink, error)
+
+// outputSinkFactories (sink type -> factory)
+var outputSinkFactories map[string]OutputSinkFactory
+var outputSinkFactoriesLock *sync.RWMutex
+
+// init Function
+func init() {
+	outputSinkFactories = map[string]OutputSinkFactory{}
+	outputSinkFactoriesLock = new(sync.RWMutex)
+
+	RegisterOutputSink("syslog", NewSyslogSink)
+	RegisterOutputSink("file", NewFileSink)
+	RegisterOutputSink("webhook", NewWebhookSink)
+}
+
+// RegisterOutputSink Function
+func RegisterOutputSink(sinkType string, factory OutputSinkFactory) {
+	outputSinkFactoriesLock.Lock()
+	defer outputSinkFactoriesLock.Unlock()
+
+	outputSinkFactories[sinkType] = factory
+}
+
+// GetOutputSinkTypes Function
+func GetOutputSinkTypes() []string {
+	outputSinkFactoriesLock.RLock()
+	defer outputSinkFactoriesLock.RUnlock()
+
+	types := []string{}
+	for sinkType := range outputSinkFactories {
+		types = append(types, sinkType)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// newOutputSink Function
+func newOutputSink(config SinkConfig) (OutputSink, error) {
+	outputSinkFactoriesLock.RLock()
+	factory, ok := outputSinkFactories[config.Type]
+	outputSinkFactoriesLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown sink type %q (available: %v)", config.Type, GetOutputSinkTypes())
+	}
+
+	return factory(config)
+}
+
+// ================= //
+// == Sink Runner == //
+// ================= //
+
+// SinkRunner Structure
+type SinkRunner struct {
+	Config SinkConfig
+	Sink   OutputSink
+
+	// stream -> subscriber
+	Subscribers map[string]*Subscriber
+
+	// counters
+	Written uint64
+	Failed  uint64
+
+	// serialize writes from multiple streams
+	writeLock *sync.Mutex
+
+	// whether the last write failed
+	failing bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// write Function
+func (sr *SinkRunner) write(ctx context.Context, events []SinkEvent) {
+	sr.writeLock.Lock()
+	defer sr.writeLock.Unlock()
+
+	if err := sr.Sink.Write(ctx, events); err != nil {
+		atomic.AddUint64(&sr.Failed, uint64(len(events)))
+		mt.SinkEvents.WithLabelValues(sr.Config.Name, mt.ResultFailure).Add(float64(len(events)))
+
+		// report the first failure only
+		if !sr.failing {
+			kg.Warnf("Failed to write events into the %s sink (%s)", sr.Config.Name, err.Error())
+			sr.failing = true
+		}
+		return
+	}
+
+	atomic.AddUint64(&sr.Written, uint64(len(events)))
+	mt.SinkEvents.WithLabelValues(sr.Config.Name, mt.ResultSuccess).Add(float64(len(events)))
+
+	if sr.failing {
+		kg.Printf("Recovered the %s sink", sr.Config.Name)
+		sr.failing = false
+	}
+}
+
+// run Function
+func (sr *SinkRunner) run(ctx context.Context, stream string, sub *Subscriber) {
+	defer sr.wg.Done()
+
+	for {
+		items, ok := sub.NextBatch(ctx, sr.Config.BatchSize)
+		if !ok {
+			return
+		}
+
+		events := make([]SinkEvent, 0, len(items))
+		for _, item := range items {
+			if msg, ok := item.(proto.Message); ok {
+				events = append(events, SinkEvent{Stream: stream, Item: msg})
+			}
+		}
+
+		sr.write(ctx, events)
+	}
+}
+
+// stop Function
+func (sr *SinkRunner) stop() {
+	sr.cancel()
+	sr.wg.Wait()
+
+	if err := sr.Sink.Close(); err != nil {
+		kg.Warnf("Failed to close the %s sink (%s)", sr.Config.Name, err.Error())
+	}
+}
+
+// ======================= //
+// == Sink Registration == //
+// ======================= //
+
+// streamBroadcaster Function
+func (ls *LogService) streamBroadcaster(stream string) (*Broadcaster, protoreflect.MessageDescriptor, error) {
+	switch stream {
+	case StreamMessage:
+		return ls.MsgBroadcaster, (&pb.Message{}).ProtoReflect().Descriptor(), nil
+	case StreamAlert:
+		return ls.AlertBroadcaster, (&pb.Alert{}).ProtoReflect().Descriptor(), nil
+	case StreamLog:
+		return ls.LogBroadcaster, (&pb.Log{}).ProtoReflect().Descriptor(), nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown stream %q", stream)
+}
+
+// AddOutputSink Function
+func (ls *LogService) AddOutputSink(config SinkConfig) error {
+	if config.Name == "" {
+		return errors.New("no sink name")
+	}
+
+	config = normalizeSinkConfig(config)
+
+	ls.SinksLock.Lock()
+	defer ls.SinksLock.Unlock()
+
+	if _, ok := ls.Sinks[config.Name]; ok {
+		return fmt.Errorf("duplicated sink name %q", config.Name)
+	}
+
+	// compile the filter for each stream
+
+	filters := map[string]*Filter{}
+
+	for _, stream := range config.Streams {
+		_, desc, err := ls.streamBroadcaster(stream)
+		if err != nil {
+			return err
+		}
+
+		filter, err := CompileFilter(config.Filter, desc)
+		if err != nil {
+			return fmt.Errorf("invalid filter for the %s stream (%s)", stream, err.Error())
+		}
+
+		filters[stream] = filter
+	}
+
+	// create the sink
+
+	sink, err := newOutputSink(config)
+	if err != nil {
+		return err
+	}
+
+	sr := &SinkRunner{
+		Config:      config,
+		Sink:        sink,
+		Subscribers: map[string]*Subscriber{},
+		writeLock:   &sync.Mutex{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sr.cancel = cancel
+
+	// a slow sink only drops its own oldest events
+	for stream, filter := range filters {
+		bc, _, _ := ls.streamBroadcaster(stream)
+
+		sub := bc.SubscribeWith("sink-"+config.Name, filter, config.BufferSize, PolicyDropOldest)
+		sr.Subscribers[stream] = sub
+
+		sr.wg.Add(1)
+		go sr.run(ctx, stream, sub)
+	}
+
+	ls.Sinks[config.Name] = sr
+
+	return nil
+}
+
+// RemoveOutputSink Function
+func (ls *LogService) RemoveOutputSink(name string) {
+	ls.SinksLock.Lock()
+	sr, ok := ls.Sinks[name]
+	delete(ls.Sinks, name)
+	ls.SinksLock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	for stream := range sr.Subscribers {
+		bc, _, _ := ls.streamBroadcaster(stream)
+		bc.Unsubscribe("sink-" + name)
+	}
+
+	sr.stop()
+}
+
+// UpdateOutputSinks Function
+func (ls *LogService) UpdateOutputSinks(configs []SinkConfig) error {
+	desired := map[string]SinkConfig{}
+	for _, config := range configs {
+		desired[config.Name] = normalizeSinkConfig(config)
+	}
+
+	// remove the sinks deleted or changed
+
+	ls.SinksLock.RLock()
+	removed := []string{}
+	for name, sr := range ls.Sinks {
+		if config, ok := desired[name]; !ok || !reflect.DeepEqual(config, sr.Config) {
+			removed = append(removed, name)
+		}
+	}
+	ls.SinksLock.RUnlock()
+
+	for _, name := range removed {
+		ls.RemoveOutputSink(name)
+		kg.Printf("Removed an output sink (%s)", name)
+	}
+
+	// add the sinks created or changed
+
+	var errs []string
+
+	for _, config := range configs {
+		ls.SinksLock.RLock()
+		_, ok := ls.Sinks[config.Name]
+		ls.SinksLock.RUnlock()
+
+		if ok {
+			continue
+		}
+
+		if err := ls.AddOutputSink(config); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to add the %s sink (%s)", config.Name, err.Error()))
+			continue
+		}
+		kg.Printf("Added an output sink (%s, %s)", config.Name, config.Type)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// StopOutputSinks Function
+func (ls *LogService) StopOutputSinks() {
+	ls.SinksLock.RLock()
+	names := []string{}
+	for name := range ls.Sinks {
+		names = append(names, name)
+	}
+	ls.SinksLock.RUnlock()
+
+	for _, name := range names {
+		ls.RemoveOutputSink(name)
+	}
+}
+
+// ================== //
+// == Sink Helpers == //
+// ================== //
+
+// normalizeSinkConfig Function
+func normalizeSinkConfig(config SinkConfig) SinkConfig {
+	if len(config.Streams) == 0 {
+		config.Streams = []string{StreamAlert, StreamLog}
+	}
+
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultSinkBufferSize
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultSinkBatchSize
+	}
+
+	return config
+}
+
+// sinkEventJSON Function
+func sinkEventJSON(event SinkEvent) ([]byte, error) {
+	return json.Marshal(event.Item)
+}
+
+// sinkRetry Function (gives up as soon as ctx is canceled)
+func sinkRetry(ctx context.Context, maxRetries int, interval time.Duration, fn func() error) error {
+	err := fn()
+
+	for retry := 0; err != nil && retry < maxRetries; retry++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+
+		err = fn()
+	}
+
+	return err
+}
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+)
+
+// fakeSink Structure
+type fakeSink struct {
+	lock   sync.Mutex
+	events []SinkEvent
+	block  chan struct{}
+}
+
+func (sink *fakeSink) Write(ctx context.Context, events []SinkEvent) error {
+	if sink.block != nil {
+		<-sink.block
+	}
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	sink.events = append(sink.events, events...)
+	return nil
+}
+
+func (sink *fakeSink) Close() error {
+	return nil
+}
+
+func (sink *fakeSink) count() int {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	return len(sink.events)
+}
+
+// waitFor Function
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestOutputSinkRegistry(t *testing.T) {
+	sink := &fakeSink{}
+
+	RegisterOutputSink("fake", func(config SinkConfig) (OutputSink, error) {
+		return sink, nil
+	})
+
+	ls := NewLogService(DefaultSubscriberBufferSize, DefaultSlowSubscriberPolicy)
+	defer ls.StopOutputSinks()
+
+	if err := ls.AddOutputSink(SinkConfig{Name: "bad", Type: "unknown"}); err == nil {
+		t.Error("[FAIL] Failed to reject an unknown sink type")
+		return
+	}
+
+	if err := ls.AddOutputSink(SinkConfig{Name: "bad", Type: "fake", Filter: `action == `}); err == nil {
+		t.Error("[FAIL] Failed to reject an invalid filter")
+		return
+	}
+
+	// action only exists in alerts
+	if err := ls.AddOutputSink(SinkConfig{Name: "bad", Type: "fake", Filter: `action == "Block"`}); err == nil {
+		t.Error("[FAIL] Failed to reject a filter invalid for the log stream")
+		return
+	}
+	t.Log("[PASS] Rejected invalid sink configurations")
+
+	if err := ls.AddOutputSink(SinkConfig{Name: "fake", Type: "fake", Streams: []string{StreamAlert}, Filter: `action == "Block"`}); err != nil {
+		t.Errorf("[FAIL] Failed to add a sink (%s)", err.Error())
+		return
+	}
+
+	ls.AlertBroadcaster.Broadcast(&pb.Alert{Action: "Audit"})
+	ls.AlertBroadcaster.Broadcast(&pb.Alert{Action: "Block"})
+	ls.LogBroadcaster.Broadcast(&pb.Log{})
+
+	if !waitFor(func() bool { return sink.count() == 1 }) {
+		t.Errorf("[FAIL] Wrote %d events (expected 1)", sink.count())
+		return
+	}
+	t.Log("[PASS] Wrote filtered events into a sink")
+}
+
+func TestOutputSinkNonBlocking(t *testing.T) {
+	sink := &fakeSink{block: make(chan struct{})}
+
+	RegisterOutputSink("stuck", func(config SinkConfig) (OutputSink, error) {
+		return sink, nil
+	})
+
+	ls := NewLogService(DefaultSubscriberBufferSize, DefaultSlowSubscriberPolicy)
+
+	if err := ls.AddOutputSink(SinkConfig{Name: "stuck", Type: "stuck", Streams: []string{StreamLog}, BufferSize: 8}); err != nil {
+		t.Errorf("[FAIL] Failed to add a sink (%s)", err.Error())
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			ls.LogBroadcaster.Broadcast(&pb.Log{})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("[FAIL] Blocked by a stuck sink")
+		return
+	}
+
+	if atomic.LoadUint64(&ls.Sinks["stuck"].Subscribers[StreamLog].Dropped) == 0 {
+		t.Error("[FAIL] Failed to drop events for a stuck sink")
+		return
+	}
+	t.Log("[PASS] Dropped events for a stuck sink without blocking")
+
+	close(sink.block)
+	ls.StopOutputSinks()
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-sink")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alerts.jsonl")
+
+	sink, err := NewFileSink(SinkConfig{File: &FileSinkConfig{Path: path, MaxSize: 1, MaxBackups: 2, Compress: true}})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a file sink (%s)", err.Error())
+		return
+	}
+
+	// ~1.5KB per event, ~3MB in total
+	events := []SinkEvent{}
+	for i := 0; i < 100; i++ {
+		events = append(events, SinkEvent{Stream: StreamAlert, Item: &pb.Alert{Data: strings.Repeat("x", 1500)}})
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := sink.Write(context.Background(), events); err != nil {
+			t.Errorf("[FAIL] Failed to write events (%s)", err.Error())
+			return
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Errorf("[FAIL] Failed to close the file sink (%s)", err.Error())
+		return
+	}
+
+	backups, _ := filepath.Glob(path + ".*.gz")
+	if len(backups) != 2 {
+		t.Errorf("[FAIL] Kept %d compressed backups (expected 2)", len(backups))
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > 1024*1024 {
+		t.Error("[FAIL] Failed to limit the size of the current file")
+		return
+	}
+	t.Log("[PASS] Rotated and compressed JSON-lines files")
+}
+
+func TestWebhookSink(t *testing.T) {
+	var requests int32
+	var received int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// fail the first request
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		batch := []json.RawMessage{}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		atomic.AddInt32(&received, int32(len(batch)))
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(SinkConfig{Webhook: &WebhookSinkConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"X-Token": "secret"},
+		RetryInterval: "10ms",
+	}})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a webhook sink (%s)", err.Error())
+		return
+	}
+	defer sink.Close()
+
+	events := []SinkEvent{{Stream: StreamAlert, Item: &pb.Alert{}}, {Stream: StreamAlert, Item: &pb.Alert{}}}
+
+	if err := sink.Write(context.Background(), events); err != nil {
+		t.Errorf("[FAIL] Failed to post events (%s)", err.Error())
+		return
+	}
+
+	if atomic.LoadInt32(&requests) != 2 || atomic.LoadInt32(&received) != 2 {
+		t.Errorf("[FAIL] Sent %d requests with %d events (expected 2 and 2)", requests, received)
+		return
+	}
+	t.Log("[PASS] Posted a batch of events with a retry")
+}
+
+func TestWebhookSinkCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// retries would take an hour without cancellation
+	sink, err := NewWebhookSink(SinkConfig{Webhook: &WebhookSinkConfig{URL: server.URL, RetryInterval: "1h"}})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a webhook sink (%s)", err.Error())
+		return
+	}
+	defer sink.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	if err := sink.Write(ctx, []SinkEvent{{Stream: StreamAlert, Item: &pb.Alert{}}}); err != context.Canceled {
+		t.Errorf("[FAIL] Got an unexpected error (%v)", err)
+		return
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Error("[FAIL] Failed to stop retries on cancellation")
+		return
+	}
+	t.Log("[PASS] Stopped retries on cancellation")
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to listen (%s)", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SinkConfig{Syslog: &SyslogSinkConfig{Network: "udp", Address: conn.LocalAddr().String()}})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a syslog sink (%s)", err.Error())
+		return
+	}
+	defer sink.Close()
+
+	alert := &pb.Alert{Timestamp: 1626000000, HostName: "node 1", PolicyName: "block-bash"}
+
+	if err := sink.Write(context.Background(), []SinkEvent{{Stream: StreamAlert, Item: alert}}); err != nil {
+		t.Errorf("[FAIL] Failed to write an alert (%s)", err.Error())
+		return
+	}
+
+	buf := make([]byte, 4096)
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Errorf("[FAIL] Failed to set a deadline (%s)", err.Error())
+		return
+	}
+
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to receive an alert (%s)", err.Error())
+		return
+	}
+
+	// local0 (16) * 8 + warning (4) = 132
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<132>1 2021-07-11T10:40:00Z node1 kubearmor ") || !strings.Contains(msg, " Alert - {") {
+		t.Errorf("[FAIL] Got an unexpected message (%s)", msg)
+		return
+	}
+	t.Log("[PASS] Sent an RFC 5424 message")
+
+	if _, err := NewSyslogSink(SinkConfig{Syslog: &SyslogSinkConfig{Network: "http", Address: "localhost"}}); err == nil {
+		t.Error("[FAIL] Failed to reject an unsupported network")
+		return
+	}
+
+	if _, err := NewSyslogSink(SinkConfig{}); err == nil {
+		t.Error("[FAIL] Failed to reject a missing configuration")
+		return
+	}
+	t.Log("[PASS] Rejected invalid syslog configurations")
+}
@@ -44,6 +44,84 @@ func getOperationAndCapabilityFromName(capName string) (op, cap string) {
 	return op, cap
 }
 
+// matchArg Function
+func matchArg(pattern, arg string) bool {
+	// glob where '*' matches any characters including '/' (e.g., --output=*)
+	p, a := 0, 0
+	star, next := -1, 0
+
+	for a < len(arg) {
+		if p < len(pattern) && (pattern[p] == '?' || pattern[p] == arg[a]) {
+			p++
+			a++
+		} else if p < len(pattern) && pattern[p] == '*' {
+			star, next = p, a
+			p++
+		} else if star >= 0 {
+			next++
+			p, a = star+1, next
+		} else {
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+// matchArgs Function
+func matchArgs(args []string, log tp.Log) bool {
+	if len(args) == 0 {
+		return true
+	}
+
+	// only executions have arguments
+	if log.Process == nil || len(log.Process.Args) < 2 {
+		return false
+	}
+
+	for _, arg := range args {
+		found := false
+
+		for _, procArg := range log.Process.Args[1:] {
+			if matchArg(arg, procArg) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchSyscall Function
+func matchSyscall(secPolicy tp.MatchPolicy, log tp.Log) bool {
+	// the syscall of a log is in its data (e.g., syscall=SYS_UNLINKAT fd=-100 flags=0)
+	if !strings.HasPrefix(log.Data, "syscall=SYS_") {
+		return false
+	}
+
+	syscall := strings.TrimPrefix(strings.Split(log.Data, " ")[0], "syscall=SYS_")
+	syscall = strings.ToLower(syscall)
+
+	if !kl.ContainsElement(secPolicy.Syscalls, syscall) {
+		return false
+	}
+
+	if secPolicy.Resource != "" && secPolicy.Resource != log.Resource {
+		return false
+	}
+
+	return secPolicy.Source == "" || strings.Contains(secPolicy.Source, strings.Split(log.Source, " ")[0])
+}
+
 // newMatchPolicy Function
 func (fd *Feeder) newMatchPolicy(policyEnabled int, policyName, src string, mp interface{}) tp.MatchPolicy {
 	match := tp.MatchPolicy{
@@ -60,8 +138,13 @@ func (fd *Feeder) newMatchPolicy(policyEnabled int, policyName, src string, mp i
 		match.Resource = ppt.Path
 		match.ResourceType = "Path"
 
+		match.Args = ppt.Args
+
 		if policyEnabled == tp.KubeArmorPolicyAudited && strings.HasPrefix(ppt.Action, "Block") {
 			match.Action = "Audit (" + ppt.Action + ")"
+		} else if len(ppt.Args) > 0 && strings.HasPrefix(ppt.Action, "Block") {
+			// no enforcer can see arguments, so executions with them are only audited
+			match.Action = "Audit (" + ppt.Action + ")"
 		} else {
 			match.Action = ppt.Action
 		}
@@ -166,10 +249,32 @@ func (fd *Feeder) newMatchPolicy(policyEnabled int, policyName, src string, mp i
 		} else {
 			match.Action = cct.Action
 		}
+	} else if smt, ok := mp.(tp.SyscallMatchType); ok {
+		match.Severity = strconv.Itoa(smt.Severity)
+		match.Tags = smt.Tags
+		match.Message = smt.Message
+
+		match.Operation = "Syscall"
+		match.Resource = smt.Path
+		match.ResourceType = "Syscall"
+
+		match.Syscalls = smt.Syscalls
+
+		if strings.HasPrefix(smt.Action, "Block") {
+			// AppArmor cannot block syscalls, so they are only audited
+			match.Action = "Audit (" + smt.Action + ")"
+		} else {
+			match.Action = smt.Action
+		}
 	} else {
 		return tp.MatchPolicy{}
 	}
 
+	// the rules that the enforcer in use cannot enforce are only audited (e.g., directories in BPF-LSM)
+	if policyEnabled == tp.KubeArmorPolicyEnabled && fd.IsEnforceable != nil && (match.Action == "Block" || match.Action == "Allow") && !fd.IsEnforceable(mp) {
+		match.Action = "Audit (" + match.Action + ")"
+	}
+
 	return match
 }
 
@@ -396,6 +501,32 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, endPoint tp.EndPoint) {
 			}
 
 		}
+
+		for _, sys := range secPolicy.Spec.Syscalls.MatchSyscalls {
+			if len(sys.Syscalls) == 0 {
+				continue
+			}
+
+			fromSource := ""
+
+			if len(sys.FromSource) == 0 {
+				match := fd.newMatchPolicy(endPoint.PolicyEnabled, policyName, fromSource, sys)
+				matches.Policies = append(matches.Policies, match)
+				continue
+			}
+
+			for _, src := range sys.FromSource {
+				if len(src.Path) > 0 {
+					fromSource = src.Path
+				} else {
+					continue
+				}
+
+				match := fd.newMatchPolicy(endPoint.PolicyEnabled, policyName, fromSource, sys)
+				match.IsFromSource = len(fromSource) > 0
+				matches.Policies = append(matches.Policies, match)
+			}
+		}
 	}
 
 	fd.SecurityPoliciesLock.Lock()
@@ -409,8 +540,10 @@ func (fd *Feeder) UpdateSecurityPolicies(action string, endPoint tp.EndPoint) {
 
 // UpdateHostSecurityPolicies Function
 func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.HostSecurityPolicy) {
+	node := fd.getNode()
+
 	if action == "DELETED" {
-		delete(fd.SecurityPolicies, fd.Node.NodeName)
+		delete(fd.SecurityPolicies, node.NodeName)
 		return
 	}
 
@@ -433,7 +566,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(path.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, path)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, path)
 				matches.Policies = append(matches.Policies, match)
 				continue
 			}
@@ -445,7 +578,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, path)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, path)
 				match.IsFromSource = len(fromSource) > 0
 				matches.Policies = append(matches.Policies, match)
 			}
@@ -455,7 +588,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(dir.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, dir)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, dir)
 				matches.Policies = append(matches.Policies, match)
 				continue
 			}
@@ -467,7 +600,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, dir)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, dir)
 				match.IsFromSource = len(fromSource) > 0
 				matches.Policies = append(matches.Policies, match)
 			}
@@ -500,7 +633,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(path.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, path)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, path)
 				matches.Policies = append(matches.Policies, match)
 				continue
 			}
@@ -512,7 +645,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, path)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, path)
 				match.IsFromSource = len(fromSource) > 0
 				matches.Policies = append(matches.Policies, match)
 			}
@@ -522,7 +655,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(dir.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, dir)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, dir)
 				matches.Policies = append(matches.Policies, match)
 				continue
 			}
@@ -534,7 +667,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, dir)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, dir)
 				match.IsFromSource = len(fromSource) > 0
 				matches.Policies = append(matches.Policies, match)
 			}
@@ -547,7 +680,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 
 			fromSource := ""
 
-			match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, patt)
+			match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, patt)
 
 			regexpComp, err := regexp.Compile(patt.Pattern)
 			if err != nil {
@@ -571,7 +704,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(proto.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, proto)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, proto)
 				if len(match.Resource) == 0 {
 					continue
 				}
@@ -586,7 +719,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, proto)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, proto)
 				if len(match.Resource) == 0 {
 					continue
 				}
@@ -604,7 +737,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 			fromSource := ""
 
 			if len(cap.FromSource) == 0 {
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, cap)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, cap)
 				if len(match.Resource) == 0 {
 					continue
 				}
@@ -619,7 +752,7 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 					continue
 				}
 
-				match := fd.newMatchPolicy(fd.Node.PolicyEnabled, policyName, fromSource, cap)
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, cap)
 				if len(match.Resource) == 0 {
 					continue
 				}
@@ -627,10 +760,36 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 				matches.Policies = append(matches.Policies, match)
 			}
 		}
+
+		for _, sys := range secPolicy.Spec.Syscalls.MatchSyscalls {
+			if len(sys.Syscalls) == 0 {
+				continue
+			}
+
+			fromSource := ""
+
+			if len(sys.FromSource) == 0 {
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, sys)
+				matches.Policies = append(matches.Policies, match)
+				continue
+			}
+
+			for _, src := range sys.FromSource {
+				if len(src.Path) > 0 {
+					fromSource = src.Path
+				} else {
+					continue
+				}
+
+				match := fd.newMatchPolicy(node.PolicyEnabled, policyName, fromSource, sys)
+				match.IsFromSource = len(fromSource) > 0
+				matches.Policies = append(matches.Policies, match)
+			}
+		}
 	}
 
 	fd.SecurityPoliciesLock.Lock()
-	fd.SecurityPolicies[fd.Node.NodeName] = matches
+	fd.SecurityPolicies[node.NodeName] = matches
 	fd.SecurityPoliciesLock.Unlock()
 }
 
@@ -640,6 +799,8 @@ func (fd *Feeder) UpdateHostSecurityPolicies(action string, secPolicies []tp.Hos
 
 // UpdateMatchedPolicy Function
 func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
+	node := fd.getNode()
+
 	allowProcPolicy := ""
 	allowProcPolicySeverity := ""
 	allowProcTags := []string{}
@@ -657,10 +818,13 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 
 	mightBeNative := false
 
+	// allow policies that the enforcer in use cannot enforce
+	auditedAllow := false
+
 	if log.Result == "Passed" || log.Result == "Operation not permitted" || log.Result == "Permission denied" {
 		fd.SecurityPoliciesLock.RLock()
 
-		key := fd.Node.NodeName
+		key := node.NodeName
 
 		if log.NamespaceName != "" && log.PodName != "" {
 			key = log.NamespaceName + "_" + log.PodName
@@ -669,7 +833,11 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 		secPolicies := fd.SecurityPolicies[key].Policies
 		for _, secPolicy := range secPolicies {
 			if secPolicy.Source == "" || secPolicy.IsFromSource || strings.Contains(secPolicy.Source, strings.Split(log.Source, " ")[0]) || (log.Source == "runc:[2:INIT]" && strings.Contains(secPolicy.Source, strings.Split(log.Resource, " ")[0])) {
-				if secPolicy.Action == "Allow" {
+				if secPolicy.Action == "Allow" || secPolicy.Action == "Audit (Allow)" {
+					if secPolicy.Action == "Audit (Allow)" {
+						auditedAllow = true
+					}
+
 					if secPolicy.Operation == "Process" {
 						if allowProcPolicy == "" {
 							allowProcPolicy = secPolicy.PolicyName
@@ -746,6 +914,26 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				}
 			}
 
+			if secPolicy.Operation == "Syscall" {
+				if matchSyscall(secPolicy, log) {
+					log.PolicyName = secPolicy.PolicyName
+					log.Severity = secPolicy.Severity
+
+					if len(secPolicy.Tags) > 0 {
+						log.Tags = strings.Join(secPolicy.Tags[:], ",")
+					}
+
+					if len(secPolicy.Message) > 0 {
+						log.Message = secPolicy.Message
+					}
+
+					log.Type = "MatchedPolicy"
+					log.Action = secPolicy.Action
+				}
+
+				continue
+			}
+
 			switch log.Operation {
 			case "Process", "File":
 				if secPolicy.Operation == log.Operation {
@@ -762,7 +950,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 						}
 					}
 
-					if matched || strings.Contains(log.Resource, secPolicy.Resource) {
+					if (matched || strings.Contains(log.Resource, secPolicy.Resource)) && matchArgs(secPolicy.Args, log) {
 						if (log.Result != "Passed" && secPolicy.Action == "Allow") || secPolicy.Source == "" ||
 							(secPolicy.Source != "" && strings.Contains(secPolicy.Source, strings.Split(log.Source, " ")[0])) ||
 							(secPolicy.Source != "" && log.Source == "runc:[2:INIT]" && strings.Contains(secPolicy.Source, strings.Split(log.Resource, " ")[0])) {
@@ -832,7 +1020,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				return log
 			}
 
-			if log.PolicyEnabled == tp.KubeArmorPolicyEnabled && log.Result != "Passed" {
+			if log.PolicyEnabled == tp.KubeArmorPolicyEnabled && !auditedAllow && log.Result != "Passed" {
 				if log.Operation == "Process" && allowProcPolicy != "" {
 					log.PolicyName = allowProcPolicy
 					log.Severity = allowProcPolicySeverity
@@ -886,7 +1074,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				}
 			}
 
-			if log.PolicyEnabled == tp.KubeArmorPolicyAudited {
+			if log.PolicyEnabled == tp.KubeArmorPolicyAudited || auditedAllow {
 				if log.Operation == "Process" && allowProcPolicy != "" {
 					log.PolicyName = allowProcPolicy
 					log.Severity = allowProcPolicySeverity
@@ -945,7 +1133,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				return log
 			}
 
-			if log.ProcessVisibilityEnabled && log.Operation == "Process" {
+			if log.ProcessVisibilityEnabled && (log.Operation == "Process" || log.Operation == "Syscall") {
 				log.Type = "ContainerLog"
 				return log
 			} else if log.FileVisibilityEnabled && log.Operation == "File" {
@@ -965,7 +1153,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				}
 			}
 
-			if log.Action == "Allow" && log.Result == "Passed" {
+			if (log.Action == "Allow" || log.Action == "Audit (Allow)") && log.Result == "Passed" {
 				return tp.Log{}
 			}
 
@@ -986,7 +1174,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				return log
 			}
 
-			if fd.Node.PolicyEnabled == tp.KubeArmorPolicyAudited {
+			if node.PolicyEnabled == tp.KubeArmorPolicyAudited {
 				if log.Operation == "Process" && allowProcPolicy != "" {
 					log.PolicyName = allowProcPolicy
 					log.Severity = allowProcPolicySeverity
@@ -1040,16 +1228,16 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				}
 			}
 
-			if fd.Node.ProcessVisibilityEnabled && log.Operation == "Process" {
+			if node.ProcessVisibilityEnabled && (log.Operation == "Process" || log.Operation == "Syscall") {
 				log.Type = "HostLog"
 				return log
-			} else if fd.Node.FileVisibilityEnabled && log.Operation == "File" {
+			} else if node.FileVisibilityEnabled && log.Operation == "File" {
 				log.Type = "HostLog"
 				return log
-			} else if fd.Node.NetworkVisibilityEnabled && log.Operation == "Network" {
+			} else if node.NetworkVisibilityEnabled && log.Operation == "Network" {
 				log.Type = "HostLog"
 				return log
-			} else if fd.Node.CapabilitiesVisibilityEnabled && log.Operation == "Capabilities" {
+			} else if node.CapabilitiesVisibilityEnabled && log.Operation == "Capabilities" {
 				log.Type = "HostLog"
 				return log
 			}
@@ -1061,7 +1249,7 @@ func (fd *Feeder) UpdateMatchedPolicy(log tp.Log) tp.Log {
 				}
 			}
 
-			if log.Action == "Allow" && log.Result == "Passed" {
+			if (log.Action == "Allow" || log.Action == "Audit (Allow)") && log.Result == "Passed" {
 				return tp.Log{}
 			}
 
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func TestMatchProcessArgs(t *testing.T) {
+	node := tp.Node{NodeName: "nodeName"}
+	fd := NewPolicyMatcher(&node)
+
+	secPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-block-curl-output"}}
+	secPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{
+		{Path: "/usr/bin/curl", Args: []string{"-o"}, Action: "Block"},
+		{Path: "/usr/bin/wget", Args: []string{"--output-document=*", "-q"}, Action: "Audit"},
+	}
+
+	endPoint := tp.EndPoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", PolicyEnabled: tp.KubeArmorPolicyEnabled, SecurityPolicies: []tp.SecurityPolicy{secPolicy}}
+	fd.UpdateSecurityPolicies("ADDED", endPoint)
+
+	newLog := func(args ...string) tp.Log {
+		return tp.Log{
+			NamespaceName: "multiubuntu",
+			PodName:       "ubuntu-1",
+			ContainerID:   "0123456789abcdef",
+			Source:        "/bin/bash",
+			Operation:     "Process",
+			Resource:      args[0],
+			Result:        "Passed",
+			Process:       &tp.ProcessEvent{ExecPath: args[0], Args: args},
+		}
+	}
+
+	log := fd.UpdateMatchedPolicy(newLog("/usr/bin/curl", "-o", "/tmp/index.html", "example.com"))
+	if log.Type != "MatchedPolicy" || log.PolicyName != "ksp-block-curl-output" || log.Action != "Audit (Block)" {
+		t.Errorf("[FAIL] Failed to match an execution with arguments (%s, %s)", log.Type, log.Action)
+		return
+	}
+	t.Log("[PASS] Matched an execution with arguments (audited as AppArmor cannot see them)")
+
+	log = fd.UpdateMatchedPolicy(newLog("/usr/bin/curl", "example.com"))
+	if log.Type != "" {
+		t.Errorf("[FAIL] Matched an execution without the arguments (%s)", log.Action)
+		return
+	}
+	t.Log("[PASS] Skipped an execution without the arguments")
+
+	log = fd.UpdateMatchedPolicy(newLog("/usr/bin/wget", "-q", "--output-document=/tmp/index.html", "example.com"))
+	if log.Type != "MatchedPolicy" || log.Action != "Audit" {
+		t.Errorf("[FAIL] Failed to match arguments with a pattern (%s, %s)", log.Type, log.Action)
+		return
+	}
+
+	log = fd.UpdateMatchedPolicy(newLog("/usr/bin/wget", "--output-document=/tmp/index.html", "example.com"))
+	if log.Type != "" {
+		t.Errorf("[FAIL] Matched an execution with a part of the arguments (%s)", log.Action)
+		return
+	}
+	t.Log("[PASS] Matched all arguments with patterns")
+}
+
+func TestMatchSyscalls(t *testing.T) {
+	node := tp.Node{NodeName: "nodeName"}
+	fd := NewPolicyMatcher(&node)
+
+	secPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-audit-mount"}}
+	secPolicy.Spec.Syscalls.MatchSyscalls = []tp.SyscallMatchType{
+		{Syscalls: []string{"mount", "umount2"}, Severity: 5, Action: "Block"},
+		{Syscalls: []string{"unlinkat"}, Path: "/etc/passwd", Action: "Audit"},
+	}
+
+	endPoint := tp.EndPoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", PolicyEnabled: tp.KubeArmorPolicyEnabled, SecurityPolicies: []tp.SecurityPolicy{secPolicy}}
+	fd.UpdateSecurityPolicies("ADDED", endPoint)
+
+	newLog := func(operation, resource, data string) tp.Log {
+		return tp.Log{
+			NamespaceName: "multiubuntu",
+			PodName:       "ubuntu-1",
+			ContainerID:   "0123456789abcdef",
+			Source:        "/bin/mount",
+			Operation:     operation,
+			Resource:      resource,
+			Data:          data,
+			Result:        "Passed",
+		}
+	}
+
+	log := fd.UpdateMatchedPolicy(newLog("Syscall", "/mnt", "syscall=SYS_MOUNT source=/dev/sda1 fstype=ext4 flags=MS_RDONLY"))
+	if log.Type != "MatchedPolicy" || log.PolicyName != "ksp-audit-mount" || log.Severity != "5" || log.Action != "Audit (Block)" {
+		t.Errorf("[FAIL] Failed to match a mount syscall (%s, %s)", log.Type, log.Action)
+		return
+	}
+	t.Log("[PASS] Matched a mount syscall (audited as AppArmor cannot block it)")
+
+	log = fd.UpdateMatchedPolicy(newLog("File", "/etc/passwd", "syscall=SYS_UNLINKAT fd=-100 flags=0"))
+	if log.Type != "MatchedPolicy" || log.Action != "Audit" {
+		t.Errorf("[FAIL] Failed to match an unlinkat syscall on a path (%s, %s)", log.Type, log.Action)
+		return
+	}
+
+	log = fd.UpdateMatchedPolicy(newLog("File", "/etc/hosts", "syscall=SYS_UNLINKAT fd=-100 flags=0"))
+	if log.Type != "" {
+		t.Errorf("[FAIL] Matched an unlinkat syscall on another path (%s)", log.Action)
+		return
+	}
+	t.Log("[PASS] Matched a syscall on a path")
+
+	log = fd.UpdateMatchedPolicy(newLog("Syscall", "uid=0", "syscall=SYS_SETUID"))
+	if log.Type != "" {
+		t.Errorf("[FAIL] Matched an unlisted syscall (%s)", log.Action)
+		return
+	}
+	t.Log("[PASS] Skipped an unlisted syscall")
+}
+
+func TestMatchUnenforceableRules(t *testing.T) {
+	node := tp.Node{NodeName: "nodeName"}
+	fd := NewPolicyMatcher(&node)
+
+	// e.g., BPF-LSM only enforces blocked file paths
+	fd.IsEnforceable = func(rule interface{}) bool {
+		fpt, ok := rule.(tp.FilePathType)
+		return ok && fpt.Action == "Block"
+	}
+
+	secPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-block-files"}}
+	secPolicy.Spec.File.MatchPaths = []tp.FilePathType{{Path: "/etc/shadow", Action: "Block"}}
+	secPolicy.Spec.File.MatchDirectories = []tp.FileDirectoryType{{Directory: "/credentials/", Action: "Block"}}
+
+	allowPolicy := tp.SecurityPolicy{Metadata: map[string]string{"policyName": "ksp-allow-ls"}}
+	allowPolicy.Spec.Process.MatchPaths = []tp.ProcessPathType{{Path: "/bin/ls", Action: "Allow"}}
+
+	endPoint := tp.EndPoint{NamespaceName: "multiubuntu", EndPointName: "ubuntu-1", PolicyEnabled: tp.KubeArmorPolicyEnabled, SecurityPolicies: []tp.SecurityPolicy{secPolicy, allowPolicy}}
+	fd.UpdateSecurityPolicies("ADDED", endPoint)
+
+	newLog := func(operation, resource, result string) tp.Log {
+		return tp.Log{
+			NamespaceName: "multiubuntu",
+			PodName:       "ubuntu-1",
+			ContainerID:   "0123456789abcdef",
+			Source:        "/bin/bash",
+			Operation:     operation,
+			Resource:      resource,
+			Result:        result,
+		}
+	}
+
+	log := fd.UpdateMatchedPolicy(newLog("File", "/etc/shadow", "Permission denied"))
+	if log.Type != "MatchedPolicy" || log.Action != "Block" {
+		t.Errorf("[FAIL] Failed to match an enforced rule (%s, %s)", log.Type, log.Action)
+		return
+	}
+	t.Log("[PASS] Matched an enforced rule")
+
+	log = fd.UpdateMatchedPolicy(newLog("File", "/credentials/password", "Passed"))
+	if log.Type != "MatchedPolicy" || log.Action != "Audit (Block)" {
+		t.Errorf("[FAIL] Failed to audit a blocked directory (%s, %s)", log.Type, log.Action)
+		return
+	}
+	t.Log("[PASS] Audited a blocked directory that the enforcer cannot enforce")
+
+	log = fd.UpdateMatchedPolicy(newLog("Process", "/bin/sleep 1", "Passed"))
+	if log.Type != "MatchedPolicy" || log.PolicyName != "ksp-allow-ls" || log.Action != "Audit (Allow)" {
+		t.Errorf("[FAIL] Failed to audit the default deny of an allow policy (%s, %s)", log.Type, log.Action)
+		return
+	}
+
+	log = fd.UpdateMatchedPolicy(newLog("Process", "/bin/ls", "Passed"))
+	if log.Type != "" {
+		t.Errorf("[FAIL] Reported an allowed execution (%s, %s)", log.Type, log.Action)
+		return
+	}
+	t.Log("[PASS] Audited an allow policy that the enforcer cannot enforce")
+}
@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// DefaultReplayBufferSize for each stream
+const DefaultReplayBufferSize = 4096
+
+// MaxReplayRecordSize is the largest record in replay logs (to reject corrupted lengths)
+const MaxReplayRecordSize = 1 << 20
+
+// ================== //
+// == Replay Error == //
+// ================== //
+
+// ReplayGapError Structure
+type ReplayGapError struct {
+	Requested uint64
+	Oldest    uint64
+	Latest    uint64
+}
+
+// Error Function
+func (e *ReplayGapError) Error() string {
+	if e.Requested > e.Latest+1 {
+		return fmt.Sprintf("requested sequence %d is ahead of the latest sequence %d", e.Requested, e.Latest)
+	}
+	return fmt.Sprintf("requested sequence %d is no longer available (oldest: %d, latest: %d)", e.Requested, e.Oldest, e.Latest)
+}
+
+// =================== //
+// == Replay Buffer == //
+// =================== //
+
+// sequenced Interface
+type sequenced interface {
+	GetSequenceNumber() uint64
+}
+
+// ReplayBuffer Structure
+type ReplayBuffer struct {
+	// ring buffer
+	items []sequenced
+	head  int
+	count int
+
+	// the latest sequence number
+	latest uint64
+
+	lock *sync.RWMutex
+
+	// on-disk replay log (optional)
+	path     string
+	file     *os.File
+	records  int
+	newItem  func() proto.Message
+	diskFail bool
+	closed   bool
+}
+
+// NewReplayBuffer Function
+func NewReplayBuffer(size int) *ReplayBuffer {
+	if size <= 0 {
+		size = DefaultReplayBufferSize
+	}
+
+	return &ReplayBuffer{
+		items: make([]sequenced, size),
+		lock:  new(sync.RWMutex),
+	}
+}
+
+// NewPersistentReplayBuffer Function
+func NewPersistentReplayBuffer(size int, path string, newItem func() proto.Message) (*ReplayBuffer, error) {
+	rb := NewReplayBuffer(size)
+
+	rb.path = path
+	rb.newItem = newItem
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+
+	if err := rb.load(); err != nil {
+		return nil, err
+	}
+
+	// rewrite the replay log with the loaded items only
+	if err := rb.compact(); err != nil {
+		return nil, err
+	}
+
+	return rb, nil
+}
+
+// Size Function
+func (rb *ReplayBuffer) Size() int {
+	return len(rb.items)
+}
+
+// Latest Function
+func (rb *ReplayBuffer) Latest() uint64 {
+	rb.lock.RLock()
+	defer rb.lock.RUnlock()
+
+	return rb.latest
+}
+
+// Append Function
+func (rb *ReplayBuffer) Append(item sequenced) {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	rb.appendLocked(item)
+
+	// the replay log is reopened if it was closed by a failure
+	if rb.path != "" && !rb.closed {
+		rb.persistLocked(item)
+	}
+}
+
+// appendLocked Function
+func (rb *ReplayBuffer) appendLocked(item sequenced) {
+	if rb.count == len(rb.items) {
+		rb.items[rb.head] = nil
+		rb.head = (rb.head + 1) % len(rb.items)
+		rb.count--
+	}
+
+	rb.items[(rb.head+rb.count)%len(rb.items)] = item
+	rb.count++
+
+	if seq := item.GetSequenceNumber(); seq > rb.latest {
+		rb.latest = seq
+	}
+}
+
+// Since Function
+func (rb *ReplayBuffer) Since(from uint64) ([]interface{}, error) {
+	rb.lock.RLock()
+	defer rb.lock.RUnlock()
+
+	// nothing to replay
+	if from == 0 || from == rb.latest+1 {
+		return nil, nil
+	}
+
+	oldest := rb.latest + 1
+	if rb.count > 0 {
+		oldest = rb.items[rb.head].GetSequenceNumber()
+	}
+
+	if from < oldest || from > rb.latest+1 {
+		return nil, &ReplayGapError{Requested: from, Oldest: oldest, Latest: rb.latest}
+	}
+
+	items := []interface{}{}
+
+	for i := 0; i < rb.count; i++ {
+		item := rb.items[(rb.head+i)%len(rb.items)]
+		if item.GetSequenceNumber() >= from {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// Close Function
+func (rb *ReplayBuffer) Close() {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	rb.closed = true
+
+	if rb.file != nil {
+		if err := rb.file.Close(); err != nil {
+			kg.Warnf("Failed to close %s (%s)", rb.path, err.Error())
+		}
+		rb.file = nil
+	}
+}
+
+// ===================== //
+// == Replay Log File == //
+// ===================== //
+
+// load Function
+func (rb *ReplayBuffer) load() error {
+	file, err := os.Open(filepath.Clean(rb.path))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			kg.Warnf("Failed to close %s (%s)", rb.path, err.Error())
+		}
+	}()
+
+	reader := bufio.NewReader(file)
+
+	// the end of the last good record
+	offset := int64(0)
+	corrupted := false
+
+	for {
+		length, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			kg.Warnf("Stopped loading a truncated replay log (%s)", rb.path)
+			corrupted = true
+			break
+		}
+
+		if length > MaxReplayRecordSize {
+			kg.Warnf("Stopped loading a replay log with a too large record (%s, %d bytes)", rb.path, length)
+			corrupted = true
+			break
+		}
+
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			kg.Warnf("Stopped loading a truncated replay log (%s)", rb.path)
+			corrupted = true
+			break
+		}
+
+		msg := rb.newItem()
+		if err := proto.Unmarshal(data, msg); err != nil {
+			kg.Warnf("Stopped loading a corrupted replay log (%s)", rb.path)
+			corrupted = true
+			break
+		}
+
+		if item, ok := msg.(sequenced); ok {
+			rb.appendLocked(item)
+		}
+
+		offset += int64(uvarintSize(length)) + int64(length)
+	}
+
+	// drop the records after the last good one
+	if corrupted {
+		if err := os.Truncate(filepath.Clean(rb.path), offset); err != nil {
+			kg.Warnf("Failed to truncate the replay log %s (%s)", rb.path, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// uvarintSize Function
+func uvarintSize(val uint64) int {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutUvarint(buf, val)
+}
+
+// reopen Function
+func (rb *ReplayBuffer) reopen() error {
+	file, err := os.OpenFile(filepath.Clean(rb.path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	rb.file = file
+
+	return nil
+}
+
+// compact Function
+func (rb *ReplayBuffer) compact() error {
+	if rb.file != nil {
+		if err := rb.file.Close(); err != nil {
+			return err
+		}
+		rb.file = nil
+	}
+
+	tmpPath := rb.path + ".tmp"
+
+	tmpFile, err := os.OpenFile(filepath.Clean(tmpPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+
+	for i := 0; i < rb.count; i++ {
+		if _, err := writer.Write(encodeReplayRecord(rb.items[(rb.head+i)%len(rb.items)])); err != nil {
+			_ = tmpFile.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, rb.path); err != nil {
+		return err
+	}
+
+	rb.records = rb.count
+
+	return rb.reopen()
+}
+
+// persistLocked Function
+func (rb *ReplayBuffer) persistLocked(item sequenced) {
+	// keep the replay log at most twice as large as the ring
+	if rb.records >= 2*len(rb.items) {
+		if err := rb.compact(); err != nil {
+			kg.Warnf("Failed to compact the replay log %s (%s)", rb.path, err.Error())
+		} else {
+			rb.diskFail = false
+			return // the new item was written while compacting
+		}
+	}
+
+	// retry opening the replay log closed by a failure (e.g., while compacting)
+	if rb.file == nil {
+		if err := rb.reopen(); err != nil {
+			rb.reportDiskFailure(err)
+			return
+		}
+	}
+
+	record := encodeReplayRecord(item)
+	if record == nil {
+		return
+	}
+
+	if _, err := rb.file.Write(record); err != nil {
+		rb.reportDiskFailure(err)
+		return
+	}
+
+	rb.records++
+	rb.diskFail = false
+}
+
+// reportDiskFailure Function (only the first failure in a row is reported)
+func (rb *ReplayBuffer) reportDiskFailure(err error) {
+	if !rb.diskFail {
+		kg.Warnf("Failed to write the replay log %s (%s)", rb.path, err.Error())
+		rb.diskFail = true
+	}
+}
+
+// encodeReplayRecord Function
+func encodeReplayRecord(item sequenced) []byte {
+	msg, ok := item.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil || len(data) > MaxReplayRecordSize {
+		return nil
+	}
+
+	header := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, uint64(len(data)))
+
+	return append(header[:n], data...)
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	rb := NewReplayBuffer(4)
+
+	for seq := uint64(1); seq <= 6; seq++ {
+		rb.Append(&pb.Log{SequenceNumber: seq})
+	}
+
+	// 3, 4, 5, 6 are kept
+
+	items, err := rb.Since(4)
+	if err != nil || len(items) != 3 || items[0].(*pb.Log).SequenceNumber != 4 {
+		t.Errorf("[FAIL] Failed to replay logs from 4 (%v)", err)
+		return
+	}
+	t.Log("[PASS] Replayed logs")
+
+	if items, err := rb.Since(7); err != nil || len(items) != 0 {
+		t.Error("[FAIL] Failed to resume from the next sequence number")
+		return
+	}
+	t.Log("[PASS] Resumed from the next sequence number")
+
+	if _, err := rb.Since(2); err == nil {
+		t.Error("[FAIL] Failed to detect a gap")
+		return
+	} else if gap, ok := err.(*ReplayGapError); !ok || gap.Oldest != 3 || gap.Latest != 6 {
+		t.Errorf("[FAIL] Got an unexpected error (%v)", err)
+		return
+	}
+	t.Log("[PASS] Detected a gap")
+
+	if _, err := rb.Since(10); err == nil {
+		t.Error("[FAIL] Failed to detect a sequence number ahead of the latest one")
+		return
+	}
+	t.Log("[PASS] Detected a sequence number ahead of the latest one")
+}
+
+func TestPersistentReplayBuffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-replay")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alerts.replay")
+	newAlert := func() proto.Message { return &pb.Alert{} }
+
+	rb, err := NewPersistentReplayBuffer(4, path, newAlert)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a replay buffer (%s)", err.Error())
+		return
+	}
+
+	// write enough alerts to trigger compaction
+	for seq := uint64(1); seq <= 20; seq++ {
+		rb.Append(&pb.Alert{SequenceNumber: seq, PolicyName: "test"})
+	}
+	rb.Close()
+
+	rb, err = NewPersistentReplayBuffer(4, path, newAlert)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to reload a replay buffer (%s)", err.Error())
+		return
+	}
+	defer rb.Close()
+
+	if rb.Latest() != 20 {
+		t.Errorf("[FAIL] Reloaded the latest sequence number %d (expected 20)", rb.Latest())
+		return
+	}
+
+	items, err := rb.Since(17)
+	if err != nil || len(items) != 4 || items[3].(*pb.Alert).PolicyName != "test" {
+		t.Errorf("[FAIL] Failed to replay reloaded alerts (%v)", err)
+		return
+	}
+	t.Log("[PASS] Reloaded alerts from the disk")
+}
+
+func TestCorruptedReplayLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-replay")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs.replay")
+
+	// a good record followed by a record claiming to be 1 TiB
+	record := encodeReplayRecord(&pb.Log{SequenceNumber: 1})
+
+	header := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, 1<<40)
+
+	if err := ioutil.WriteFile(path, append(record, header[:n]...), 0600); err != nil {
+		t.Errorf("[FAIL] Failed to write a replay log (%s)", err.Error())
+		return
+	}
+
+	newLog := func() proto.Message { return &pb.Log{} }
+
+	rb, err := NewPersistentReplayBuffer(4, path, newLog)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to load a replay log with a too large record (%s)", err.Error())
+		return
+	}
+	rb.Close()
+
+	if rb.Latest() != 1 {
+		t.Errorf("[FAIL] Loaded the latest sequence number %d (expected 1)", rb.Latest())
+		return
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(record)) {
+		t.Errorf("[FAIL] Failed to truncate the replay log at the last good record")
+		return
+	}
+	t.Log("[PASS] Loaded the good records of a replay log with a too large record")
+}
+
+func TestReplayLogCompactFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubearmor-replay")
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a temporary directory (%s)", err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs.replay")
+	newLog := func() proto.Message { return &pb.Log{} }
+
+	rb, err := NewPersistentReplayBuffer(2, path, newLog)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to create a replay buffer (%s)", err.Error())
+		return
+	}
+
+	// compaction fails since the temporary file cannot be created
+	if err := os.Mkdir(path+".tmp", 0750); err != nil {
+		t.Errorf("[FAIL] Failed to create a directory (%s)", err.Error())
+		return
+	}
+
+	for seq := uint64(1); seq <= 6; seq++ {
+		rb.Append(&pb.Log{SequenceNumber: seq})
+	}
+	rb.Close()
+
+	if err := os.Remove(path + ".tmp"); err != nil {
+		t.Errorf("[FAIL] Failed to remove a directory (%s)", err.Error())
+		return
+	}
+
+	rb, err = NewPersistentReplayBuffer(2, path, newLog)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to reload a replay buffer (%s)", err.Error())
+		return
+	}
+	defer rb.Close()
+
+	if rb.Latest() != 6 {
+		t.Errorf("[FAIL] Stopped persisting logs after a failed compaction (latest %d)", rb.Latest())
+		return
+	}
+	t.Log("[PASS] Kept persisting logs after a failed compaction")
+}
+
+func TestBroadcasterResume(t *testing.T) {
+	bc := NewBroadcaster(8, PolicyDropOldest)
+	bc.SetReplayBuffer(NewReplayBuffer(8))
+
+	for i := 0; i < 5; i++ {
+		bc.Broadcast(&pb.Log{SequenceNumber: bc.NextSequence()})
+	}
+
+	sub, err := bc.SubscribeFrom("resumed", nil, 3)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to resume (%s)", err.Error())
+		return
+	}
+
+	bc.Broadcast(&pb.Log{SequenceNumber: bc.NextSequence()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// 3, 4, 5 (replayed) and 6 (live)
+	for expected := uint64(3); expected <= 6; expected++ {
+		item, ok := sub.Next(ctx)
+		if !ok || item.(*pb.Log).SequenceNumber != expected {
+			t.Errorf("[FAIL] Got %v (expected sequence %d)", item, expected)
+			return
+		}
+	}
+	t.Log("[PASS] Replayed missed logs before live ones")
+
+	// without the replay buffer
+
+	bc.SetReplayBuffer(nil)
+
+	if _, err := bc.SubscribeFrom("gap", nil, 3); err == nil {
+		t.Error("[FAIL] Failed to report a gap without the replay buffer")
+		return
+	}
+
+	if _, err := bc.SubscribeFrom("next", nil, 7); err != nil {
+		t.Errorf("[FAIL] Failed to resume from the next sequence number (%s)", err.Error())
+		return
+	}
+	t.Log("[PASS] Resumed without the replay buffer")
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/kubearmor/KubeArmor/protobuf"
+)
+
+// ================= //
+// == Syslog Sink == //
+// ================= //
+
+// syslog severities (RFC 5424)
+const (
+	syslogError   = 3
+	syslogWarning = 4
+	syslogInfo    = 6
+	syslogDebug   = 7
+)
+
+// syslogFacilities (name -> code)
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogSinkConfig Structure
+type SyslogSinkConfig struct {
+	// {udp|tcp|unix|unixgram}
+	Network string `json:"network"`
+
+	// host:port or socket path
+	Address string `json:"address"`
+
+	// facility name (default: local0)
+	Facility string `json:"facility,omitempty"`
+
+	// app name (default: kubearmor)
+	AppName string `json:"appName,omitempty"`
+
+	// dial/write timeout (default: 5s)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// SyslogSink Structure
+type SyslogSink struct {
+	Config SyslogSinkConfig
+
+	facility int
+	timeout  time.Duration
+	procID   string
+
+	conn     net.Conn
+	connLock *sync.Mutex
+}
+
+// NewSyslogSink Function
+func NewSyslogSink(config SinkConfig) (OutputSink, error) {
+	if config.Syslog == nil {
+		return nil, errors.New("no syslog configuration")
+	}
+
+	sink := &SyslogSink{Config: *config.Syslog, connLock: &sync.Mutex{}}
+
+	switch sink.Config.Network {
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported network %q for syslog, {udp|tcp|unix|unixgram}", sink.Config.Network)
+	}
+
+	if sink.Config.Address == "" {
+		return nil, errors.New("no syslog address")
+	}
+
+	if sink.Config.Facility == "" {
+		sink.Config.Facility = "local0"
+	}
+
+	facility, ok := syslogFacilities[sink.Config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", sink.Config.Facility)
+	}
+	sink.facility = facility
+
+	if sink.Config.AppName == "" {
+		sink.Config.AppName = "kubearmor"
+	}
+
+	sink.timeout = 5 * time.Second
+	if sink.Config.Timeout != "" {
+		timeout, err := time.ParseDuration(sink.Config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog timeout %q", sink.Config.Timeout)
+		}
+		sink.timeout = timeout
+	}
+
+	sink.procID = fmt.Sprintf("%d", os.Getpid())
+
+	return sink, nil
+}
+
+// isStream Function
+func (sink *SyslogSink) isStream() bool {
+	return sink.Config.Network == "tcp" || sink.Config.Network == "unix"
+}
+
+// Write Function
+func (sink *SyslogSink) Write(ctx context.Context, events []SinkEvent) error {
+	sink.connLock.Lock()
+	defer sink.connLock.Unlock()
+
+	for _, event := range events {
+		msg, err := sink.format(event)
+		if err != nil {
+			return err
+		}
+
+		// octet counting (RFC 6587) for stream sockets
+		if sink.isStream() {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+
+		// reconnect once if the connection is broken
+		if err := sink.send(msg); err != nil {
+			sink.closeConn()
+
+			if err := sink.send(msg); err != nil {
+				sink.closeConn()
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// send Function
+func (sink *SyslogSink) send(msg []byte) error {
+	if sink.conn == nil {
+		conn, err := net.DialTimeout(sink.Config.Network, sink.Config.Address, sink.timeout)
+		if err != nil {
+			return err
+		}
+		sink.conn = conn
+	}
+
+	if err := sink.conn.SetWriteDeadline(time.Now().Add(sink.timeout)); err != nil {
+		return err
+	}
+
+	_, err := sink.conn.Write(msg)
+	return err
+}
+
+// closeConn Function
+func (sink *SyslogSink) closeConn() {
+	if sink.conn != nil {
+		_ = sink.conn.Close()
+		sink.conn = nil
+	}
+}
+
+// format Function
+func (sink *SyslogSink) format(event SinkEvent) ([]byte, error) {
+	data, err := sinkEventJSON(event)
+	if err != nil {
+		return nil, err
+	}
+
+	severity := syslogInfo
+	hostName := ""
+	timestamp := time.Now()
+	msgID := "-"
+
+	switch item := event.Item.(type) {
+	case *pb.Message:
+		switch item.Level {
+		case "ERROR":
+			severity = syslogError
+		case "WARN":
+			severity = syslogWarning
+		case "DEBUG":
+			severity = syslogDebug
+		}
+		hostName = item.HostName
+		timestamp = time.Unix(item.Timestamp, 0)
+		msgID = "Message"
+	case *pb.Alert:
+		severity = syslogWarning
+		hostName = item.HostName
+		timestamp = time.Unix(item.Timestamp, 0)
+		msgID = "Alert"
+	case *pb.Log:
+		hostName = item.HostName
+		timestamp = time.Unix(item.Timestamp, 0)
+		msgID = "Log"
+	}
+
+	if timestamp.Unix() == 0 {
+		timestamp = time.Now()
+	}
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s - ",
+		sink.facility*8+severity,
+		timestamp.UTC().Format(time.RFC3339),
+		syslogHeaderField(hostName, 255),
+		syslogHeaderField(sink.Config.AppName, 48),
+		sink.procID,
+		msgID)
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
+
+// syslogHeaderField Function
+func syslogHeaderField(field string, maxLen int) string {
+	field = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, field)
+
+	if field == "" {
+		return "-"
+	}
+
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+
+	return field
+}
+
+// Close Function
+func (sink *SyslogSink) Close() error {
+	sink.connLock.Lock()
+	defer sink.connLock.Unlock()
+
+	sink.closeConn()
+	return nil
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package feeder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ================== //
+// == Webhook Sink == //
+// ================== //
+
+// WebhookSinkConfig Structure
+type WebhookSinkConfig struct {
+	// endpoint to post events to (as a JSON array)
+	URL string `json:"url"`
+
+	// additional HTTP headers (e.g., Authorization)
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// request timeout (default: 5s)
+	Timeout string `json:"timeout,omitempty"`
+
+	// number of retries for a failed batch (default: 3)
+	MaxRetries *int `json:"maxRetries,omitempty"`
+
+	// initial interval between retries, doubled after each retry (default: 1s)
+	RetryInterval string `json:"retryInterval,omitempty"`
+}
+
+// WebhookSink Structure
+type WebhookSink struct {
+	Config WebhookSinkConfig
+
+	client        *http.Client
+	maxRetries    int
+	retryInterval time.Duration
+}
+
+// NewWebhookSink Function
+func NewWebhookSink(config SinkConfig) (OutputSink, error) {
+	if config.Webhook == nil {
+		return nil, errors.New("no webhook configuration")
+	}
+
+	sink := &WebhookSink{Config: *config.Webhook}
+
+	if sink.Config.URL == "" {
+		return nil, errors.New("no webhook URL")
+	}
+
+	timeout := 5 * time.Second
+	if sink.Config.Timeout != "" {
+		val, err := time.ParseDuration(sink.Config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook timeout %q", sink.Config.Timeout)
+		}
+		timeout = val
+	}
+	sink.client = &http.Client{Timeout: timeout}
+
+	sink.maxRetries = 3
+	if sink.Config.MaxRetries != nil {
+		sink.maxRetries = *sink.Config.MaxRetries
+	}
+
+	sink.retryInterval = time.Second
+	if sink.Config.RetryInterval != "" {
+		val, err := time.ParseDuration(sink.Config.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook retryInterval %q", sink.Config.RetryInterval)
+		}
+		sink.retryInterval = val
+	}
+
+	return sink, nil
+}
+
+// Write Function
+func (sink *WebhookSink) Write(ctx context.Context, events []SinkEvent) error {
+	batch := make([]json.RawMessage, 0, len(events))
+
+	for _, event := range events {
+		data, err := sinkEventJSON(event)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, data)
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	return sinkRetry(ctx, sink.maxRetries, sink.retryInterval, func() error {
+		return sink.post(ctx, body)
+	})
+}
+
+// post Function
+func (sink *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, val := range sink.Config.Headers {
+		req.Header.Set(key, val)
+	}
+
+	resp, err := sink.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	// drain the body to reuse the connection
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, sink.Config.URL)
+	}
+
+	return nil
+}
+
+// Close Function
+func (sink *WebhookSink) Close() error {
+	sink.client.CloseIdleConnections()
+	return nil
+}
@@ -6,17 +6,21 @@ replace (
 	github.com/kubearmor/KubeArmor => ../../
 	github.com/kubearmor/KubeArmor/KubeArmor => ../
 	github.com/kubearmor/KubeArmor/KubeArmor/common => ./common
+	github.com/kubearmor/KubeArmor/KubeArmor/config => ./config
 	github.com/kubearmor/KubeArmor/KubeArmor/core => ./core
-	github.com/kubearmor/KubeArmor/KubeArmor/discover => ./discovery
+	github.com/kubearmor/KubeArmor/KubeArmor/discovery => ./discovery
 	github.com/kubearmor/KubeArmor/KubeArmor/enforcer => ./enforcer
 	github.com/kubearmor/KubeArmor/KubeArmor/feeder => ./feeder
 	github.com/kubearmor/KubeArmor/KubeArmor/log => ./log
+	github.com/kubearmor/KubeArmor/KubeArmor/metrics => ./metrics
 	github.com/kubearmor/KubeArmor/KubeArmor/monitor => ./monitor
+	github.com/kubearmor/KubeArmor/KubeArmor/policy => ./policy
 	github.com/kubearmor/KubeArmor/KubeArmor/types => ./types
 	github.com/kubearmor/KubeArmor/protobuf => ../protobuf
 )
 
 require (
+	github.com/cilium/ebpf v0.6.2
 	github.com/containerd/containerd v1.5.2
 	github.com/containerd/typeurl v1.0.2
 	github.com/docker/docker v20.10.7+incompatible
@@ -30,6 +34,7 @@ require (
 	github.com/moby/term v0.0.0-20201216013528-df9cb8a40635 // indirect
 	github.com/morikuni/aec v1.0.0 // indirect
 	github.com/opencontainers/runtime-spec v1.0.3-0.20200929063507-e6143ca7d51d
+	github.com/prometheus/client_golang v1.7.1
 	go.uber.org/zap v1.18.1
 	golang.org/x/lint v0.0.0-20210508222113-6edffad5e616 // indirect
 	golang.org/x/net v0.0.0-20210726213435-c6fcb2dbf985 // indirect
@@ -38,7 +43,10 @@ require (
 	google.golang.org/appengine v1.6.6 // indirect
 	google.golang.org/genproto v0.0.0-20210108203827-ffc7fda8c3d7 // indirect
 	google.golang.org/grpc v1.34.0
+	google.golang.org/protobuf v1.26.0
 	k8s.io/api v0.21.2
 	k8s.io/apimachinery v0.21.2
 	k8s.io/client-go v0.21.2
+	k8s.io/cri-api v0.21.2
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	cfg "github.com/kubearmor/KubeArmor/KubeArmor/config"
 	"github.com/kubearmor/KubeArmor/KubeArmor/core"
 	kg "github.com/kubearmor/KubeArmor/KubeArmor/log"
 )
@@ -36,17 +37,61 @@ func main() {
 	// options (string)
 	clusterPtr := flag.String("cluster", "", "cluster name")
 	gRPCPtr := flag.String("gRPC", "32767", "gRPC port number")
+	httpPtr := flag.String("http", "2112", "HTTP port number for metrics and health probes, {port|none}")
 	logPathPtr := flag.String("logPath", "none", "log file path, {path|stdout|none}")
+	containerRuntimePtr := flag.String("containerRuntime", "", "container runtime, {docker|containerd|cri-o} (auto-detected if empty)")
+	criSocketPtr := flag.String("criSocket", "", "path to the container runtime socket (auto-detected if empty)")
+	slowSubscriberPolicyPtr := flag.String("slowSubscriberPolicy", "drop-oldest", "policy for slow gRPC subscribers, {drop-oldest|disconnect}")
+	replayDirPtr := flag.String("replayDir", "", "directory to keep replay logs on disk (in-memory only if empty)")
+	outputSinksPtr := flag.String("outputSinks", "", "path to the output sink configuration (syslog, file, webhook)")
+	configPtr := flag.String("config", "", "path to the config file (reloaded on changes, overriding the defaults of the flags not set)")
+	configMapPtr := flag.String("configMap", "", "ConfigMap holding the config in kubearmor.yaml (reloaded on changes, overriding the config file and the defaults of the flags not set), {namespace/name}")
+
+	// options (integer)
+	subscriberBufferSizePtr := flag.Int("subscriberBufferSize", 1024, "number of events buffered per gRPC subscriber")
+	replayBufferSizePtr := flag.Int("replayBufferSize", 4096, "number of recent events kept per stream for resumed subscribers (0 to disable)")
 
 	// options (boolean)
 	enableKubeArmorPolicyPtr := flag.Bool("enableKubeArmorPolicy", true, "enabling KubeArmorPolicy")
 	enableKubeArmorHostPolicyPtr := flag.Bool("enableKubeArmorHostPolicy", false, "enabling KubeArmorHostPolicy")
+	enableSeccompEnforcerPtr := flag.Bool("enableSeccompEnforcer", false, "enabling the Seccomp enforcer when neither AppArmor nor BPF-LSM is available")
 
 	flag.Parse()
 
 	// == //
 
-	core.KubeArmor(*clusterPtr, *gRPCPtr, *logPathPtr, *enableKubeArmorPolicyPtr, *enableKubeArmorHostPolicyPtr)
+	// the config file and the ConfigMap override these options
+	config := cfg.DefaultConfig()
+
+	config.Cluster = *clusterPtr
+	config.GRPC = *gRPCPtr
+	config.HTTP = *httpPtr
+	config.LogPath = *logPathPtr
+
+	config.ContainerRuntime = *containerRuntimePtr
+	config.CRISocket = *criSocketPtr
+
+	config.EnableKubeArmorPolicy = *enableKubeArmorPolicyPtr
+	config.EnableKubeArmorHostPolicy = *enableKubeArmorHostPolicyPtr
+	config.EnableSeccompEnforcer = *enableSeccompEnforcerPtr
+
+	config.SubscriberBufferSize = *subscriberBufferSizePtr
+	config.SlowSubscriberPolicy = *slowSubscriberPolicyPtr
+
+	config.ReplayBufferSize = *replayBufferSizePtr
+	config.ReplayDir = *replayDirPtr
+
+	config.OutputSinks = *outputSinksPtr
+
+	config.ConfigPath = *configPtr
+	config.ConfigMap = *configMapPtr
+
+	// the flags set explicitly are not overridden by the config file and the ConfigMap
+	flag.Visit(func(f *flag.Flag) {
+		config.ExplicitFlags = append(config.ExplicitFlags, f.Name)
+	})
+
+	core.KubeArmor(config)
 
 	// == //
 }
@@ -10,14 +10,27 @@ import (
 	"testing"
 )
 
-var clusterPtr, gRPCPtr, logPathPtr *string
+var clusterPtr, gRPCPtr, httpPtr, logPathPtr, containerRuntimePtr, criSocketPtr, slowSubscriberPolicyPtr, replayDirPtr, outputSinksPtr, configPtr, configMapPtr *string
+var subscriberBufferSizePtr, replayBufferSizePtr *int
 var enableKubeArmorPolicyPtr, enableKubeArmorHostPolicyPtr *bool
 
 func init() {
 	// options (string)
 	clusterPtr = flag.String("cluster", "", "cluster name")
 	gRPCPtr = flag.String("gRPC", "32767", "gRPC port number")
+	httpPtr = flag.String("http", "2112", "HTTP port number for metrics and health probes")
 	logPathPtr = flag.String("logPath", "none", "log file path")
+	containerRuntimePtr = flag.String("containerRuntime", "", "container runtime")
+	criSocketPtr = flag.String("criSocket", "", "path to the container runtime socket")
+	slowSubscriberPolicyPtr = flag.String("slowSubscriberPolicy", "drop-oldest", "policy for slow gRPC subscribers")
+	replayDirPtr = flag.String("replayDir", "", "directory to keep replay logs on disk")
+	outputSinksPtr = flag.String("outputSinks", "", "path to the output sink configuration")
+	configPtr = flag.String("config", "", "path to the config file")
+	configMapPtr = flag.String("configMap", "", "ConfigMap holding the config")
+
+	// options (integer)
+	subscriberBufferSizePtr = flag.Int("subscriberBufferSize", 1024, "number of events buffered per gRPC subscriber")
+	replayBufferSizePtr = flag.Int("replayBufferSize", 4096, "number of recent events kept per stream")
 
 	// options (boolean)
 	enableKubeArmorPolicyPtr = flag.Bool("enableKubeArmorPolicy", false, "enabling KubeArmorPolicy")
@@ -30,7 +43,11 @@ func TestMain(t *testing.T) {
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	// Set os args to set flags in main
-	os.Args = []string{"cmd", "-cluster", *clusterPtr, "-gRPC", *gRPCPtr, "-logPath", *logPathPtr,
+	os.Args = []string{"cmd", "-cluster", *clusterPtr, "-gRPC", *gRPCPtr, "-http", *httpPtr, "-logPath", *logPathPtr,
+		"-containerRuntime", *containerRuntimePtr, "-criSocket", *criSocketPtr,
+		"-slowSubscriberPolicy", *slowSubscriberPolicyPtr, "-subscriberBufferSize", strconv.Itoa(*subscriberBufferSizePtr),
+		"-replayDir", *replayDirPtr, "-outputSinks", *outputSinksPtr, "-replayBufferSize", strconv.Itoa(*replayBufferSizePtr),
+		"-config", *configPtr, "-configMap", *configMapPtr,
 		"-enableKubeArmorPolicy", strconv.FormatBool(*enableKubeArmorPolicyPtr),
 		"-enableKubeArmorHostPolicy", strconv.FormatBool(*enableKubeArmorHostPolicyPtr)}
 
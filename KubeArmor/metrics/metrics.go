@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Namespace for all KubeArmor metrics
+const Namespace = "kubearmor"
+
+// Results
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// ============ //
+// == Global == //
+// ============ //
+
+// Registry for KubeArmor metrics
+var Registry *prometheus.Registry
+
+// LostEvents (source, cpu)
+var LostEvents *prometheus.CounterVec
+
+// Alerts (namespace, policy, operation, result)
+var Alerts *prometheus.CounterVec
+
+// Logs (namespace, operation, result)
+var Logs *prometheus.CounterVec
+
+// PolicyMatchDuration (operation)
+var PolicyMatchDuration *prometheus.HistogramVec
+
+// SubscriberDroppedEvents (stream)
+var SubscriberDroppedEvents *prometheus.CounterVec
+
+// SinkEvents (sink, result)
+var SinkEvents *prometheus.CounterVec
+
+// DroppedLogs (source)
+var DroppedLogs *prometheus.CounterVec
+
+// EnforcerProfileReloads (enforcer, result)
+var EnforcerProfileReloads *prometheus.CounterVec
+
+// EnforcerProfileReloadDuration (enforcer)
+var EnforcerProfileReloadDuration *prometheus.HistogramVec
+
+// EnforcerFailures (enforcer, operation)
+var EnforcerFailures *prometheus.CounterVec
+
+// init Function
+func init() {
+	Registry = prometheus.NewRegistry()
+
+	Registry.MustRegister(prometheus.NewGoCollector())
+	Registry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{Namespace: Namespace}))
+
+	LostEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "lost_events_total",
+		Help:      "Number of system events lost in perf buffers",
+	}, []string{"source", "cpu"})
+
+	Alerts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "alerts_total",
+		Help:      "Number of alerts generated by matched policies",
+	}, []string{"namespace", "policy", "operation", "result"})
+
+	Logs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "logs_total",
+		Help:      "Number of system logs generated",
+	}, []string{"namespace", "operation", "result"})
+
+	PolicyMatchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "policy_match_duration_seconds",
+		Help:      "Time taken to match a log with security policies",
+		Buckets:   prometheus.ExponentialBuckets(0.000001, 4, 10),
+	}, []string{"operation"})
+
+	SubscriberDroppedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "subscriber_dropped_events_total",
+		Help:      "Number of events dropped for slow gRPC subscribers and output sinks",
+	}, []string{"stream"})
+
+	SinkEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "sink_events_total",
+		Help:      "Number of events written into output sinks",
+	}, []string{"sink", "result"})
+
+	DroppedLogs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "dropped_logs_total",
+		Help:      "Number of system events dropped in the log pipeline",
+	}, []string{"source"})
+
+	EnforcerProfileReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "enforcer_profile_reloads_total",
+		Help:      "Number of security profile reloads by the runtime enforcer",
+	}, []string{"enforcer", "result"})
+
+	EnforcerProfileReloadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "enforcer_profile_reload_duration_seconds",
+		Help:      "Time taken to reload a security profile",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"enforcer"})
+
+	EnforcerFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "enforcer_failures_total",
+		Help:      "Number of failures in the runtime enforcer",
+	}, []string{"enforcer", "operation"})
+
+	Registry.MustRegister(LostEvents, Alerts, Logs, PolicyMatchDuration, SubscriberDroppedEvents, SinkEvents, DroppedLogs,
+		EnforcerProfileReloads, EnforcerProfileReloadDuration, EnforcerFailures)
+}
+
+// ============= //
+// == Helpers == //
+// ============= //
+
+// register Function
+func register(collector prometheus.Collector) {
+	// replace the existing one if any
+	Registry.Unregister(collector)
+	Registry.MustRegister(collector)
+}
+
+// RegisterQueue Function
+func RegisterQueue(queue string, depth, capacity func() int) {
+	labels := prometheus.Labels{"queue": queue}
+
+	register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   Namespace,
+		Name:        "queue_depth",
+		Help:        "Number of events waiting in an internal queue",
+		ConstLabels: labels,
+	}, func() float64 { return float64(depth()) }))
+
+	register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   Namespace,
+		Name:        "queue_capacity",
+		Help:        "Capacity of an internal queue",
+		ConstLabels: labels,
+	}, func() float64 { return float64(capacity()) }))
+}
+
+// RegisterSubscribers Function
+func RegisterSubscribers(stream string, count func() int) {
+	register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   Namespace,
+		Name:        "subscribers",
+		Help:        "Number of gRPC subscribers and output sinks",
+		ConstLabels: prometheus.Labels{"stream": stream},
+	}, func() float64 { return float64(count()) }))
+}
+
+// ObserveProfileReload Function
+func ObserveProfileReload(enforcer string, start time.Time, err error) {
+	EnforcerProfileReloadDuration.WithLabelValues(enforcer).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		EnforcerProfileReloads.WithLabelValues(enforcer, ResultFailure).Inc()
+		EnforcerFailures.WithLabelValues(enforcer, "reload").Inc()
+	} else {
+		EnforcerProfileReloads.WithLabelValues(enforcer, ResultSuccess).Inc()
+	}
+}
+
+// Handler Function
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
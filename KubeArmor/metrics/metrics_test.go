@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	depth := 3
+
+	// register twice to replace the old one
+	RegisterQueue("test", func() int { return 1 }, func() int { return 8 })
+	RegisterQueue("test", func() int { return depth }, func() int { return 8 })
+
+	LostEvents.WithLabelValues("container", "0").Add(5)
+	Alerts.WithLabelValues("multiubuntu", "ksp-block-bash", "Process", "Permission denied").Inc()
+	ObserveProfileReload("AppArmor", time.Now(), nil)
+	ObserveProfileReload("AppArmor", time.Now(), errors.New("failed"))
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(recorder.Body)
+	if err != nil {
+		t.Errorf("[FAIL] Failed to read metrics (%s)", err.Error())
+		return
+	}
+
+	expected := []string{
+		`kubearmor_queue_depth{queue="test"} 3`,
+		`kubearmor_queue_capacity{queue="test"} 8`,
+		`kubearmor_lost_events_total{cpu="0",source="container"} 5`,
+		`kubearmor_alerts_total{namespace="multiubuntu",operation="Process",policy="ksp-block-bash",result="Permission denied"} 1`,
+		`kubearmor_enforcer_profile_reloads_total{enforcer="AppArmor",result="failure"} 1`,
+		`kubearmor_enforcer_profile_reloads_total{enforcer="AppArmor",result="success"} 1`,
+		`kubearmor_enforcer_failures_total{enforcer="AppArmor",operation="reload"} 1`,
+		`kubearmor_enforcer_profile_reload_duration_seconds_count{enforcer="AppArmor"} 2`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("[FAIL] Failed to find %s", line)
+			return
+		}
+	}
+	t.Log("[PASS] Exposed metrics")
+}
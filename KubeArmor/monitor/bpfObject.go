@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/perf"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// KernelBTFPath is where kernels built with CONFIG_DEBUG_INFO_BTF expose their BTF
+const KernelBTFPath = "/sys/kernel/btf/vmlinux"
+
+// CO-RE Objects (built by BPF/Makefile)
+const (
+	BPFObjectName     = "system_monitor.bpf.o"
+	HostBPFObjectName = "system_monitor_host.bpf.o"
+)
+
+// ================ //
+// == BPF Object == //
+// ================ //
+
+// BPFObject Structure
+type BPFObject struct {
+	Collection *ebpf.Collection
+	Links      []link.Link
+	Reader     *perf.Reader
+
+	EventChan chan []byte
+	LostChan  chan LostSample
+}
+
+// IsBTFAvailable Function
+func IsBTFAvailable() bool {
+	if _, err := os.Stat(KernelBTFPath); err != nil {
+		return false
+	}
+	return true
+}
+
+// GetBPFObjectPath Function
+func GetBPFObjectPath(homeDir, name string) (string, error) {
+	objPath := filepath.Join(homeDir, "BPF", name)
+	if _, err := os.Stat(filepath.Clean(objPath)); err != nil {
+		// go test
+
+		objPath = filepath.Join(os.Getenv("PWD"), "..", "BPF", name)
+		if _, err := os.Stat(filepath.Clean(objPath)); err != nil {
+			return "", err
+		}
+	}
+
+	return objPath, nil
+}
+
+// LoadBPFObject Function
+func LoadBPFObject(objPath, sysPrefix string, systemCalls []string, perCPUBuffer int) (*BPFObject, error) {
+	spec, err := ebpf.LoadCollectionSpec(objPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %v", objPath, err)
+	}
+
+	obj := &BPFObject{}
+
+	// CO-RE relocations are applied against the kernel BTF here
+	obj.Collection, err = ebpf.NewCollection(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s into the kernel: %v", objPath, err)
+	}
+
+	for _, syscallName := range systemCalls {
+		if err := obj.attachKprobe(fmt.Sprintf("syscall__%s", syscallName), sysPrefix+syscallName, false); err != nil {
+			obj.Close()
+			return nil, err
+		}
+		if err := obj.attachKprobe(fmt.Sprintf("trace_ret_%s", syscallName), sysPrefix+syscallName, true); err != nil {
+			obj.Close()
+			return nil, err
+		}
+	}
+
+	tracepoints := []string{"do_exit"}
+
+	for _, tracepoint := range tracepoints {
+		if err := obj.attachKprobe(fmt.Sprintf("trace_%s", tracepoint), tracepoint, false); err != nil {
+			obj.Close()
+			return nil, err
+		}
+	}
+
+	eventsMap, ok := obj.Collection.Maps["sys_events"]
+	if !ok {
+		obj.Close()
+		return nil, errors.New("sys_events is not found")
+	}
+
+	obj.Reader, err = perf.NewReader(eventsMap, perCPUBuffer)
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("error initializing events perf buffer: %v", err)
+	}
+
+	obj.EventChan = make(chan []byte, 8192)
+	obj.LostChan = make(chan LostSample)
+
+	return obj, nil
+}
+
+// attachKprobe Function
+func (obj *BPFObject) attachKprobe(progName, symbol string, ret bool) error {
+	prog, ok := obj.Collection.Programs[progName]
+	if !ok {
+		return fmt.Errorf("error loading kprobe %s: not found", progName)
+	}
+
+	var kp link.Link
+	var err error
+
+	if ret {
+		kp, err = link.Kretprobe(symbol, prog)
+		if err != nil {
+			return fmt.Errorf("error attaching kretprobe %s: %v", symbol, err)
+		}
+	} else {
+		kp, err = link.Kprobe(symbol, prog)
+		if err != nil {
+			return fmt.Errorf("error attaching kprobe %s: %v", symbol, err)
+		}
+	}
+
+	obj.Links = append(obj.Links, kp)
+
+	return nil
+}
+
+// Start Function
+func (obj *BPFObject) Start() {
+	go func() {
+		for {
+			record, err := obj.Reader.Read()
+			if err != nil {
+				if perf.IsClosed(err) {
+					return
+				}
+				continue
+			}
+
+			if record.LostSamples > 0 {
+				obj.LostChan <- LostSample{CPU: record.CPU, Count: record.LostSamples}
+				continue
+			}
+
+			obj.EventChan <- record.RawSample
+		}
+	}()
+}
+
+// Close Function
+func (obj *BPFObject) Close() {
+	if obj.Reader != nil {
+		_ = obj.Reader.Close()
+	}
+
+	for _, kp := range obj.Links {
+		_ = kp.Close()
+	}
+	obj.Links = nil
+
+	if obj.Collection != nil {
+		obj.Collection.Close()
+	}
+}
@@ -60,6 +60,7 @@ func (mon *SystemMonitor) DeleteActiveHostPid(hostPid uint32) {
 		if node, ok := pidMap[hostPid]; ok {
 			node.Exited = true
 			node.ExitedTime = time.Now()
+			pidMap[hostPid] = node
 		}
 	}
 }
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// LogPipeline Default Values
+const (
+	DefaultLogWorkers         = 4
+	DefaultLogWorkerQueueSize = 1024
+)
+
+// Backpressure Policies (when the queue of a worker is full)
+const (
+	// wait for the worker, leaving the events in perf buffers (lost there if they overflow)
+	BackpressureBlock = "block"
+
+	// drop the event and count it
+	BackpressureDrop = "drop"
+)
+
+// ================== //
+// == Log Pipeline == //
+// ================== //
+
+// LogEvent Structure
+type LogEvent struct {
+	// events with the same key are handled in order by the same worker
+	Key string
+
+	// context to build a log from
+	Context ContextCombined
+
+	// log built already (e.g., execve at its return)
+	Log *tp.Log
+}
+
+// GetHostLogKey Function
+func GetHostLogKey(ctx SyscallContext) string {
+	// there are no containers on a host, thus the events of each process are kept in order
+	return strconv.FormatUint(uint64(ctx.HostPID), 10)
+}
+
+// LogPipelineStats Structure
+type LogPipelineStats struct {
+	Submitted uint64
+	Processed uint64
+	Dropped   uint64
+}
+
+// LogPipeline Structure
+type LogPipeline struct {
+	// counters (64-bit aligned for atomic operations)
+	submitted uint64
+	processed uint64
+	dropped   uint64
+
+	// source label for metrics (container or host)
+	Source string
+
+	// backpressure policy
+	Backpressure string
+
+	// one queue per worker
+	queues []chan LogEvent
+
+	// handler called by workers
+	handler func(LogEvent)
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewLogPipeline Function
+func NewLogPipeline(source string, workers, queueSize int, backpressure string, handler func(LogEvent)) *LogPipeline {
+	if workers <= 0 {
+		workers = DefaultLogWorkers
+	}
+
+	if queueSize <= 0 {
+		queueSize = DefaultLogWorkerQueueSize
+	}
+
+	if backpressure != BackpressureDrop {
+		backpressure = BackpressureBlock
+	}
+
+	pl := &LogPipeline{}
+
+	pl.Source = source
+	pl.Backpressure = backpressure
+
+	pl.queues = make([]chan LogEvent, workers)
+	for idx := range pl.queues {
+		pl.queues[idx] = make(chan LogEvent, queueSize)
+	}
+
+	pl.handler = handler
+	pl.stopChan = make(chan struct{})
+
+	return pl
+}
+
+// Start Function
+func (pl *LogPipeline) Start() {
+	mt.RegisterQueue("pipeline/"+pl.Source, pl.Len, pl.Cap)
+
+	for _, queue := range pl.queues {
+		pl.wg.Add(1)
+		go pl.work(queue)
+	}
+}
+
+// work Function
+func (pl *LogPipeline) work(queue chan LogEvent) {
+	defer pl.wg.Done()
+
+	for {
+		select {
+		case event := <-queue:
+			pl.handle(event)
+
+		case <-pl.stopChan:
+			// drain the events submitted before stopping
+			for {
+				select {
+				case event := <-queue:
+					pl.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle Function
+func (pl *LogPipeline) handle(event LogEvent) {
+	pl.handler(event)
+	atomic.AddUint64(&pl.processed, 1)
+}
+
+// getQueue Function
+func (pl *LogPipeline) getQueue(key string) chan LogEvent {
+	if len(pl.queues) == 1 {
+		return pl.queues[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return pl.queues[h.Sum32()%uint32(len(pl.queues))]
+}
+
+// Submit Function
+func (pl *LogPipeline) Submit(event LogEvent) bool {
+	if pl == nil {
+		return false
+	}
+
+	select {
+	case <-pl.stopChan:
+		return false
+	default:
+	}
+
+	queue := pl.getQueue(event.Key)
+
+	if pl.Backpressure == BackpressureDrop {
+		select {
+		case queue <- event:
+		default:
+			atomic.AddUint64(&pl.dropped, 1)
+			mt.DroppedLogs.WithLabelValues(pl.Source).Inc()
+			return false
+		}
+	} else {
+		select {
+		case queue <- event:
+		case <-pl.stopChan:
+			return false
+		}
+	}
+
+	atomic.AddUint64(&pl.submitted, 1)
+
+	return true
+}
+
+// Stop Function
+func (pl *LogPipeline) Stop() {
+	pl.stopOnce.Do(func() {
+		close(pl.stopChan)
+	})
+
+	pl.wg.Wait()
+}
+
+// Len Function
+func (pl *LogPipeline) Len() int {
+	n := 0
+	for _, queue := range pl.queues {
+		n += len(queue)
+	}
+	return n
+}
+
+// Cap Function
+func (pl *LogPipeline) Cap() int {
+	n := 0
+	for _, queue := range pl.queues {
+		n += cap(queue)
+	}
+	return n
+}
+
+// Stats Function
+func (pl *LogPipeline) Stats() LogPipelineStats {
+	return LogPipelineStats{
+		Submitted: atomic.LoadUint64(&pl.submitted),
+		Processed: atomic.LoadUint64(&pl.processed),
+		Dropped:   atomic.LoadUint64(&pl.dropped),
+	}
+}
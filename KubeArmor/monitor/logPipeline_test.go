@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// synthetic events from containers interleaved like on a busy node
+
+func generateLogEvents(containers, events int) []LogEvent {
+	r := rand.New(rand.NewSource(1))
+
+	generated := make([]LogEvent, 0, events)
+	seqs := make([]uint32, containers)
+
+	for i := 0; i < events; i++ {
+		idx := r.Intn(containers)
+		seqs[idx]++
+
+		ctx := SyscallContext{HostPID: seqs[idx], EventID: SysOpenAt, Argnum: 3}
+		copy(ctx.Comm[:], "bash")
+		args := []interface{}{int32(-100), "/etc/passwd", "O_RDONLY"}
+
+		containerID := "container-" + strconv.Itoa(idx)
+		generated = append(generated, LogEvent{Key: containerID, Context: ContextCombined{ContainerID: containerID, ContextSys: ctx, ContextArgs: args}})
+	}
+
+	return generated
+}
+
+func TestLogPipelineOrdering(t *testing.T) {
+	lock := sync.Mutex{}
+	lastSeqs := map[string]uint32{}
+	outOfOrder := 0
+
+	pl := NewLogPipeline("test", 4, 16, BackpressureBlock, func(event LogEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		// the sequence number of each container is in HostPID
+		if event.Context.ContextSys.HostPID != lastSeqs[event.Key]+1 {
+			outOfOrder++
+		}
+		lastSeqs[event.Key] = event.Context.ContextSys.HostPID
+	})
+	pl.Start()
+
+	events := generateLogEvents(32, 20000)
+	for _, event := range events {
+		if !pl.Submit(event) {
+			t.Errorf("[FAIL] Failed to submit an event")
+			return
+		}
+	}
+
+	pl.Stop()
+
+	stats := pl.Stats()
+	if stats.Submitted != uint64(len(events)) || stats.Processed != uint64(len(events)) || stats.Dropped != 0 {
+		t.Errorf("[FAIL] Got unexpected stats (%+v)", stats)
+		return
+	}
+	t.Log("[PASS] Processed all events with backpressure")
+
+	if outOfOrder > 0 {
+		t.Errorf("[FAIL] Processed %d events out of order", outOfOrder)
+		return
+	}
+	t.Log("[PASS] Kept the events of each container in order")
+}
+
+func TestLogPipelineDrop(t *testing.T) {
+	release := make(chan struct{})
+
+	pl := NewLogPipeline("test", 1, 4, BackpressureDrop, func(event LogEvent) {
+		<-release
+	})
+	pl.Start()
+
+	// one in the worker and four in the queue
+	accepted := 0
+	for _, event := range generateLogEvents(1, 10) {
+		if pl.Submit(event) {
+			accepted++
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	pl.Stop()
+
+	stats := pl.Stats()
+	if accepted != 5 || stats.Submitted != 5 || stats.Processed != 5 || stats.Dropped != 5 {
+		t.Errorf("[FAIL] Got unexpected stats (accepted: %d, %+v)", accepted, stats)
+		return
+	}
+	t.Log("[PASS] Dropped and counted events beyond the queue")
+
+	if pl.Submit(LogEvent{Key: "container-0"}) {
+		t.Errorf("[FAIL] Accepted an event after stopping")
+		return
+	}
+	t.Log("[PASS] Rejected events after stopping")
+}
+
+// go test ./monitor -run '^$' -bench LogPipeline -benchmem
+func BenchmarkLogPipeline(b *testing.B) {
+	mon := newTestSystemMonitor()
+
+	events := generateLogEvents(64, 65536)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		for _, backpressure := range []string{BackpressureBlock, BackpressureDrop} {
+			b.Run(fmt.Sprintf("workers=%d/%s", workers, backpressure), func(b *testing.B) {
+				pl := NewLogPipeline("bench", workers, DefaultLogWorkerQueueSize, backpressure, func(event LogEvent) {
+					// the work of building a log without the feeder
+					_, _ = mon.BuildLog(event.Context)
+				})
+				pl.Start()
+
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					pl.Submit(events[i%len(events)])
+				}
+
+				pl.Stop()
+
+				b.StopTimer()
+
+				stats := pl.Stats()
+				b.ReportMetric(float64(stats.Dropped)/float64(b.N), "drops/op")
+			})
+		}
+	}
+}
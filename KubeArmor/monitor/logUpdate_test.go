@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func newTestSystemMonitor() *SystemMonitor {
+	containers := map[string]tp.Container{}
+	containersLock := new(sync.RWMutex)
+
+	activePidMap := map[string]tp.PidMap{}
+	activeHostPidMap := map[string]tp.PidMap{}
+	activePidMapLock := new(sync.RWMutex)
+
+	activeHostMap := map[uint32]tp.PidMap{}
+	activeHostMapLock := new(sync.RWMutex)
+
+	return NewSystemMonitor(tp.Node{}, nil, &containers, &containersLock,
+		&activePidMap, &activeHostPidMap, &activePidMapLock, &activeHostMap, &activeHostMapLock)
+}
+
+func TestBuildLogEvents(t *testing.T) {
+	mon := newTestSystemMonitor()
+
+	ctx := SyscallContext{HostPID: 101, EventID: SysOpenAt, Argnum: 3, Retval: 3}
+	copy(ctx.Comm[:], "cat")
+
+	// openat(AT_FDCWD, "/etc/passwd", O_RDONLY|O_CLOEXEC)
+
+	log, ok := mon.BuildLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{int32(-100), "/etc/passwd", "O_RDONLY|O_CLOEXEC"}})
+	if !ok {
+		t.Errorf("[FAIL] Failed to build an openat log")
+		return
+	}
+
+	if log.Resource != "/etc/passwd" || log.Data != "syscall=SYS_OPENAT fd=-100 flags=O_RDONLY|O_CLOEXEC" {
+		t.Errorf("[FAIL] Changed the legacy fields (%s, %s)", log.Resource, log.Data)
+		return
+	}
+
+	if log.File == nil || log.File.Path != "/etc/passwd" || log.File.FD != -100 || strings.Join(log.File.Flags, ",") != "O_RDONLY,O_CLOEXEC" {
+		t.Errorf("[FAIL] Got an unexpected file event (%+v)", log.File)
+		return
+	}
+	t.Log("[PASS] Built a file event")
+
+	// connect(3, {AF_INET, 10.0.0.1:80})
+
+	ctx.EventID = SysConnect
+	sockAddr := map[string]string{"sa_family": "AF_INET", "sin_addr": "10.0.0.1", "sin_port": "80"}
+
+	log, ok = mon.BuildLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{int32(3), sockAddr}})
+	if !ok {
+		t.Errorf("[FAIL] Failed to build a connect log")
+		return
+	}
+
+	if !strings.Contains(log.Resource, "sin_port=80") || log.Data != "syscall=SYS_CONNECT fd=3" {
+		t.Errorf("[FAIL] Changed the legacy fields (%s, %s)", log.Resource, log.Data)
+		return
+	}
+
+	if log.Network == nil || log.Network.Family != "AF_INET" || log.Network.Addr != "10.0.0.1" || log.Network.Port != 80 || log.Network.FD != 3 {
+		t.Errorf("[FAIL] Got an unexpected network event (%+v)", log.Network)
+		return
+	}
+	t.Log("[PASS] Built a network event")
+
+	// socket(AF_INET, SOCK_STREAM, 6)
+
+	ctx.EventID = SysSocket
+
+	log, ok = mon.BuildHostLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{"AF_INET", "SOCK_STREAM", int32(6)}})
+	if !ok || log.Network == nil || log.Network.SockType != "SOCK_STREAM" || log.Network.Protocol != 6 {
+		t.Errorf("[FAIL] Got an unexpected network event (%+v)", log.Network)
+		return
+	}
+	t.Log("[PASS] Built a network event for a host")
+
+	// execve("/bin/ls", ["ls", "-al"])
+
+	process := BuildProcessEvent(ctx, "/bin/ls", []string{"ls", "-al"}, 0, "")
+	if process.ExecPath != "/bin/ls" || strings.Join(process.Args, " ") != "ls -al" || process.Comm != "cat" {
+		t.Errorf("[FAIL] Got an unexpected process event (%+v)", process)
+		return
+	}
+	t.Log("[PASS] Built a process event")
+}
+
+func TestBuildSyscallLog(t *testing.T) {
+	mon := newTestSystemMonitor()
+
+	ctx := SyscallContext{HostPID: 101, EventID: SysRenameAt2, Argnum: 4, Retval: 0}
+	copy(ctx.Comm[:], "mv")
+
+	// renameat2(AT_FDCWD, "/etc/shadow", AT_FDCWD, "/tmp/shadow")
+
+	log, ok := mon.BuildLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{int32(-100), "/etc/shadow", int32(-100), "/tmp/shadow"}})
+	if !ok || log.Operation != "File" || log.Resource != "/etc/shadow" || log.Data != "syscall=SYS_RENAMEAT2 fd=-100 newfd=-100 newpath=/tmp/shadow" {
+		t.Errorf("[FAIL] Got an unexpected renameat2 log (%s, %s, %s)", log.Operation, log.Resource, log.Data)
+		return
+	}
+
+	if log.File == nil || log.File.Path != "/etc/shadow" || log.File.FD != -100 || log.Result != "Passed" {
+		t.Errorf("[FAIL] Got an unexpected file event (%+v)", log.File)
+		return
+	}
+	t.Log("[PASS] Built a renameat2 log")
+
+	// umount2("/mnt", MNT_DETACH) = -EPERM
+
+	ctx.EventID = SysUmount2
+	ctx.Retval = -1
+
+	log, ok = mon.BuildHostLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{"/mnt", "MNT_DETACH"}})
+	if !ok || log.Operation != "Syscall" || log.Resource != "/mnt" || log.Data != "syscall=SYS_UMOUNT2 flags=MNT_DETACH" || log.Result != "Operation not permitted" {
+		t.Errorf("[FAIL] Got an unexpected umount2 log (%s, %s, %s, %s)", log.Operation, log.Resource, log.Data, log.Result)
+		return
+	}
+	t.Log("[PASS] Built an umount2 log for a host")
+
+	// setuid(0)
+
+	ctx.EventID = SysSetuid
+	ctx.Retval = 0
+
+	log, ok = mon.BuildLog(ContextCombined{ContextSys: ctx, ContextArgs: []interface{}{int32(0)}})
+	if !ok || log.Operation != "Syscall" || log.Resource != "uid=0" || log.Data != "syscall=SYS_SETUID" {
+		t.Errorf("[FAIL] Got an unexpected setuid log (%s, %s, %s)", log.Operation, log.Resource, log.Data)
+		return
+	}
+	t.Log("[PASS] Built a setuid log")
+}
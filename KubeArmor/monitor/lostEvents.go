@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// UnknownCPU is used when a perf buffer does not tell the CPU of lost events (BCC)
+const UnknownCPU = -1
+
+// DefaultLostEventsReportInterval is the interval (in seconds) to report lost events
+const DefaultLostEventsReportInterval = 10
+
+// ================= //
+// == Lost Events == //
+// ================= //
+
+// LostSample Structure
+type LostSample struct {
+	CPU   int
+	Count uint64
+}
+
+// LostEventCounter Structure
+type LostEventCounter struct {
+	// module -> cpu -> the number of events lost since the last flush
+	Pending map[string]map[int]uint64
+
+	// module -> the number of events lost since the start
+	Total map[string]uint64
+
+	Lock *sync.Mutex
+}
+
+// NewLostEventCounter Function
+func NewLostEventCounter() *LostEventCounter {
+	lc := new(LostEventCounter)
+
+	lc.Pending = map[string]map[int]uint64{}
+	lc.Total = map[string]uint64{}
+	lc.Lock = new(sync.Mutex)
+
+	return lc
+}
+
+// getCPULabel Function
+func getCPULabel(cpu int) string {
+	if cpu == UnknownCPU {
+		return "unknown"
+	}
+	return strconv.Itoa(cpu)
+}
+
+// Add Function
+func (lc *LostEventCounter) Add(module string, cpu int, count uint64) {
+	if count == 0 {
+		return
+	}
+
+	mt.LostEvents.WithLabelValues(module, getCPULabel(cpu)).Add(float64(count))
+
+	lc.Lock.Lock()
+	defer lc.Lock.Unlock()
+
+	if _, ok := lc.Pending[module]; !ok {
+		lc.Pending[module] = map[int]uint64{}
+	}
+
+	lc.Pending[module][cpu] += count
+	lc.Total[module] += count
+}
+
+// Flush Function
+func (lc *LostEventCounter) Flush() map[string]map[int]uint64 {
+	lc.Lock.Lock()
+	defer lc.Lock.Unlock()
+
+	pending := lc.Pending
+	lc.Pending = map[string]map[int]uint64{}
+
+	return pending
+}
+
+// GetTotal Function
+func (lc *LostEventCounter) GetTotal(module string) uint64 {
+	lc.Lock.Lock()
+	defer lc.Lock.Unlock()
+
+	return lc.Total[module]
+}
+
+// FormatLostEvents Function
+func FormatLostEvents(module string, perCPU map[int]uint64) string {
+	cpus := []int{}
+	total := uint64(0)
+
+	for cpu, count := range perCPU {
+		cpus = append(cpus, cpu)
+		total += count
+	}
+
+	sort.Ints(cpus)
+
+	details := []string{}
+	for _, cpu := range cpus {
+		if cpu == UnknownCPU {
+			details = append(details, fmt.Sprintf("unknown cpu: %d", perCPU[cpu]))
+		} else {
+			details = append(details, fmt.Sprintf("cpu%d: %d", cpu, perCPU[cpu]))
+		}
+	}
+
+	return fmt.Sprintf("Lost %d events in the %s monitor (%s)", total, module, strings.Join(details, ", "))
+}
+
+// ReportLostEvents Function
+func (mon *SystemMonitor) ReportLostEvents() {
+	interval := mon.LostEventsReportInterval
+	if interval <= 0 {
+		interval = DefaultLostEventsReportInterval
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-StopChan:
+			return
+
+		case <-ticker.C:
+			pending := mon.LostEvents.Flush()
+
+			modules := []string{}
+			for module := range pending {
+				modules = append(modules, module)
+			}
+			sort.Strings(modules)
+
+			for _, module := range modules {
+				mon.Logger.Warnf("%s, consider increasing perfPageCount (%d)", FormatLostEvents(module, pending[module]), mon.PerfPageCount)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"testing"
+)
+
+func TestLostEventCounter(t *testing.T) {
+	lc := NewLostEventCounter()
+
+	lc.Add("container", 3, 10)
+	lc.Add("container", 0, 5)
+	lc.Add("container", 3, 2)
+	lc.Add("host", UnknownCPU, 7)
+	lc.Add("host", 1, 0)
+
+	pending := lc.Flush()
+
+	if pending["container"][3] != 12 || pending["container"][0] != 5 || len(pending["host"]) != 1 || pending["host"][UnknownCPU] != 7 {
+		t.Errorf("[FAIL] Failed to aggregate lost events per CPU (%v)", pending)
+		return
+	}
+	t.Log("[PASS] Aggregated lost events per CPU")
+
+	if msg := FormatLostEvents("container", pending["container"]); msg != "Lost 17 events in the container monitor (cpu0: 5, cpu3: 12)" {
+		t.Errorf("[FAIL] Got an unexpected message (%s)", msg)
+		return
+	}
+
+	if msg := FormatLostEvents("host", pending["host"]); msg != "Lost 7 events in the host monitor (unknown cpu: 7)" {
+		t.Errorf("[FAIL] Got an unexpected message (%s)", msg)
+		return
+	}
+	t.Log("[PASS] Formatted lost events")
+
+	if pending := lc.Flush(); len(pending) != 0 {
+		t.Errorf("[FAIL] Failed to reset lost events (%v)", pending)
+		return
+	}
+
+	if lc.GetTotal("container") != 17 || lc.GetTotal("host") != 7 {
+		t.Errorf("[FAIL] Failed to keep the total of lost events (%d, %d)", lc.GetTotal("container"), lc.GetTotal("host"))
+		return
+	}
+	t.Log("[PASS] Kept the total of lost events after a flush")
+}
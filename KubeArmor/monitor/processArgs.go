@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// DefaultMaxArgLength is the max length of an argument in logs
+const DefaultMaxArgLength = 512
+
+// RedactedArg replaces the redacted parts of arguments
+const RedactedArg = "[REDACTED]"
+
+// TruncatedArg is appended to truncated arguments
+const TruncatedArg = "..."
+
+// ================== //
+// == Process Args == //
+// ================== //
+
+// ArgsFilter Structure
+type ArgsFilter struct {
+	// max length of an argument (0 for no limit)
+	MaxArgLength int
+
+	// arguments matching any of the rules are redacted
+	RedactionRules []*regexp.Regexp
+}
+
+// NewArgsFilter Function
+func NewArgsFilter(maxArgLength int, redactionRules []string) (*ArgsFilter, error) {
+	af := &ArgsFilter{MaxArgLength: maxArgLength}
+
+	for _, rule := range redactionRules {
+		re, err := regexp.Compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule %q (%s)", rule, err.Error())
+		}
+		af.RedactionRules = append(af.RedactionRules, re)
+	}
+
+	return af, nil
+}
+
+// redact Function
+func (af *ArgsFilter) redact(arg string) string {
+	for _, re := range af.RedactionRules {
+		// redact the groups of a rule if any (e.g., keep "--password=" in "--password=(.+)")
+		arg = re.ReplaceAllStringFunc(arg, func(matched string) string {
+			submatches := re.FindStringSubmatchIndex(matched)
+			if len(submatches) <= 2 {
+				return RedactedArg
+			}
+
+			redacted := ""
+			last := 0
+
+			for idx := 2; idx < len(submatches); idx += 2 {
+				if submatches[idx] < last {
+					continue // nested or unmatched groups
+				}
+				redacted = redacted + matched[last:submatches[idx]] + RedactedArg
+				last = submatches[idx+1]
+			}
+
+			return redacted + matched[last:]
+		})
+	}
+
+	return arg
+}
+
+// Filter Function
+func (af *ArgsFilter) Filter(args []string) []string {
+	filtered := make([]string, 0, len(args))
+
+	for idx, arg := range args {
+		// keep argv[0] as it is
+		if idx > 0 && af != nil {
+			arg = af.redact(arg)
+
+			// truncate after redaction, not to leave a part of secrets
+			if af.MaxArgLength > 0 && len(arg) > af.MaxArgLength {
+				end := af.MaxArgLength
+				for end > 0 && !utf8.RuneStart(arg[end]) {
+					end--
+				}
+				arg = arg[:end] + TruncatedArg
+			}
+		}
+
+		filtered = append(filtered, arg)
+	}
+
+	return filtered
+}
+
+// SetArgsFilter Function
+func (mon *SystemMonitor) SetArgsFilter(maxArgLength int, redactionRules []string) error {
+	af, err := NewArgsFilter(maxArgLength, redactionRules)
+	if err != nil {
+		return err
+	}
+
+	mon.ArgsFilterLock.Lock()
+	defer mon.ArgsFilterLock.Unlock()
+
+	mon.ArgsFilter = af
+
+	return nil
+}
+
+// FilterArgs Function
+func (mon *SystemMonitor) FilterArgs(args []string) []string {
+	mon.ArgsFilterLock.RLock()
+	defer mon.ArgsFilterLock.RUnlock()
+
+	return mon.ArgsFilter.Filter(args)
+}
@@ -4,6 +4,7 @@
 package monitor
 
 import (
+	"bytes"
 	"time"
 
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
@@ -71,8 +72,9 @@ func (mon *SystemMonitor) BuildPidNode(ctx SyscallContext, execPath string, args
 	node.PID = ctx.PID
 	node.UID = ctx.UID
 
-	node.Comm = string(ctx.Comm[:])
+	node.Comm = string(ctx.Comm[:bytes.IndexByte(ctx.Comm[:], 0)])
 	node.ExecPath = execPath
+	node.Args = append([]string{}, args...)
 
 	for idx, arg := range args {
 		if idx == 0 {
@@ -148,6 +150,62 @@ func (mon *SystemMonitor) GetExecPathWithHostPID(containerID string, hostPid uin
 	return ""
 }
 
+// GetProcessAncestry Function
+func (mon *SystemMonitor) GetProcessAncestry(containerID string, hostPid uint32) []tp.ProcessAncestor {
+	if mon.AncestryDepth <= 0 {
+		return nil
+	}
+
+	ActiveHostPidMap := *(mon.ActiveHostPidMap)
+	ActivePidMapLock := *(mon.ActivePidMapLock)
+
+	ActiveHostMap := *(mon.ActiveHostMap)
+	ActiveHostMapLock := *(mon.ActiveHostMapLock)
+
+	ActivePidMapLock.RLock()
+	defer ActivePidMapLock.RUnlock()
+
+	ActiveHostMapLock.RLock()
+	defer ActiveHostMapLock.RUnlock()
+
+	ancestry := []tp.ProcessAncestor{}
+
+	// pids can be reused, so stop at a cycle
+	visited := map[uint32]bool{}
+
+	for pid := hostPid; pid != 0 && !visited[pid] && len(ancestry) < mon.AncestryDepth; {
+		visited[pid] = true
+
+		// processes in the container, and then the ones on the host (e.g., containerd-shim)
+		// exited parents are still there until CleanUpExitedHostPids removes them
+		node, ok := ActiveHostPidMap[containerID][pid]
+		if !ok {
+			node, ok = ActiveHostMap[pid][pid]
+		}
+
+		if !ok {
+			break
+		}
+
+		ancestry = append(ancestry, tp.ProcessAncestor{
+			HostPID:  int32(node.HostPID),
+			PID:      int32(node.PID),
+			Comm:     node.Comm,
+			ExecPath: node.ExecPath,
+			Exited:   node.Exited,
+		})
+
+		pid = node.HostPPID
+	}
+
+	// from the oldest ancestor to the process itself
+	for i, j := 0, len(ancestry)-1; i < j; i, j = i+1, j-1 {
+		ancestry[i], ancestry[j] = ancestry[j], ancestry[i]
+	}
+
+	return ancestry
+}
+
 // DeleteActivePid Function
 func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext) {
 	ActivePidMap := *(mon.ActivePidMap)
@@ -161,6 +219,7 @@ func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext
 		if node, ok := pidMap[ctx.PID]; ok {
 			node.Exited = true
 			node.ExitedTime = time.Now()
+			pidMap[ctx.PID] = node
 		}
 	}
 
@@ -171,6 +230,7 @@ func (mon *SystemMonitor) DeleteActivePid(containerID string, ctx SyscallContext
 		if node, ok := pidMap[ctx.HostPID]; ok {
 			node.Exited = true
 			node.ExitedTime = time.Now()
+			pidMap[ctx.HostPID] = node
 		}
 	}
 }
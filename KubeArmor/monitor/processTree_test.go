@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func getAncestryChain(ancestry []tp.ProcessAncestor) string {
+	comms := []string{}
+	for _, ancestor := range ancestry {
+		comms = append(comms, ancestor.Comm)
+	}
+	return strings.Join(comms, " -> ")
+}
+
+func TestGetProcessAncestry(t *testing.T) {
+	mon := newTestSystemMonitor()
+
+	containerID := "0123456789abcdef"
+
+	// containerd-shim (host) -> bash -> curl (container)
+
+	ctx := SyscallContext{HostPPID: 1, HostPID: 100}
+	copy(ctx.Comm[:], "containerd-shim")
+	mon.AddActiveHostPid(ctx.HostPID, mon.BuildPidNode(ctx, "/usr/bin/containerd-shim", []string{"containerd-shim"}))
+
+	ctx = SyscallContext{HostPPID: 100, HostPID: 200, PPID: 0, PID: 1}
+	copy(ctx.Comm[:], "bash")
+	mon.AddActivePid(containerID, mon.BuildPidNode(ctx, "/bin/bash", []string{"bash"}))
+
+	ctx = SyscallContext{HostPPID: 200, HostPID: 300, PPID: 1, PID: 2}
+	copy(ctx.Comm[:], "curl")
+	mon.AddActivePid(containerID, mon.BuildPidNode(ctx, "/usr/bin/curl", []string{"curl", "example.com"}))
+
+	ancestry := mon.GetProcessAncestry(containerID, 300)
+	if chain := getAncestryChain(ancestry); chain != "containerd-shim -> bash -> curl" {
+		t.Errorf("[FAIL] Got an unexpected ancestry (%s)", chain)
+		return
+	}
+
+	if ancestry[2].ExecPath != "/usr/bin/curl example.com" || ancestry[2].HostPID != 300 || ancestry[2].PID != 2 {
+		t.Errorf("[FAIL] Got an unexpected process (%+v)", ancestry[2])
+		return
+	}
+	t.Log("[PASS] Got the exec chain across the container and the host")
+
+	// bash exits before the log of curl is built
+
+	mon.DeleteActivePid(containerID, SyscallContext{HostPID: 200, PID: 1})
+
+	ancestry = mon.GetProcessAncestry(containerID, 300)
+	if chain := getAncestryChain(ancestry); chain != "containerd-shim -> bash -> curl" || !ancestry[1].Exited || ancestry[2].Exited {
+		t.Errorf("[FAIL] Lost an exited parent (%+v)", ancestry)
+		return
+	}
+	t.Log("[PASS] Kept an exited parent in the chain")
+
+	// depth
+
+	mon.AncestryDepth = 2
+
+	if chain := getAncestryChain(mon.GetProcessAncestry(containerID, 300)); chain != "bash -> curl" {
+		t.Errorf("[FAIL] Got an unexpected ancestry with depth 2 (%s)", chain)
+		return
+	}
+
+	mon.AncestryDepth = 0
+
+	if ancestry := mon.GetProcessAncestry(containerID, 300); len(ancestry) != 0 {
+		t.Errorf("[FAIL] Got an ancestry with depth 0 (%+v)", ancestry)
+		return
+	}
+	t.Log("[PASS] Limited the depth of the ancestry")
+
+	// a pid reused by its own descendant
+
+	mon.AncestryDepth = DefaultAncestryDepth
+
+	ctx = SyscallContext{HostPPID: 300, HostPID: 100}
+	copy(ctx.Comm[:], "sh")
+	mon.AddActivePid(containerID, mon.BuildPidNode(ctx, "/bin/sh", []string{"sh"}))
+
+	if chain := getAncestryChain(mon.GetProcessAncestry(containerID, 100)); chain != "bash -> curl -> sh" {
+		t.Errorf("[FAIL] Got an unexpected ancestry with a cycle (%s)", chain)
+		return
+	}
+	t.Log("[PASS] Stopped at a cycle")
+}
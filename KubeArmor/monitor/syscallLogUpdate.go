@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"strconv"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ================= //
+// == Syscall Log == //
+// ================= //
+
+// getStringArg Function
+func getStringArg(args []interface{}, idx int) string {
+	if idx < len(args) {
+		if val, ok := args[idx].(string); ok {
+			return val
+		}
+	}
+	return ""
+}
+
+// getIntArg Function
+func getIntArg(args []interface{}, idx int) (string, int32) {
+	if idx < len(args) {
+		if val, ok := args[idx].(int32); ok {
+			return strconv.Itoa(int(val)), val
+		}
+	}
+	return "", 0
+}
+
+// buildSyscallLog Function
+func buildSyscallLog(log *tp.Log, msg ContextCombined) bool {
+	args := msg.ContextArgs
+	syscall := "syscall=" + getSyscallName(int32(msg.ContextSys.EventID))
+
+	switch msg.ContextSys.EventID {
+	case SysUnlink: // path
+		path := getStringArg(args, 0)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall
+
+		log.File = &tp.FileEvent{Path: path}
+
+	case SysUnlinkAt: // fd, path, flags
+		fd, fdVal := getIntArg(args, 0)
+		path := getStringArg(args, 1)
+		flags := getStringArg(args, 2)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " fd=" + fd + " flags=" + flags
+
+		log.File = &tp.FileEvent{Path: path, FD: fdVal, Flags: splitFlags(flags)}
+
+	case SysRename: // oldpath, newpath
+		path := getStringArg(args, 0)
+		newPath := getStringArg(args, 1)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " newpath=" + newPath
+
+		log.File = &tp.FileEvent{Path: path}
+
+	case SysRenameAt, SysRenameAt2: // olddirfd, oldpath, newdirfd, newpath
+		fd, fdVal := getIntArg(args, 0)
+		path := getStringArg(args, 1)
+		newFd, _ := getIntArg(args, 2)
+		newPath := getStringArg(args, 3)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " fd=" + fd + " newfd=" + newFd + " newpath=" + newPath
+
+		log.File = &tp.FileEvent{Path: path, FD: fdVal}
+
+	case SysChmod: // path, mode
+		path := getStringArg(args, 0)
+		mode := getStringArg(args, 1)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " mode=" + mode
+
+		log.File = &tp.FileEvent{Path: path}
+
+	case SysFchmodAt: // fd, path, mode
+		fd, fdVal := getIntArg(args, 0)
+		path := getStringArg(args, 1)
+		mode := getStringArg(args, 2)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " fd=" + fd + " mode=" + mode
+
+		log.File = &tp.FileEvent{Path: path, FD: fdVal}
+
+	case SysChown, SysLchown: // path, uid, gid
+		path := getStringArg(args, 0)
+		uid, _ := getIntArg(args, 1)
+		gid, _ := getIntArg(args, 2)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " uid=" + uid + " gid=" + gid
+
+		log.File = &tp.FileEvent{Path: path}
+
+	case SysFchown: // fd, uid, gid
+		fd, fdVal := getIntArg(args, 0)
+		uid, _ := getIntArg(args, 1)
+		gid, _ := getIntArg(args, 2)
+
+		log.Operation = "File"
+		log.Resource = ""
+		log.Data = syscall + " fd=" + fd + " uid=" + uid + " gid=" + gid
+
+		log.File = &tp.FileEvent{FD: fdVal}
+
+	case SysFchownAt: // fd, path, uid, gid, flags
+		fd, fdVal := getIntArg(args, 0)
+		path := getStringArg(args, 1)
+		uid, _ := getIntArg(args, 2)
+		gid, _ := getIntArg(args, 3)
+		flags := getStringArg(args, 4)
+
+		log.Operation = "File"
+		log.Resource = path
+		log.Data = syscall + " fd=" + fd + " uid=" + uid + " gid=" + gid + " flags=" + flags
+
+		log.File = &tp.FileEvent{Path: path, FD: fdVal, Flags: splitFlags(flags)}
+
+	case SysMount: // source, target, fstype, flags
+		log.Operation = "Syscall"
+		log.Resource = getStringArg(args, 1)
+		log.Data = syscall + " source=" + getStringArg(args, 0) + " fstype=" + getStringArg(args, 2) + " flags=" + getStringArg(args, 3)
+
+	case SysUmount2: // target, flags
+		log.Operation = "Syscall"
+		log.Resource = getStringArg(args, 0)
+		log.Data = syscall + " flags=" + getStringArg(args, 1)
+
+	case SysPtrace: // request, pid
+		pid, _ := getIntArg(args, 1)
+
+		log.Operation = "Syscall"
+		log.Resource = "request=" + getStringArg(args, 0) + " pid=" + pid
+		log.Data = syscall
+
+	case SysSetuid: // uid
+		uid, _ := getIntArg(args, 0)
+
+		log.Operation = "Syscall"
+		log.Resource = "uid=" + uid
+		log.Data = syscall
+
+	case SysSetgid: // gid
+		gid, _ := getIntArg(args, 0)
+
+		log.Operation = "Syscall"
+		log.Resource = "gid=" + gid
+		log.Data = syscall
+
+	case SysSetns: // fd, nstype
+		fd, _ := getIntArg(args, 0)
+
+		log.Operation = "Syscall"
+		log.Resource = "fd=" + fd + " nstype=" + getStringArg(args, 1)
+		log.Data = syscall
+
+	case SysUnshare: // flags
+		log.Operation = "Syscall"
+		log.Resource = "flags=" + getStringArg(args, 0)
+		log.Data = syscall
+
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// events in the same layout as the ones written to sys_events
+
+func writeContext(buff *bytes.Buffer, eventID, argnum int32, retval int64) {
+	ctx := SyscallContext{Ts: 1, PidID: 4026532000, MntID: 4026532001, HostPPID: 100, HostPID: 101, PPID: 1, PID: 2, UID: 0, EventID: eventID, Argnum: argnum, Retval: retval}
+	copy(ctx.Comm[:], "bash")
+	_ = binary.Write(buff, binary.LittleEndian, ctx)
+}
+
+func writeInt(buff *bytes.Buffer, argType uint8, val int32) {
+	buff.WriteByte(argType)
+	_ = binary.Write(buff, binary.LittleEndian, val)
+}
+
+func writeString(buff *bytes.Buffer, val string) {
+	buff.WriteByte(strT)
+	_ = binary.Write(buff, binary.LittleEndian, int32(len(val)+1))
+	buff.WriteString(val)
+	buff.WriteByte(0)
+}
+
+func TestDecodeSyscallEvent(t *testing.T) {
+	// openat(AT_FDCWD, "/etc/passwd", O_RDONLY) = 3
+
+	buff := new(bytes.Buffer)
+	writeContext(buff, SysOpenAt, 3, 3)
+	writeInt(buff, intT, -100)
+	writeString(buff, "/etc/passwd")
+	writeInt(buff, openFlagsT, 0)
+
+	ctx, args, err := DecodeSyscallEvent(buff.Bytes())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to decode an openat event (%s)", err.Error())
+		return
+	}
+
+	if ctx.EventID != SysOpenAt || ctx.Retval != 3 || ctx.HostPID != 101 || !strings.HasPrefix(string(ctx.Comm[:]), "bash") {
+		t.Errorf("[FAIL] Got an unexpected context (%+v)", ctx)
+		return
+	}
+
+	if len(args) != 3 || args[0].(int32) != -100 || args[1].(string) != "/etc/passwd" || args[2].(string) != "O_RDONLY" {
+		t.Errorf("[FAIL] Got unexpected arguments (%v)", args)
+		return
+	}
+	t.Log("[PASS] Decoded an openat event")
+
+	// execve("/bin/ls", ["ls", "-al"])
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysExecve, 2, 0)
+	writeString(buff, "/bin/ls")
+	buff.WriteByte(strArrT)
+	writeString(buff, "ls")
+	writeString(buff, "-al")
+	buff.WriteByte(strArrT)
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to decode an execve event (%s)", err.Error())
+		return
+	}
+
+	if len(args) != 2 || args[0].(string) != "/bin/ls" || strings.Join(args[1].([]string), " ") != "ls -al" {
+		t.Errorf("[FAIL] Got unexpected arguments (%v)", args)
+		return
+	}
+	t.Log("[PASS] Decoded an execve event")
+
+	// connect(3, {AF_INET, 10.0.0.1:80}) = 0
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysConnect, 2, 0)
+	writeInt(buff, intT, 3)
+	buff.WriteByte(sockAddrT)
+	_ = binary.Write(buff, binary.LittleEndian, int16(2))
+	_ = binary.Write(buff, binary.BigEndian, uint16(80))
+	buff.Write([]byte{10, 0, 0, 1})
+	buff.Write(make([]byte, 8)) // sin_zero
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil {
+		t.Errorf("[FAIL] Failed to decode a connect event (%s)", err.Error())
+		return
+	}
+
+	sockAddr := args[1].(map[string]string)
+	if sockAddr["sa_family"] != "AF_INET" || sockAddr["sin_port"] != "80" || sockAddr["sin_addr"] != "10.0.0.1" {
+		t.Errorf("[FAIL] Got an unexpected sockaddr (%v)", sockAddr)
+		return
+	}
+	t.Log("[PASS] Decoded a connect event")
+
+	// unlinkat(AT_FDCWD, "/tmp/dir", AT_REMOVEDIR) = 0
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysUnlinkAt, 3, 0)
+	writeInt(buff, intT, -100)
+	writeString(buff, "/tmp/dir")
+	writeInt(buff, atFlagsT, 0x200)
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil || len(args) != 3 || args[1].(string) != "/tmp/dir" || args[2].(string) != "AT_REMOVEDIR" {
+		t.Errorf("[FAIL] Failed to decode an unlinkat event (%v)", args)
+		return
+	}
+	t.Log("[PASS] Decoded an unlinkat event")
+
+	// mount("/dev/sda1", "/mnt", "ext4", MS_RDONLY|MS_BIND|MS_REC) = 0
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysMount, 4, 0)
+	writeString(buff, "/dev/sda1")
+	writeString(buff, "/mnt")
+	writeString(buff, "ext4")
+	writeInt(buff, mountFlagsT, 1|4096|16384)
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil || len(args) != 4 || args[1].(string) != "/mnt" || args[3].(string) != "MS_RDONLY|MS_BIND|MS_REC" {
+		t.Errorf("[FAIL] Failed to decode a mount event (%v)", args)
+		return
+	}
+	t.Log("[PASS] Decoded a mount event")
+
+	// ptrace(PTRACE_ATTACH, 1234) = 0, unshare(CLONE_NEWNS|CLONE_NEWNET) = 0
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysPtrace, 2, 0)
+	writeInt(buff, ptraceReqT, 16)
+	writeInt(buff, intT, 1234)
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil || len(args) != 2 || args[0].(string) != "PTRACE_ATTACH" || args[1].(int32) != 1234 {
+		t.Errorf("[FAIL] Failed to decode a ptrace event (%v)", args)
+		return
+	}
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysUnshare, 1, 0)
+	writeInt(buff, nsFlagsT, 0x00020000|0x40000000)
+
+	_, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil || len(args) != 1 || args[0].(string) != "CLONE_NEWNS|CLONE_NEWNET" {
+		t.Errorf("[FAIL] Failed to decode an unshare event (%v)", args)
+		return
+	}
+	t.Log("[PASS] Decoded ptrace and unshare events")
+
+	// do_exit (no arguments)
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, DoExit, 0, 0)
+
+	ctx, args, err = DecodeSyscallEvent(buff.Bytes())
+	if err != nil || ctx.EventID != DoExit || len(args) != 0 {
+		t.Errorf("[FAIL] Failed to decode a do_exit event")
+		return
+	}
+	t.Log("[PASS] Decoded a do_exit event")
+}
+
+func TestDecodeInvalidSyscallEvent(t *testing.T) {
+	// truncated context
+
+	buff := new(bytes.Buffer)
+	writeContext(buff, SysOpen, 2, 3)
+
+	if _, _, err := DecodeSyscallEvent(buff.Bytes()[:20]); err == nil {
+		t.Errorf("[FAIL] Accepted a truncated context")
+		return
+	}
+	t.Log("[PASS] Rejected a truncated context")
+
+	// missing arguments
+
+	writeString(buff, "/etc/passwd")
+
+	if _, _, err := DecodeSyscallEvent(buff.Bytes()); err == nil {
+		t.Errorf("[FAIL] Accepted missing arguments")
+		return
+	}
+	t.Log("[PASS] Rejected missing arguments")
+
+	// unknown argument type
+
+	buff = new(bytes.Buffer)
+	writeContext(buff, SysOpen, 1, 3)
+	writeInt(buff, 99, 0)
+
+	if _, _, err := DecodeSyscallEvent(buff.Bytes()); err == nil {
+		t.Errorf("[FAIL] Accepted an unknown argument type")
+		return
+	}
+	t.Log("[PASS] Rejected an unknown argument type")
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// KallsymsPath is the path to kernel symbols
+const KallsymsPath = "/proc/kallsyms"
+
+// names of the events that are not system calls
+var eventNames = map[int32]string{
+	DoExit: "DO_EXIT",
+	352:    "CAP_CAPABLE",
+}
+
+// system calls to trace (umount2 is sys_umount in the kernel)
+var tracedSyscalls = []string{"open", "openat", "execve", "execveat", "socket", "connect", "accept", "bind", "listen",
+	"unlink", "unlinkat", "rename", "renameat", "renameat2", "chmod", "fchmodat", "chown", "fchown", "lchown", "fchownat",
+	"mount", "umount", "ptrace", "setuid", "setgid", "setns", "unshare"}
+
+// ==================== //
+// == Syscall Tables == //
+// ==================== //
+
+// SyscallTable Structure
+type SyscallTable struct {
+	// prefix of syscall wrappers (x86_64 since 4.17, arm64 since 4.19)
+	WrapperPrefix string
+
+	// syscall number -> syscall name (e.g., SYS_OPENAT)
+	Syscalls map[int32]string
+}
+
+// SyscallTables for the supported architectures
+var SyscallTables = map[string]SyscallTable{
+	"amd64": {WrapperPrefix: "__x64_sys_", Syscalls: amd64Syscalls},
+	"arm64": {WrapperPrefix: "__arm64_sys_", Syscalls: arm64Syscalls},
+}
+
+// GetSyscallTable Function
+func GetSyscallTable(arch string) (SyscallTable, error) {
+	arch = kl.GetArchitecture(arch)
+
+	table, ok := SyscallTables[arch]
+	if !ok {
+		return SyscallTable{}, fmt.Errorf("unsupported architecture %s", arch)
+	}
+
+	return table, nil
+}
+
+// GetSystemCalls Function
+func GetSystemCalls(arch string) ([]string, error) {
+	table, err := GetSyscallTable(arch)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, name := range table.Syscalls {
+		names[name] = true
+	}
+
+	systemCalls := []string{}
+
+	for _, syscall := range tracedSyscalls {
+		name := "SYS_" + strings.ToUpper(syscall)
+		if syscall == "umount" {
+			name = "SYS_UMOUNT2"
+		}
+
+		// e.g., arm64 only has openat, unlinkat, renameat, fchmodat and fchownat
+		if names[name] {
+			systemCalls = append(systemCalls, syscall)
+		}
+	}
+
+	return systemCalls, nil
+}
+
+// getSyscallPrefix Function
+func getSyscallPrefix(arch string, kallsyms io.Reader) string {
+	table, err := GetSyscallTable(arch)
+	if err != nil || kallsyms == nil {
+		return "sys_"
+	}
+
+	// look for the wrapper of a syscall that exists on every architecture
+	scanner := bufio.NewScanner(kallsyms)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && fields[2] == table.WrapperPrefix+"bpf" {
+			return table.WrapperPrefix
+		}
+	}
+
+	return "sys_"
+}
+
+// GetSyscallPrefix Function
+func GetSyscallPrefix(arch string) string {
+	kallsyms, err := os.Open(KallsymsPath)
+	if err != nil {
+		return getSyscallPrefix(arch, nil)
+	}
+	defer kallsyms.Close()
+
+	return getSyscallPrefix(arch, kallsyms)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+)
+
+func TestSyscallTables(t *testing.T) {
+	tests := []struct {
+		arch    string
+		name    string
+		nr      int32
+		missing []string
+		prefix  string
+	}{
+		{arch: "amd64", name: "SYS_OPENAT", nr: 257, prefix: "__x64_sys_"},
+		{arch: "x86_64", name: "SYS_EXECVE", nr: 59, prefix: "__x64_sys_"},
+		{arch: "arm64", name: "SYS_OPENAT", nr: 56, missing: []string{"open", "unlink", "rename", "chmod", "chown", "lchown"}, prefix: "__arm64_sys_"},
+		{arch: "aarch64", name: "SYS_EXECVE", nr: 221, missing: []string{"open"}, prefix: "__arm64_sys_"},
+	}
+
+	for _, test := range tests {
+		table, err := GetSyscallTable(test.arch)
+		if err != nil {
+			t.Errorf("[FAIL] Failed to get the syscall table for %s (%s)", test.arch, err.Error())
+			return
+		}
+
+		if name, ok := table.Syscalls[test.nr]; !ok || name != test.name {
+			t.Errorf("[FAIL] Got an unexpected name of %d on %s (%s)", test.nr, test.arch, name)
+			return
+		}
+
+		systemCalls, err := GetSystemCalls(test.arch)
+		if err != nil {
+			t.Errorf("[FAIL] Failed to get syscalls to trace on %s (%s)", test.arch, err.Error())
+			return
+		}
+
+		for _, syscall := range []string{"openat", "execve", "unlinkat", "fchownat", "umount", "setns"} {
+			if !kl.ContainsElement(systemCalls, syscall) {
+				t.Errorf("[FAIL] Failed to trace %s on %s", syscall, test.arch)
+				return
+			}
+		}
+
+		for _, syscall := range test.missing {
+			if kl.ContainsElement(systemCalls, syscall) {
+				t.Errorf("[FAIL] Traced %s that does not exist on %s", syscall, test.arch)
+				return
+			}
+		}
+
+		kallsyms := "ffffffff81000000 T " + test.prefix + "read\nffffffff81000010 T " + test.prefix + "bpf\n"
+
+		if prefix := getSyscallPrefix(test.arch, strings.NewReader(kallsyms)); prefix != test.prefix {
+			t.Errorf("[FAIL] Got an unexpected syscall prefix on %s (%s)", test.arch, prefix)
+			return
+		}
+
+		if prefix := getSyscallPrefix(test.arch, strings.NewReader("ffffffff81000010 T sys_bpf\n")); prefix != "sys_" {
+			t.Errorf("[FAIL] Got an unexpected syscall prefix without wrappers on %s (%s)", test.arch, prefix)
+			return
+		}
+
+		t.Logf("[PASS] Got the syscall table for %s", test.arch)
+	}
+
+	if _, err := GetSystemCalls("riscv64"); err == nil {
+		t.Errorf("[FAIL] Got syscalls to trace on an unsupported architecture")
+		return
+	}
+	t.Log("[PASS] Rejected an unsupported architecture")
+
+	// event IDs are the same on every architecture
+	if name := getSyscallName(SysOpenAt); name != "SYS_OPENAT" {
+		t.Errorf("[FAIL] Got an unexpected name of an event (%s)", name)
+		return
+	}
+
+	if name := getSyscallName(DoExit); name != "DO_EXIT" {
+		t.Errorf("[FAIL] Got an unexpected name of an event (%s)", name)
+		return
+	}
+	t.Log("[PASS] Got the names of events")
+}
@@ -4,7 +4,6 @@
 package monitor
 
 import (
-	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -19,7 +18,9 @@ import (
 	"github.com/iovisor/gobpf/bcc"
 
 	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	dc "github.com/kubearmor/KubeArmor/KubeArmor/discovery"
 	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	mt "github.com/kubearmor/KubeArmor/KubeArmor/metrics"
 	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
 )
 
@@ -33,6 +34,21 @@ const (
 	SysOpenAt = 257
 	SysClose  = 3
 
+	SysUnlink    = 87
+	SysUnlinkAt  = 263
+	SysRename    = 82
+	SysRenameAt  = 264
+	SysRenameAt2 = 316
+	SysChmod     = 90
+	SysFchmodAt  = 268
+	SysChown     = 92
+	SysFchown    = 93
+	SysLchown    = 94
+	SysFchownAt  = 260
+
+	SysMount   = 165
+	SysUmount2 = 166
+
 	SysSocket  = 41
 	SysConnect = 42
 	SysAccept  = 43
@@ -42,12 +58,21 @@ const (
 	SysExecve   = 59
 	SysExecveAt = 322
 	DoExit      = 351
+
+	SysPtrace  = 101
+	SysSetuid  = 105
+	SysSetgid  = 106
+	SysSetns   = 308
+	SysUnshare = 272
 )
 
 // SystemMonitor Constant Values
 const (
 	PermissionDenied = -13
 	MaxStringLen     = 4096
+
+	// number of processes in the ancestry of a log
+	DefaultAncestryDepth = 8
 )
 
 // ======================= //
@@ -117,6 +142,9 @@ type SystemMonitor struct {
 	// logs
 	Logger *fd.Feeder
 
+	// learning mode (nil if disabled)
+	Discovery *dc.Discovery
+
 	// container id -> cotnainer
 	Containers     *map[string]tp.Container
 	ContainersLock **sync.RWMutex
@@ -130,11 +158,20 @@ type SystemMonitor struct {
 	NsMap     map[NsKey]string
 	NsMapLock *sync.RWMutex
 
+	// PidID + MntID -> visibility flags (mirrored in visibility_map)
+	VisibilityMap     map[NsKey]uint32
+	VisibilityMapLock *sync.RWMutex
+
+	// the mount namespace of the host (for host visibility)
+	HostMntNS uint32
+
 	// system monitor (for container)
-	BpfModule *bcc.Module
+	BpfModule      *bcc.Module
+	BpfObject      *BPFObject
+	AttachedProbes int
 
-	// context + args (for container)
-	ContextChan chan ContextCombined
+	// logs in order (for container)
+	LogPipeline *LogPipeline
 
 	// process + file (for container)
 	SyscallChannel     chan []byte
@@ -146,10 +183,12 @@ type SystemMonitor struct {
 	ActiveHostMapLock **sync.RWMutex
 
 	// system monitor (for host)
-	HostBpfModule *bcc.Module
+	HostBpfModule      *bcc.Module
+	HostBpfObject      *BPFObject
+	HostAttachedProbes int
 
-	// context + args (for host)
-	HostContextChan chan ContextCombined
+	// logs in order (for host)
+	HostLogPipeline *LogPipeline
 
 	// process + file (for host)
 	HostSyscallChannel     chan []byte
@@ -157,7 +196,30 @@ type SystemMonitor struct {
 	HostSyscallPerfMap     *bcc.PerfMap
 
 	// lists to skip
-	UntrackedNamespaces []string
+	UntrackedNamespaces     []string
+	UntrackedNamespacesLock *sync.RWMutex
+
+	// architecture of the node (GOARCH style)
+	Arch string
+
+	// number of pages per CPU for perf buffers
+	PerfPageCount int
+
+	// events lost in perf buffers, and the interval (in seconds) to report them
+	LostEvents               *LostEventCounter
+	LostEventsReportInterval int
+
+	// max number of processes in the ancestry of a log (0 to disable)
+	AncestryDepth int
+
+	// truncation and redaction of exec arguments
+	ArgsFilter     *ArgsFilter
+	ArgsFilterLock *sync.RWMutex
+
+	// log pipelines, workers and the queue size per worker, and {block|drop} when queues are full
+	LogWorkers         int
+	LogWorkerQueueSize int
+	LogBackpressure    string
 
 	UptimeTimeStamp float64
 	HostByteOrder   binary.ByteOrder
@@ -177,6 +239,7 @@ func NewSystemMonitor(node tp.Node, logger *fd.Feeder, containers *map[string]tp
 
 	mon.HostName = node.NodeName
 	mon.KernelVersion = node.KernelVersion
+	mon.Arch = kl.GetArchitecture(node.Architecture)
 
 	mon.EnableKubeArmorPolicy = node.EnableKubeArmorPolicy
 	mon.EnableKubeArmorHostPolicy = node.EnableKubeArmorHostPolicy
@@ -196,10 +259,27 @@ func NewSystemMonitor(node tp.Node, logger *fd.Feeder, containers *map[string]tp
 	mon.NsMap = make(map[NsKey]string)
 	mon.NsMapLock = new(sync.RWMutex)
 
-	mon.ContextChan = make(chan ContextCombined, 4096)
-	mon.HostContextChan = make(chan ContextCombined, 4096)
+	mon.VisibilityMap = make(map[NsKey]uint32)
+	mon.VisibilityMapLock = new(sync.RWMutex)
+
+	mon.HostMntNS = getHostMntNS()
 
 	mon.UntrackedNamespaces = []string{"kube-system", "kubearmor"}
+	mon.UntrackedNamespacesLock = new(sync.RWMutex)
+
+	mon.PerfPageCount = 64
+
+	mon.LostEvents = NewLostEventCounter()
+	mon.LostEventsReportInterval = DefaultLostEventsReportInterval
+
+	mon.AncestryDepth = DefaultAncestryDepth
+
+	mon.ArgsFilter = &ArgsFilter{MaxArgLength: DefaultMaxArgLength}
+	mon.ArgsFilterLock = new(sync.RWMutex)
+
+	mon.LogWorkers = DefaultLogWorkers
+	mon.LogWorkerQueueSize = DefaultLogWorkerQueueSize
+	mon.LogBackpressure = BackpressureBlock
 
 	mon.UptimeTimeStamp = kl.GetUptimeTimestamp()
 	mon.HostByteOrder = bcc.GetHostByteOrder()
@@ -218,6 +298,24 @@ func (mon *SystemMonitor) InitBPF() error {
 		return err
 	}
 
+	// the syscalls and their kernel symbols depend on the architecture
+	systemCalls, err := GetSystemCalls(mon.Arch)
+	if err != nil {
+		return err
+	}
+	sysPrefix := GetSyscallPrefix(mon.Arch)
+
+	// use the precompiled CO-RE objects if the kernel has BTF, otherwise fall back to BCC
+	if IsBTFAvailable() {
+		err := mon.InitBPFObjects(homeDir, sysPrefix, systemCalls)
+		if err == nil {
+			return nil
+		}
+
+		mon.Logger.Warnf("Failed to load CO-RE eBPF objects, falling back to BCC (%s)", err.Error())
+		mon.DestroyBPFObjects()
+	}
+
 	if kl.IsInK8sCluster() {
 		if b, err := ioutil.ReadFile(filepath.Clean("/media/root/etc/os-release")); err == nil {
 			s := string(b)
@@ -294,9 +392,6 @@ func (mon *SystemMonitor) InitBPF() error {
 
 	mon.Logger.Print("Initialized the eBPF program")
 
-	sysPrefix := bcc.GetSyscallPrefix()
-	systemCalls := []string{"open", "openat", "execve", "execveat", "socket", "connect", "accept", "bind", "listen"}
-
 	if mon.BpfModule != nil {
 		for _, syscallName := range systemCalls {
 			kp, err := mon.BpfModule.LoadKprobe(fmt.Sprintf("syscall__%s", syscallName))
@@ -307,6 +402,7 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kprobe %s: %v", syscallName, err)
 			}
+			mon.AttachedProbes++
 			kp, err = mon.BpfModule.LoadKprobe(fmt.Sprintf("trace_ret_%s", syscallName))
 			if err != nil {
 				return fmt.Errorf("error loading kprobe %s: %v", syscallName, err)
@@ -315,6 +411,7 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kretprobe %s: %v", syscallName, err)
 			}
+			mon.AttachedProbes++
 		}
 
 		tracepoints := []string{"do_exit"}
@@ -328,13 +425,14 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kprobe %s: %v", tracepoint, err)
 			}
+			mon.AttachedProbes++
 		}
 
 		eventsTable := bcc.NewTable(mon.BpfModule.TableId("sys_events"), mon.BpfModule)
 		mon.SyscallChannel = make(chan []byte, 8192)
 		mon.SyscallLostChannel = make(chan uint64)
 
-		mon.SyscallPerfMap, err = bcc.InitPerfMapWithPageCnt(eventsTable, mon.SyscallChannel, mon.SyscallLostChannel, 64)
+		mon.SyscallPerfMap, err = bcc.InitPerfMapWithPageCnt(eventsTable, mon.SyscallChannel, mon.SyscallLostChannel, mon.PerfPageCount)
 		if err != nil {
 			return fmt.Errorf("error initializing events perf map: %v", err)
 		}
@@ -350,6 +448,7 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kprobe %s: %v", syscallName, err)
 			}
+			mon.HostAttachedProbes++
 			kp, err = mon.HostBpfModule.LoadKprobe(fmt.Sprintf("trace_ret_%s", syscallName))
 			if err != nil {
 				return fmt.Errorf("error loading kprobe %s: %v", syscallName, err)
@@ -358,6 +457,7 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kretprobe %s: %v", syscallName, err)
 			}
+			mon.HostAttachedProbes++
 		}
 
 		tracepoints := []string{"do_exit"}
@@ -371,13 +471,14 @@ func (mon *SystemMonitor) InitBPF() error {
 			if err != nil {
 				return fmt.Errorf("error attaching kprobe %s: %v", tracepoint, err)
 			}
+			mon.HostAttachedProbes++
 		}
 
 		hostEventsTable := bcc.NewTable(mon.HostBpfModule.TableId("sys_events"), mon.HostBpfModule)
 		mon.HostSyscallChannel = make(chan []byte, 8192)
 		mon.HostSyscallLostChannel = make(chan uint64)
 
-		mon.HostSyscallPerfMap, err = bcc.InitPerfMapWithPageCnt(hostEventsTable, mon.HostSyscallChannel, mon.HostSyscallLostChannel, 64)
+		mon.HostSyscallPerfMap, err = bcc.InitPerfMapWithPageCnt(hostEventsTable, mon.HostSyscallChannel, mon.HostSyscallLostChannel, mon.PerfPageCount)
 		if err != nil {
 			return fmt.Errorf("error initializing events perf map: %v", err)
 		}
@@ -386,8 +487,67 @@ func (mon *SystemMonitor) InitBPF() error {
 	return nil
 }
 
+// InitBPFObjects Function
+func (mon *SystemMonitor) InitBPFObjects(homeDir, sysPrefix string, systemCalls []string) error {
+	perCPUBuffer := mon.PerfPageCount * os.Getpagesize()
+
+	mon.Logger.Print("Initializing CO-RE eBPF objects")
+
+	if mon.EnableKubeArmorPolicy {
+		objPath, err := GetBPFObjectPath(homeDir, BPFObjectName)
+		if err != nil {
+			return err
+		}
+
+		mon.BpfObject, err = LoadBPFObject(objPath, sysPrefix, systemCalls, perCPUBuffer)
+		if err != nil {
+			return err
+		}
+
+		mon.AttachedProbes = len(mon.BpfObject.Links)
+		mon.SyscallChannel = mon.BpfObject.EventChan
+	}
+
+	if mon.EnableKubeArmorHostPolicy {
+		objPath, err := GetBPFObjectPath(homeDir, HostBPFObjectName)
+		if err != nil {
+			return err
+		}
+
+		mon.HostBpfObject, err = LoadBPFObject(objPath, sysPrefix, systemCalls, perCPUBuffer)
+		if err != nil {
+			return err
+		}
+
+		mon.HostAttachedProbes = len(mon.HostBpfObject.Links)
+		mon.HostSyscallChannel = mon.HostBpfObject.EventChan
+	}
+
+	mon.Logger.Print("Initialized CO-RE eBPF objects")
+
+	return nil
+}
+
+// DestroyBPFObjects Function
+func (mon *SystemMonitor) DestroyBPFObjects() {
+	if mon.BpfObject != nil {
+		mon.BpfObject.Close()
+		mon.BpfObject = nil
+	}
+
+	if mon.HostBpfObject != nil {
+		mon.HostBpfObject.Close()
+		mon.HostBpfObject = nil
+	}
+
+	mon.AttachedProbes = 0
+	mon.HostAttachedProbes = 0
+}
+
 // DestroySystemMonitor Function
 func (mon *SystemMonitor) DestroySystemMonitor() error {
+	mon.DestroyBPFObjects()
+
 	if mon.SyscallPerfMap != nil {
 		mon.SyscallPerfMap.Stop()
 	}
@@ -396,8 +556,8 @@ func (mon *SystemMonitor) DestroySystemMonitor() error {
 		mon.BpfModule.Close()
 	}
 
-	if mon.ContextChan != nil {
-		close(mon.ContextChan)
+	if mon.LogPipeline != nil {
+		mon.LogPipeline.Stop()
 	}
 
 	if mon.HostSyscallPerfMap != nil {
@@ -408,8 +568,8 @@ func (mon *SystemMonitor) DestroySystemMonitor() error {
 		mon.HostBpfModule.Close()
 	}
 
-	if mon.HostContextChan != nil {
-		close(mon.HostContextChan)
+	if mon.HostLogPipeline != nil {
+		mon.HostLogPipeline.Stop()
 	}
 
 	mon.Ticker.Stop()
@@ -417,18 +577,103 @@ func (mon *SystemMonitor) DestroySystemMonitor() error {
 	return nil
 }
 
+// StartLogPipelines Function
+func (mon *SystemMonitor) StartLogPipelines() {
+	if mon.EnableKubeArmorPolicy {
+		mon.LogPipeline = NewLogPipeline("container", mon.LogWorkers, mon.LogWorkerQueueSize, mon.LogBackpressure, mon.UpdateLog)
+		mon.LogPipeline.Start()
+	}
+
+	if mon.EnableKubeArmorHostPolicy {
+		mon.HostLogPipeline = NewLogPipeline("host", mon.LogWorkers, mon.LogWorkerQueueSize, mon.LogBackpressure, mon.UpdateHostLog)
+		mon.HostLogPipeline.Start()
+	}
+}
+
+// SetUntrackedNamespaces Function
+func (mon *SystemMonitor) SetUntrackedNamespaces(namespaces []string) {
+	mon.UntrackedNamespacesLock.Lock()
+	defer mon.UntrackedNamespacesLock.Unlock()
+
+	mon.UntrackedNamespaces = append([]string{}, namespaces...)
+}
+
+// IsUntrackedNamespace Function
+func (mon *SystemMonitor) IsUntrackedNamespace(namespace string) bool {
+	mon.UntrackedNamespacesLock.RLock()
+	defer mon.UntrackedNamespacesLock.RUnlock()
+
+	return kl.ContainsElement(mon.UntrackedNamespaces, namespace)
+}
+
+// GetHealthStatus Function
+func (mon *SystemMonitor) GetHealthStatus() tp.HealthStatus {
+	health := tp.HealthStatus{Name: "systemMonitor", Healthy: true, Details: map[string]string{}}
+
+	// skip if system monitor is not active
+	if mon == nil {
+		health.Message = "disabled"
+		return health
+	}
+
+	if mon.EnableKubeArmorPolicy {
+		health.Details["attachedProbes"] = strconv.Itoa(mon.AttachedProbes)
+
+		if mon.LogPipeline != nil {
+			health.Details["droppedLogs"] = strconv.FormatUint(mon.LogPipeline.Stats().Dropped, 10)
+		}
+
+		if mon.LostEvents != nil {
+			health.Details["lostEvents"] = strconv.FormatUint(mon.LostEvents.GetTotal("container"), 10)
+		}
+
+		if mon.AttachedProbes == 0 || (mon.SyscallPerfMap == nil && mon.BpfObject == nil) {
+			health.Healthy = false
+			health.Message = "no probes attached for containers"
+		}
+	}
+
+	if mon.EnableKubeArmorHostPolicy {
+		health.Details["hostAttachedProbes"] = strconv.Itoa(mon.HostAttachedProbes)
+
+		if mon.HostLogPipeline != nil {
+			health.Details["hostDroppedLogs"] = strconv.FormatUint(mon.HostLogPipeline.Stats().Dropped, 10)
+		}
+
+		if mon.LostEvents != nil {
+			health.Details["hostLostEvents"] = strconv.FormatUint(mon.LostEvents.GetTotal("host"), 10)
+		}
+
+		if mon.HostAttachedProbes == 0 || (mon.HostSyscallPerfMap == nil && mon.HostBpfObject == nil) {
+			health.Healthy = false
+			health.Message = "no probes attached for a host"
+		}
+	}
+
+	return health
+}
+
 // ======================= //
 // == System Call Trace == //
 // ======================= //
 
 // TraceSyscall Function
 func (mon *SystemMonitor) TraceSyscall() {
-	if mon.SyscallPerfMap != nil {
+	// lost samples with CPUs (CO-RE only)
+	var lostSamples chan LostSample
+
+	if mon.BpfObject != nil {
+		lostSamples = mon.BpfObject.LostChan
+		mon.BpfObject.Start()
+	} else if mon.SyscallPerfMap != nil {
 		mon.SyscallPerfMap.Start()
 	} else {
 		return
 	}
 
+	// expose the backlog of events read from perf buffers
+	mt.RegisterQueue("perf/container", func() int { return len(mon.SyscallChannel) }, func() int { return cap(mon.SyscallChannel) })
+
 	Containers := *(mon.Containers)
 	ContainersLock := *(mon.ContainersLock)
 
@@ -444,13 +689,7 @@ func (mon *SystemMonitor) TraceSyscall() {
 				continue
 			}
 
-			dataBuff := bytes.NewBuffer(dataRaw)
-			ctx, err := readContextFromBuff(dataBuff)
-			if err != nil {
-				continue
-			}
-
-			args, err := GetArgs(dataBuff, ctx.Argnum)
+			ctx, args, err := DecodeSyscallEvent(dataRaw)
 			if err != nil {
 				continue
 			}
@@ -463,11 +702,11 @@ func (mon *SystemMonitor) TraceSyscall() {
 				if containerID != "" {
 					ContainersLock.RLock()
 					namespace := Containers[containerID].NamespaceName
-					if kl.ContainsElement(mon.UntrackedNamespaces, namespace) {
-						ContainersLock.RUnlock()
+					ContainersLock.RUnlock()
+
+					if mon.IsUntrackedNamespace(namespace) {
 						continue
 					}
-					ContainersLock.RUnlock()
 				}
 			}
 
@@ -485,6 +724,11 @@ func (mon *SystemMonitor) TraceSyscall() {
 				}
 			} else if ctx.EventID == SysExecve {
 				if len(args) == 2 { // enter
+					// truncate and redact the arguments before they are stored or logged
+					if val, ok := args[1].([]string); ok {
+						args[1] = mon.FilterArgs(val)
+					}
+
 					// build a pid node
 					pidNode := mon.BuildPidNode(ctx, args[0].(string), args[1].([]string))
 					mon.AddActivePid(containerID, pidNode)
@@ -509,6 +753,8 @@ func (mon *SystemMonitor) TraceSyscall() {
 					log.Operation = "Process"
 					log.Data = "syscall=" + getSyscallName(int32(ctx.EventID))
 
+					log.Process = BuildProcessEvent(ctx, args[0].(string), args[1].([]string), 0, "")
+
 					// store the log in the map
 					execLogMap[ctx.HostPID] = log
 
@@ -531,15 +777,18 @@ func (mon *SystemMonitor) TraceSyscall() {
 						log.Result = "Passed"
 					}
 
-					// push the generated log
-					if mon.Logger != nil {
-						go mon.Logger.PushLog(log)
-					}
+					// push the generated log after the events preceding it
+					mon.LogPipeline.Submit(LogEvent{Key: containerID, Log: &log})
 				}
 
 				continue
 			} else if ctx.EventID == SysExecveAt {
 				if len(args) == 4 { // enter
+					// truncate and redact the arguments before they are stored or logged
+					if val, ok := args[2].([]string); ok {
+						args[2] = mon.FilterArgs(val)
+					}
+
 					// build a pid node
 					pidNode := mon.BuildPidNode(ctx, args[1].(string), args[2].([]string))
 					mon.AddActivePid(containerID, pidNode)
@@ -573,6 +822,9 @@ func (mon *SystemMonitor) TraceSyscall() {
 					log.Operation = "Process"
 					log.Data = "syscall=" + getSyscallName(int32(ctx.EventID)) + " fd=" + fd + " flag=" + procExecFlag
 
+					dirFD, _ := args[0].(int32)
+					log.Process = BuildProcessEvent(ctx, args[1].(string), args[2].([]string), dirFD, procExecFlag)
+
 					// store the log in the map
 					execLogMap[ctx.HostPID] = log
 
@@ -595,10 +847,8 @@ func (mon *SystemMonitor) TraceSyscall() {
 						log.Result = "Passed"
 					}
 
-					// push the generated log
-					if mon.Logger != nil {
-						go mon.Logger.PushLog(log)
-					}
+					// push the generated log after the events preceding it
+					mon.LogPipeline.Submit(LogEvent{Key: containerID, Log: &log})
 				}
 
 				continue
@@ -607,24 +857,36 @@ func (mon *SystemMonitor) TraceSyscall() {
 				continue
 			}
 
-			// push the context to the channel for logging
-			mon.ContextChan <- ContextCombined{ContainerID: containerID, ContextSys: ctx, ContextArgs: args}
+			// push the context to the pipeline for logging
+			mon.LogPipeline.Submit(LogEvent{Key: containerID, Context: ContextCombined{ContainerID: containerID, ContextSys: ctx, ContextArgs: args}})
+
+		case lost := <-lostSamples:
+			mon.LostEvents.Add("container", lost.CPU, lost.Count)
 
-		//nolint
-		case _ = <-mon.SyscallLostChannel:
-			continue
+		case lost := <-mon.SyscallLostChannel:
+			// BCC does not tell the CPU of lost events
+			mon.LostEvents.Add("container", UnknownCPU, lost)
 		}
 	}
 }
 
 // TraceHostSyscall Function
 func (mon *SystemMonitor) TraceHostSyscall() {
-	if mon.HostSyscallPerfMap != nil {
+	// lost samples with CPUs (CO-RE only)
+	var lostSamples chan LostSample
+
+	if mon.HostBpfObject != nil {
+		lostSamples = mon.HostBpfObject.LostChan
+		mon.HostBpfObject.Start()
+	} else if mon.HostSyscallPerfMap != nil {
 		mon.HostSyscallPerfMap.Start()
 	} else {
 		return
 	}
 
+	// expose the backlog of events read from perf buffers
+	mt.RegisterQueue("perf/host", func() int { return len(mon.HostSyscallChannel) }, func() int { return cap(mon.HostSyscallChannel) })
+
 	execLogMap := map[uint32]tp.Log{}
 
 	for {
@@ -637,13 +899,7 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 				continue
 			}
 
-			dataBuff := bytes.NewBuffer(dataRaw)
-			ctx, err := readContextFromBuff(dataBuff)
-			if err != nil {
-				continue
-			}
-
-			args, err := GetArgs(dataBuff, ctx.Argnum)
+			ctx, args, err := DecodeSyscallEvent(dataRaw)
 			if err != nil {
 				continue
 			}
@@ -658,6 +914,11 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 				}
 			} else if ctx.EventID == SysExecve {
 				if len(args) == 2 { // enter
+					// truncate and redact the arguments before they are stored or logged
+					if val, ok := args[1].([]string); ok {
+						args[1] = mon.FilterArgs(val)
+					}
+
 					// build a pid node
 					pidNode := mon.BuildPidNode(ctx, args[0].(string), args[1].([]string))
 					mon.AddActiveHostPid(ctx.HostPID, pidNode)
@@ -682,6 +943,8 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 					log.Operation = "Process"
 					log.Data = "syscall=" + getSyscallName(int32(ctx.EventID))
 
+					log.Process = BuildProcessEvent(ctx, args[0].(string), args[1].([]string), 0, "")
+
 					// store the log in the map
 					execLogMap[ctx.HostPID] = log
 
@@ -704,15 +967,18 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 						log.Result = "Passed"
 					}
 
-					// push the generated log
-					if mon.Logger != nil {
-						go mon.Logger.PushLog(log)
-					}
+					// push the generated log after the events preceding it
+					mon.HostLogPipeline.Submit(LogEvent{Key: GetHostLogKey(ctx), Log: &log})
 				}
 
 				continue
 			} else if ctx.EventID == SysExecveAt {
 				if len(args) == 4 { // enter
+					// truncate and redact the arguments before they are stored or logged
+					if val, ok := args[2].([]string); ok {
+						args[2] = mon.FilterArgs(val)
+					}
+
 					// build a pid node
 					pidNode := mon.BuildPidNode(ctx, args[1].(string), args[2].([]string))
 					mon.AddActiveHostPid(ctx.HostPID, pidNode)
@@ -746,6 +1012,9 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 					log.Operation = "Process"
 					log.Data = "syscall=" + getSyscallName(int32(ctx.EventID)) + " fd=" + fd + " flag=" + procExecFlag
 
+					dirFD, _ := args[0].(int32)
+					log.Process = BuildProcessEvent(ctx, args[1].(string), args[2].([]string), dirFD, procExecFlag)
+
 					// store the log in the map
 					execLogMap[ctx.HostPID] = log
 
@@ -768,10 +1037,8 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 						log.Result = "Passed"
 					}
 
-					// push the generated log
-					if mon.Logger != nil {
-						go mon.Logger.PushLog(log)
-					}
+					// push the generated log after the events preceding it
+					mon.HostLogPipeline.Submit(LogEvent{Key: GetHostLogKey(ctx), Log: &log})
 				}
 
 				continue
@@ -780,12 +1047,15 @@ func (mon *SystemMonitor) TraceHostSyscall() {
 				continue
 			}
 
-			// push the context to the channel for logging
-			mon.HostContextChan <- ContextCombined{ContainerID: "", ContextSys: ctx, ContextArgs: args}
+			// push the context to the pipeline for logging
+			mon.HostLogPipeline.Submit(LogEvent{Key: GetHostLogKey(ctx), Context: ContextCombined{ContainerID: "", ContextSys: ctx, ContextArgs: args}})
+
+		case lost := <-lostSamples:
+			mon.LostEvents.Add("host", lost.CPU, lost.Count)
 
-		//nolint
-		case _ = <-mon.SyscallLostChannel:
-			continue
+		case lost := <-mon.HostSyscallLostChannel:
+			// BCC does not tell the CPU of lost events
+			mon.LostEvents.Add("host", UnknownCPU, lost)
 		}
 	}
 }
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/iovisor/gobpf/bcc"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// Visibility Flags (the same as VIS_* in BPF/system_monitor.c)
+const (
+	VisibilityProcess      uint32 = 0x1
+	VisibilityFile         uint32 = 0x2
+	VisibilityNetwork      uint32 = 0x4
+	VisibilityCapabilities uint32 = 0x8
+	VisibilityUntracked    uint32 = 0x100
+)
+
+// VisibilityMapName is the name of the BPF map for visibility flags
+const VisibilityMapName = "visibility_map"
+
+// HostPidNS is the ID of the initial pid namespace (PROC_PID_INIT_INO in BPF/system_monitor.c)
+const HostPidNS uint32 = 0xEffffffc
+
+// ==================== //
+// == Visibility Map == //
+// ==================== //
+
+// getPolicyVisibilityFlags Function
+func getPolicyVisibilityFlags(file tp.FileType, network tp.NetworkType, capabilities tp.CapabilitiesType, syscalls tp.SyscallsType) uint32 {
+	flags := uint32(0)
+
+	if len(file.MatchPaths) > 0 || len(file.MatchDirectories) > 0 || len(file.MatchPatterns) > 0 {
+		flags |= VisibilityFile
+	}
+	if len(network.MatchProtocols) > 0 || len(capabilities.MatchCapabilities) > 0 {
+		flags |= VisibilityNetwork
+	}
+	if len(syscalls.MatchSyscalls) > 0 {
+		flags |= VisibilityProcess | VisibilityFile
+	}
+
+	return flags
+}
+
+// GetVisibilityFlags Function
+func GetVisibilityFlags(endPoint tp.EndPoint, untracked, discovered bool) uint32 {
+	if untracked {
+		return VisibilityUntracked
+	}
+
+	flags := uint32(0)
+
+	if endPoint.ProcessVisibilityEnabled {
+		flags |= VisibilityProcess
+	}
+	if endPoint.FileVisibilityEnabled {
+		flags |= VisibilityFile
+	}
+	if endPoint.NetworkVisibilityEnabled {
+		flags |= VisibilityNetwork
+	}
+	if endPoint.CapabilitiesVisibilityEnabled {
+		flags |= VisibilityCapabilities
+	}
+
+	// keep the events that security policies need to match even if they are invisible
+	for _, secPolicy := range endPoint.SecurityPolicies {
+		spec := secPolicy.Spec
+		flags |= getPolicyVisibilityFlags(spec.File, spec.Network, spec.Capabilities, spec.Syscalls)
+	}
+
+	// keep the events that discovery learns from
+	if discovered {
+		flags |= VisibilityProcess | VisibilityFile | VisibilityNetwork
+	}
+
+	return flags
+}
+
+// GetHostVisibilityFlags Function
+func GetHostVisibilityFlags(node tp.Node, secPolicies []tp.HostSecurityPolicy) uint32 {
+	flags := uint32(0)
+
+	if node.ProcessVisibilityEnabled {
+		flags |= VisibilityProcess
+	}
+	if node.FileVisibilityEnabled {
+		flags |= VisibilityFile
+	}
+	if node.NetworkVisibilityEnabled {
+		flags |= VisibilityNetwork
+	}
+	if node.CapabilitiesVisibilityEnabled {
+		flags |= VisibilityCapabilities
+	}
+
+	// keep the events that host security policies need to match even if they are invisible
+	for _, secPolicy := range secPolicies {
+		spec := secPolicy.Spec
+		flags |= getPolicyVisibilityFlags(spec.File, spec.Network, spec.Capabilities, spec.Syscalls)
+	}
+
+	return flags
+}
+
+// getHostMntNS Function
+func getHostMntNS() uint32 {
+	// e.g., mnt:[4026531840]
+	link, err := os.Readlink("/proc/1/ns/mnt")
+	if err != nil {
+		return 0
+	}
+
+	mntns, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "mnt:["), "]"), 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return uint32(mntns)
+}
+
+// encodeNsKey Function
+func (mon *SystemMonitor) encodeNsKey(key NsKey) []byte {
+	buff := new(bytes.Buffer)
+	_ = binary.Write(buff, mon.HostByteOrder, key)
+	return buff.Bytes()
+}
+
+// setVisibilityMap Function
+func (mon *SystemMonitor) setVisibilityMap(key NsKey, flags uint32, delete bool) error {
+	if mon.BpfObject != nil {
+		visibilityMap, ok := mon.BpfObject.Collection.Maps[VisibilityMapName]
+		if !ok {
+			return fmt.Errorf("%s is not found", VisibilityMapName)
+		}
+
+		if delete {
+			return visibilityMap.Delete(key)
+		}
+		return visibilityMap.Put(key, flags)
+	}
+
+	if mon.BpfModule != nil {
+		visibilityMap := bcc.NewTable(mon.BpfModule.TableId(VisibilityMapName), mon.BpfModule)
+
+		if delete {
+			return visibilityMap.Delete(mon.encodeNsKey(key))
+		}
+
+		leaf := make([]byte, 4)
+		mon.HostByteOrder.PutUint32(leaf, flags)
+
+		return visibilityMap.Set(mon.encodeNsKey(key), leaf)
+	}
+
+	return nil
+}
+
+// UpdateVisibilityMap Function
+func (mon *SystemMonitor) UpdateVisibilityMap(pidns, mntns, flags uint32) {
+	if pidns == 0 || mntns == 0 {
+		return
+	}
+
+	key := NsKey{PidNS: pidns, MntNS: mntns}
+
+	mon.VisibilityMapLock.Lock()
+	defer mon.VisibilityMapLock.Unlock()
+
+	if val, ok := mon.VisibilityMap[key]; ok && val == flags {
+		return
+	}
+
+	if err := mon.setVisibilityMap(key, flags, false); err != nil {
+		mon.Logger.Warnf("Failed to update the visibility of a container (%s)", err.Error())
+		return
+	}
+
+	mon.VisibilityMap[key] = flags
+}
+
+// UpdateHostVisibility Function
+func (mon *SystemMonitor) UpdateHostVisibility(flags uint32) {
+	// processes in other mount namespaces (e.g., systemd services) stay fully visible
+	mon.UpdateVisibilityMap(HostPidNS, mon.HostMntNS, flags)
+}
+
+// DeleteVisibilityMap Function
+func (mon *SystemMonitor) DeleteVisibilityMap(pidns, mntns uint32) {
+	key := NsKey{PidNS: pidns, MntNS: mntns}
+
+	mon.VisibilityMapLock.Lock()
+	defer mon.VisibilityMapLock.Unlock()
+
+	if _, ok := mon.VisibilityMap[key]; !ok {
+		return
+	}
+
+	if err := mon.setVisibilityMap(key, 0, true); err != nil {
+		mon.Logger.Warnf("Failed to delete the visibility of a container (%s)", err.Error())
+	}
+
+	delete(mon.VisibilityMap, key)
+}
+
+// GetVisibilityMap Function
+func (mon *SystemMonitor) GetVisibilityMap(pidns, mntns uint32) (uint32, bool) {
+	mon.VisibilityMapLock.RLock()
+	defer mon.VisibilityMapLock.RUnlock()
+
+	flags, ok := mon.VisibilityMap[NsKey{PidNS: pidns, MntNS: mntns}]
+	return flags, ok
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package monitor
+
+import (
+	"testing"
+
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+func TestGetVisibilityFlags(t *testing.T) {
+	endPoint := tp.EndPoint{NamespaceName: "multiubuntu", ProcessVisibilityEnabled: true}
+
+	if flags := GetVisibilityFlags(endPoint, false, false); flags != VisibilityProcess {
+		t.Errorf("[FAIL] Got unexpected flags for process visibility (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Got the flags from visibility")
+
+	// file events are still needed for file policies
+
+	secPolicy := tp.SecurityPolicy{}
+	secPolicy.Spec.File.MatchPaths = []tp.FilePathType{{Path: "/etc/passwd", Action: "Audit"}}
+	endPoint.SecurityPolicies = []tp.SecurityPolicy{secPolicy}
+
+	if flags := GetVisibilityFlags(endPoint, false, false); flags != VisibilityProcess|VisibilityFile {
+		t.Errorf("[FAIL] Got unexpected flags for a file policy (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Kept file events for a file policy")
+
+	// discovery needs process, file, and network events
+
+	if flags := GetVisibilityFlags(endPoint, false, true); flags != VisibilityProcess|VisibilityFile|VisibilityNetwork {
+		t.Errorf("[FAIL] Got unexpected flags for a discovery target (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Kept events for a discovery target")
+
+	if flags := GetVisibilityFlags(endPoint, true, false); flags != VisibilityUntracked {
+		t.Errorf("[FAIL] Got unexpected flags for an untracked namespace (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Got the flags for an untracked namespace")
+}
+
+func TestVisibilityMap(t *testing.T) {
+	mon := newTestSystemMonitor()
+
+	mon.UpdateVisibilityMap(4026532000, 4026532001, VisibilityProcess|VisibilityNetwork)
+	mon.UpdateVisibilityMap(0, 0, VisibilityProcess)
+
+	if flags, ok := mon.GetVisibilityMap(4026532000, 4026532001); !ok || flags != VisibilityProcess|VisibilityNetwork {
+		t.Errorf("[FAIL] Failed to add the visibility of a container (%x)", flags)
+		return
+	}
+
+	if len(mon.VisibilityMap) != 1 {
+		t.Errorf("[FAIL] Added the visibility of a container without namespaces")
+		return
+	}
+	t.Log("[PASS] Added the visibility of a container")
+
+	mon.UpdateVisibilityMap(4026532000, 4026532001, VisibilityUntracked)
+
+	if flags, _ := mon.GetVisibilityMap(4026532000, 4026532001); flags != VisibilityUntracked {
+		t.Errorf("[FAIL] Failed to update the visibility of a container (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Updated the visibility of a container")
+
+	mon.DeleteVisibilityMap(4026532000, 4026532001)
+
+	if _, ok := mon.GetVisibilityMap(4026532000, 4026532001); ok {
+		t.Errorf("[FAIL] Failed to delete the visibility of a container")
+		return
+	}
+	t.Log("[PASS] Deleted the visibility of a container")
+}
+
+func TestHostVisibility(t *testing.T) {
+	node := tp.Node{NodeName: "nodeName", NetworkVisibilityEnabled: true}
+
+	if flags := GetHostVisibilityFlags(node, nil); flags != VisibilityNetwork {
+		t.Errorf("[FAIL] Got unexpected flags for network visibility (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Got the flags from host visibility")
+
+	secPolicy := tp.HostSecurityPolicy{}
+	secPolicy.Spec.Syscalls.MatchSyscalls = []tp.SyscallMatchType{{Syscalls: []string{"unshare"}, Action: "Audit"}}
+
+	flags := GetHostVisibilityFlags(node, []tp.HostSecurityPolicy{secPolicy})
+	if flags != VisibilityProcess|VisibilityFile|VisibilityNetwork {
+		t.Errorf("[FAIL] Got unexpected flags for a host syscall policy (%x)", flags)
+		return
+	}
+	t.Log("[PASS] Kept syscall events for a host syscall policy")
+
+	mon := newTestSystemMonitor()
+	mon.HostMntNS = 4026531840
+
+	mon.UpdateHostVisibility(flags)
+
+	if val, ok := mon.GetVisibilityMap(HostPidNS, 4026531840); !ok || val != flags {
+		t.Errorf("[FAIL] Failed to update the visibility of the host (%x)", val)
+		return
+	}
+	t.Log("[PASS] Updated the visibility of the host")
+}
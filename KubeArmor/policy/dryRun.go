@@ -0,0 +1,446 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	fd "github.com/kubearmor/KubeArmor/KubeArmor/feeder"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ===================== //
+// == Const. Vaiables == //
+// ===================== //
+
+// MaxTraceLineSize is the longest log line to read from a trace
+const MaxTraceLineSize = 1024 * 1024
+
+// yaml document separator
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// ============== //
+// == Policies == //
+// ============== //
+
+// ParseKubeArmorPolicies Function
+func ParseKubeArmorPolicies(data []byte) ([]tp.SecurityPolicy, error) {
+	secPolicies := []tp.SecurityPolicy{}
+
+	for _, doc := range documentSeparator.Split(string(data), -1) {
+		if len(bytes.TrimSpace([]byte(doc))) == 0 {
+			continue
+		}
+
+		header := struct {
+			Kind string `json:"kind"`
+		}{}
+
+		if err := yaml.Unmarshal([]byte(doc), &header); err != nil {
+			return nil, err
+		}
+
+		// comments only
+		if header.Kind == "" {
+			continue
+		}
+
+		if header.Kind != "KubeArmorPolicy" {
+			return nil, fmt.Errorf("unsupported kind %q", header.Kind)
+		}
+
+		policy := tp.K8sKubeArmorPolicy{}
+		if err := yaml.Unmarshal([]byte(doc), &policy); err != nil {
+			return nil, err
+		}
+
+		// same as kubectl without a namespace
+		if policy.Metadata.Namespace == "" {
+			policy.Metadata.Namespace = "default"
+		}
+
+		secPolicy, err := ConvertKubeArmorPolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+
+		secPolicies = append(secPolicies, secPolicy)
+	}
+
+	return secPolicies, nil
+}
+
+// LoadKubeArmorPolicies Function
+func LoadKubeArmorPolicies(paths []string) ([]tp.SecurityPolicy, error) {
+	secPolicies := []tp.SecurityPolicy{}
+
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+
+		policies, err := ParseKubeArmorPolicies(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+
+		secPolicies = append(secPolicies, policies...)
+	}
+
+	return secPolicies, nil
+}
+
+// ParsePodLabels Function
+func ParsePodLabels(data []byte) (map[string]map[string]string, error) {
+	// the output of 'kubectl get pods -A -o yaml' (or json)
+	pods := v1.PodList{}
+	if err := yaml.Unmarshal(data, &pods); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]map[string]string{}
+
+	for _, pod := range pods.Items {
+		labels[pod.Namespace+"/"+pod.Name] = pod.Labels
+	}
+
+	return labels, nil
+}
+
+// ============= //
+// == Dry Run == //
+// ============= //
+
+// DryRunRule Structure
+type DryRunRule struct {
+	PolicyName   string `json:"policyName"`
+	Operation    string `json:"operation,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	Resource     string `json:"resource,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Action       string `json:"action"`
+
+	Hits int `json:"hits"`
+}
+
+// DryRunReport Structure
+type DryRunReport struct {
+	// number of replayed logs
+	Events int `json:"events"`
+
+	// host logs, not covered by KubeArmorPolicy
+	Skipped int `json:"skipped"`
+
+	// pods without labels (assumed to be selected by every policy in their namespaces)
+	UnlabeledPods []string `json:"unlabeledPods,omitempty"`
+
+	// rules of the given policies
+	Rules []DryRunRule `json:"rules"`
+
+	// logs that would become alerts
+	Alerts []tp.Log `json:"alerts"`
+}
+
+// DryRunOptions Structure
+type DryRunOptions struct {
+	// namespace/pod -> labels (policies apply to all pods in their namespaces if unknown)
+	PodLabels map[string]map[string]string
+
+	// host name for logs in the trace
+	NodeName string
+}
+
+// getRuleKey Function
+func getRuleKey(match tp.MatchPolicy) string {
+	return strings.Join([]string{match.PolicyName, match.Operation, match.ResourceType, match.Resource, match.Source, match.Action}, "|")
+}
+
+// resetMatchedPolicy Function
+func resetMatchedPolicy(log tp.Log) tp.Log {
+	log.PolicyName = ""
+	log.Severity = ""
+	log.Tags = ""
+	log.Message = ""
+	log.Type = ""
+	log.Action = ""
+
+	// alerts only
+	log.ProcessVisibilityEnabled = false
+	log.FileVisibilityEnabled = false
+	log.NetworkVisibilityEnabled = false
+	log.CapabilitiesVisibilityEnabled = false
+
+	return log
+}
+
+// isAlert Function
+func isAlert(log tp.Log) bool {
+	return log.Type == "MatchedPolicy" || log.Type == "MatchedNativePolicy"
+}
+
+// DryRun Structure
+type DryRun struct {
+	Options DryRunOptions
+
+	// security policies to simulate
+	SecurityPolicies []tp.SecurityPolicy
+
+	// matcher for all rules and for a single rule
+	Matcher     *fd.Feeder
+	RuleMatcher *fd.Feeder
+
+	// namespace name + pod name -> endpoint
+	EndPoints map[string]tp.EndPoint
+
+	Report DryRunReport
+
+	// rule key -> index in the report
+	ruleIndex map[string]int
+}
+
+// NewDryRun Function
+func NewDryRun(secPolicies []tp.SecurityPolicy, options DryRunOptions) *DryRun {
+	dr := &DryRun{}
+
+	dr.Options = options
+	dr.SecurityPolicies = secPolicies
+
+	node := &tp.Node{NodeName: options.NodeName}
+
+	dr.Matcher = fd.NewPolicyMatcher(node)
+	dr.RuleMatcher = fd.NewPolicyMatcher(node)
+
+	dr.EndPoints = map[string]tp.EndPoint{}
+
+	dr.Report = DryRunReport{Rules: []DryRunRule{}, Alerts: []tp.Log{}}
+	dr.ruleIndex = map[string]int{}
+
+	// list all rules in advance to report the ones without hits
+	for _, secPolicy := range secPolicies {
+		endPoint := tp.EndPoint{
+			NamespaceName:    secPolicy.Metadata["namespaceName"],
+			EndPointName:     "",
+			PolicyEnabled:    tp.KubeArmorPolicyEnabled,
+			SecurityPolicies: []tp.SecurityPolicy{secPolicy},
+		}
+
+		dr.RuleMatcher.UpdateSecurityPolicies("ADDED", endPoint)
+		for _, match := range dr.RuleMatcher.SecurityPolicies[endPoint.NamespaceName+"_"].Policies {
+			dr.addRule(match)
+		}
+		dr.RuleMatcher.UpdateSecurityPolicies("DELETED", endPoint)
+	}
+
+	return dr
+}
+
+// addRule Function
+func (dr *DryRun) addRule(match tp.MatchPolicy) int {
+	key := getRuleKey(match)
+
+	if idx, ok := dr.ruleIndex[key]; ok {
+		return idx
+	}
+
+	dr.Report.Rules = append(dr.Report.Rules, DryRunRule{
+		PolicyName:   match.PolicyName,
+		Operation:    match.Operation,
+		ResourceType: match.ResourceType,
+		Resource:     match.Resource,
+		Source:       match.Source,
+		Action:       match.Action,
+	})
+
+	dr.ruleIndex[key] = len(dr.Report.Rules) - 1
+	return dr.ruleIndex[key]
+}
+
+// getEndPoint Function
+func (dr *DryRun) getEndPoint(log tp.Log) tp.EndPoint {
+	key := log.NamespaceName + "_" + log.PodName
+
+	if endPoint, ok := dr.EndPoints[key]; ok {
+		return endPoint
+	}
+
+	endPoint := tp.EndPoint{
+		NamespaceName: log.NamespaceName,
+		EndPointName:  log.PodName,
+		Identities:    []string{"namespaceName=" + log.NamespaceName},
+		PolicyEnabled: tp.KubeArmorPolicyEnabled,
+	}
+
+	// keep the audit mode in the trace
+	if log.PolicyEnabled == tp.KubeArmorPolicyAudited {
+		endPoint.PolicyEnabled = tp.KubeArmorPolicyAudited
+	}
+
+	labels, known := dr.Options.PodLabels[log.NamespaceName+"/"+log.PodName]
+	if !known {
+		dr.Report.UnlabeledPods = append(dr.Report.UnlabeledPods, log.NamespaceName+"/"+log.PodName)
+	}
+
+	for k, v := range labels {
+		endPoint.Identities = append(endPoint.Identities, k+"="+v)
+	}
+
+	sort.Strings(endPoint.Identities)
+
+	for _, secPolicy := range dr.SecurityPolicies {
+		if secPolicy.Metadata["namespaceName"] != log.NamespaceName {
+			continue
+		}
+
+		// without labels, assume that the policy selects the pod
+		if !known || kl.MatchIdentities(secPolicy.Spec.Selector.Identities, endPoint.Identities) {
+			endPoint.SecurityPolicies = append(endPoint.SecurityPolicies, secPolicy)
+		}
+	}
+
+	dr.Matcher.UpdateSecurityPolicies("ADDED", endPoint)
+	dr.EndPoints[key] = endPoint
+
+	return endPoint
+}
+
+// ReplayLog Function
+func (dr *DryRun) ReplayLog(log tp.Log) {
+	dr.Report.Events++
+
+	// host logs
+	if log.ContainerID == "" || log.NamespaceName == "" || log.PodName == "" {
+		dr.Report.Skipped++
+		return
+	}
+
+	endPoint := dr.getEndPoint(log)
+	if len(endPoint.SecurityPolicies) == 0 {
+		return
+	}
+
+	log = resetMatchedPolicy(log)
+	log.PolicyEnabled = endPoint.PolicyEnabled
+
+	alert := dr.Matcher.UpdateMatchedPolicy(log)
+
+	// logs in the trace passed without the policies, so the default deny of allow policies is evaluated in the audit mode
+	if !isAlert(alert) && log.PolicyEnabled == tp.KubeArmorPolicyEnabled && log.Result == "Passed" {
+		log.PolicyEnabled = tp.KubeArmorPolicyAudited
+
+		if audit := dr.Matcher.UpdateMatchedPolicy(log); isAlert(audit) && audit.Action == "Audit (Allow)" {
+			// the same as the alert of the denied access
+			alert = audit
+			alert.PolicyEnabled = tp.KubeArmorPolicyEnabled
+			alert.Action = "Allow"
+			alert.Result = "Permission denied"
+		}
+	}
+
+	if !isAlert(alert) {
+		return
+	}
+
+	dr.Report.Alerts = append(dr.Report.Alerts, alert)
+
+	// find the rules leading to the alert
+	key := log.NamespaceName + "_" + log.PodName
+	policyNames := strings.Split(alert.PolicyName, ",")
+
+	for _, match := range dr.Matcher.SecurityPolicies[key].Policies {
+		if alert.Type == "MatchedNativePolicy" {
+			if !match.Native {
+				continue
+			}
+		} else if !kl.ContainsElement(policyNames, match.PolicyName) {
+			continue
+		}
+
+		dr.RuleMatcher.SecurityPolicies[key] = tp.MatchPolicies{Policies: []tp.MatchPolicy{match}}
+
+		if result := dr.RuleMatcher.UpdateMatchedPolicy(log); result.Type == alert.Type {
+			dr.Report.Rules[dr.addRule(match)].Hits++
+		}
+	}
+
+	delete(dr.RuleMatcher.SecurityPolicies, key)
+}
+
+// ReplayTrace Function
+func (dr *DryRun) ReplayTrace(trace io.Reader) error {
+	scanner := bufio.NewScanner(trace)
+	scanner.Buffer(make([]byte, 64*1024), MaxTraceLineSize)
+
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		log := tp.Log{}
+		if err := json.Unmarshal(data, &log); err != nil {
+			return fmt.Errorf("invalid log at line %d (%s)", line, err.Error())
+		}
+
+		dr.ReplayLog(log)
+	}
+
+	return scanner.Err()
+}
+
+// RunDryRun Function
+func RunDryRun(secPolicies []tp.SecurityPolicy, trace io.Reader, options DryRunOptions) (DryRunReport, error) {
+	dr := NewDryRun(secPolicies, options)
+
+	if err := dr.ReplayTrace(trace); err != nil {
+		return dr.Report, err
+	}
+
+	return dr.Report, nil
+}
+
+// PrintReport Function
+func PrintReport(w io.Writer, report DryRunReport) {
+	fmt.Fprintf(w, "Replayed %d logs (%d host logs skipped), %d alerts\n\n", report.Events, report.Skipped, len(report.Alerts))
+
+	if len(report.UnlabeledPods) > 0 {
+		fmt.Fprintf(w, "No labels for %d pods (%s), assumed that every policy in their namespaces selects them (use -pods)\n\n", len(report.UnlabeledPods), strings.Join(report.UnlabeledPods, ", "))
+	}
+
+	fmt.Fprintf(w, "%-6s %-32s %-10s %-20s %s\n", "HITS", "POLICY", "OPERATION", "ACTION", "RESOURCE")
+	for _, rule := range report.Rules {
+		resource := rule.Resource
+		if rule.Source != "" {
+			resource = resource + " (fromSource: " + rule.Source + ")"
+		}
+
+		fmt.Fprintf(w, "%-6d %-32s %-10s %-20s %s\n", rule.Hits, rule.PolicyName, rule.Operation, rule.Action, resource)
+	}
+
+	if len(report.Alerts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%-24s %-32s %-20s %-10s %s\n", "POD", "POLICY", "ACTION", "OPERATION", "RESOURCE")
+	for _, alert := range report.Alerts {
+		fmt.Fprintf(w, "%-24s %-32s %-20s %-10s %s\n", alert.NamespaceName+"/"+alert.PodName, alert.PolicyName, alert.Action, alert.Operation, alert.Resource)
+	}
+}
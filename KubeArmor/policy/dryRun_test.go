@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+var testPolicies = `
+apiVersion: security.kubearmor.com/v1
+kind: KubeArmorPolicy
+metadata:
+  name: ksp-group-1-proc-path-block
+  namespace: multiubuntu
+spec:
+  severity: 5
+  selector:
+    matchLabels:
+      group: group-1
+  process:
+    matchPaths:
+    - path: /bin/sleep
+  action:
+    Block
+---
+apiVersion: security.kubearmor.com/v1
+kind: KubeArmorPolicy
+metadata:
+  name: ksp-group-1-file-dir-audit
+  namespace: multiubuntu
+spec:
+  selector:
+    matchLabels:
+      group: group-1
+  file:
+    matchDirectories:
+    - dir: /credentials/
+      recursive: true
+  action:
+    Audit
+`
+
+var testTrace = `
+{"timestamp":1,"updatedTime":"2021-01-01T00:00:00.000000Z","hostName":"node","namespaceName":"multiubuntu","podName":"ubuntu-1","containerID":"abc","hostPid":10,"ppid":1,"pid":10,"uid":0,"type":"ContainerLog","source":"/bin/bash","operation":"Process","resource":"/bin/sleep 1","result":"Passed"}
+{"timestamp":2,"updatedTime":"2021-01-01T00:00:01.000000Z","hostName":"node","namespaceName":"multiubuntu","podName":"ubuntu-1","containerID":"abc","hostPid":11,"ppid":1,"pid":11,"uid":0,"type":"ContainerLog","source":"/bin/bash","operation":"Process","resource":"/bin/ls","result":"Passed"}
+{"timestamp":3,"updatedTime":"2021-01-01T00:00:02.000000Z","hostName":"node","namespaceName":"multiubuntu","podName":"ubuntu-3","containerID":"def","hostPid":12,"ppid":1,"pid":12,"uid":0,"type":"ContainerLog","source":"/bin/bash","operation":"Process","resource":"/bin/sleep 1","result":"Passed"}
+
+{"timestamp":4,"updatedTime":"2021-01-01T00:00:03.000000Z","hostName":"node","hostPid":13,"ppid":1,"pid":13,"uid":0,"type":"HostLog","source":"/bin/bash","operation":"Process","resource":"/bin/sleep 1","result":"Passed"}
+`
+
+var testPods = `
+apiVersion: v1
+kind: List
+items:
+- metadata:
+    name: ubuntu-1
+    namespace: multiubuntu
+    labels:
+      group: group-1
+- metadata:
+    name: ubuntu-3
+    namespace: multiubuntu
+    labels:
+      group: group-2
+`
+
+func TestParseKubeArmorPolicies(t *testing.T) {
+	secPolicies, err := ParseKubeArmorPolicies([]byte(testPolicies))
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse policies (%s)", err.Error())
+		return
+	}
+
+	if len(secPolicies) != 2 {
+		t.Errorf("[FAIL] Got %d policies instead of 2", len(secPolicies))
+		return
+	}
+
+	secPolicy := secPolicies[1]
+
+	if secPolicy.Spec.Severity != 1 || secPolicy.Spec.File.MatchDirectories[0].Action != "Audit" {
+		t.Errorf("[FAIL] Failed to set default values (%+v)", secPolicy.Spec)
+		return
+	}
+
+	if strings.Join(secPolicy.Spec.Selector.Identities, ",") != "group=group-1,namespaceName=multiubuntu" {
+		t.Errorf("[FAIL] Got unexpected identities (%v)", secPolicy.Spec.Selector.Identities)
+		return
+	}
+	t.Log("[PASS] Parsed policies")
+
+	if _, err := ParseKubeArmorPolicies([]byte("kind: KubeArmorHostPolicy\n")); err == nil {
+		t.Errorf("[FAIL] Accepted an unsupported kind")
+		return
+	}
+	t.Log("[PASS] Rejected an unsupported kind")
+}
+
+func TestDryRun(t *testing.T) {
+	secPolicies, err := ParseKubeArmorPolicies([]byte(testPolicies))
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse policies (%s)", err.Error())
+		return
+	}
+
+	// without labels, the policies apply to all pods in the namespace
+
+	report, err := RunDryRun(secPolicies, strings.NewReader(testTrace), DryRunOptions{})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to replay the trace (%s)", err.Error())
+		return
+	}
+
+	if report.Events != 4 || report.Skipped != 1 || len(report.Alerts) != 2 {
+		t.Errorf("[FAIL] Got unexpected results (events=%d, skipped=%d, alerts=%d)", report.Events, report.Skipped, len(report.Alerts))
+		return
+	}
+
+	if len(report.Rules) != 2 || report.Rules[0].Hits != 2 || report.Rules[1].Hits != 0 {
+		t.Errorf("[FAIL] Got unexpected hits (%+v)", report.Rules)
+		return
+	}
+
+	if strings.Join(report.UnlabeledPods, ",") != "multiubuntu/ubuntu-1,multiubuntu/ubuntu-3" {
+		t.Errorf("[FAIL] Got unexpected pods without labels (%v)", report.UnlabeledPods)
+		return
+	}
+
+	alert := report.Alerts[0]
+	if alert.Type != "MatchedPolicy" || alert.PolicyName != "ksp-group-1-proc-path-block" || alert.Action != "Block" || alert.Severity != "5" {
+		t.Errorf("[FAIL] Got an unexpected alert (%+v)", alert)
+		return
+	}
+	t.Log("[PASS] Replayed the trace")
+
+	// with labels, only the selected pods
+
+	labels, err := ParsePodLabels([]byte(testPods))
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse pods (%s)", err.Error())
+		return
+	}
+
+	report, err = RunDryRun(secPolicies, strings.NewReader(testTrace), DryRunOptions{PodLabels: labels})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to replay the trace (%s)", err.Error())
+		return
+	}
+
+	if len(report.Alerts) != 1 || report.Alerts[0].PodName != "ubuntu-1" || report.Rules[0].Hits != 1 {
+		t.Errorf("[FAIL] Failed to apply label selectors (alerts=%d, rules=%+v)", len(report.Alerts), report.Rules)
+		return
+	}
+	t.Log("[PASS] Applied label selectors")
+
+	if _, err := RunDryRun(secPolicies, strings.NewReader("{invalid\n"), DryRunOptions{}); err == nil {
+		t.Errorf("[FAIL] Accepted an invalid trace")
+		return
+	}
+	t.Log("[PASS] Rejected an invalid trace")
+}
+
+var testAllowPolicy = `
+apiVersion: security.kubearmor.com/v1
+kind: KubeArmorPolicy
+metadata:
+  name: ksp-ubuntu-1-proc-path-allow
+  namespace: multiubuntu
+spec:
+  selector:
+    matchLabels:
+      group: group-1
+  process:
+    matchPaths:
+    - path: /bin/ls
+  action:
+    Allow
+`
+
+func TestDryRunAllowPolicy(t *testing.T) {
+	secPolicies, err := ParseKubeArmorPolicies([]byte(testAllowPolicy))
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse policies (%s)", err.Error())
+		return
+	}
+
+	labels, err := ParsePodLabels([]byte(testPods))
+	if err != nil {
+		t.Errorf("[FAIL] Failed to parse pods (%s)", err.Error())
+		return
+	}
+
+	report, err := RunDryRun(secPolicies, strings.NewReader(testTrace), DryRunOptions{PodLabels: labels})
+	if err != nil {
+		t.Errorf("[FAIL] Failed to replay the trace (%s)", err.Error())
+		return
+	}
+
+	// /bin/sleep in ubuntu-1 is denied by default, /bin/ls is allowed, and ubuntu-3 is not selected
+	if len(report.Alerts) != 1 || len(report.UnlabeledPods) != 0 {
+		t.Errorf("[FAIL] Got unexpected alerts (%+v)", report.Alerts)
+		return
+	}
+
+	alert := report.Alerts[0]
+	if alert.PodName != "ubuntu-1" || alert.Resource != "/bin/sleep 1" || alert.PolicyName != "ksp-ubuntu-1-proc-path-allow" ||
+		alert.Action != "Allow" || alert.Result != "Permission denied" {
+		t.Errorf("[FAIL] Got an unexpected alert (%+v)", alert)
+		return
+	}
+
+	if len(report.Rules) != 1 || report.Rules[0].Hits != 1 {
+		t.Errorf("[FAIL] Got unexpected hits (%+v)", report.Rules)
+		return
+	}
+	t.Log("[PASS] Denied the logs not allowed by an allow policy")
+}
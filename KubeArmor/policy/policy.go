@@ -0,0 +1,426 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2021 Authors of KubeArmor
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+
+	kl "github.com/kubearmor/KubeArmor/KubeArmor/common"
+	tp "github.com/kubearmor/KubeArmor/KubeArmor/types"
+)
+
+// ======================= //
+// == Security Policies == //
+// ======================= //
+
+// ConvertKubeArmorPolicy Function
+func ConvertKubeArmorPolicy(policy tp.K8sKubeArmorPolicy) (tp.SecurityPolicy, error) {
+	secPolicy := tp.SecurityPolicy{}
+
+	secPolicy.Metadata = map[string]string{}
+	secPolicy.Metadata["namespaceName"] = policy.Metadata.Namespace
+	secPolicy.Metadata["policyName"] = policy.Metadata.Name
+
+	if err := kl.Clone(policy.Spec, &secPolicy.Spec); err != nil {
+		return secPolicy, fmt.Errorf("failed to clone a spec (%s)", err.Error())
+	}
+
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Network.MatchProtocols)
+	kl.ObjCommaExpandFirstDupOthers(&secPolicy.Spec.Capabilities.MatchCapabilities)
+
+	if secPolicy.Spec.Severity == 0 {
+		secPolicy.Spec.Severity = 1 // the lowest severity, by default
+	}
+
+	switch secPolicy.Spec.Action {
+	case "allow":
+		secPolicy.Spec.Action = "Allow"
+	case "audit":
+		secPolicy.Spec.Action = "Audit"
+	case "block":
+		secPolicy.Spec.Action = "Block"
+	case "":
+		secPolicy.Spec.Action = "Block" // by default
+	}
+
+	// add identities
+
+	secPolicy.Spec.Selector.Identities = []string{"namespaceName=" + policy.Metadata.Namespace}
+
+	for k, v := range secPolicy.Spec.Selector.MatchLabels {
+		secPolicy.Spec.Selector.Identities = append(secPolicy.Spec.Selector.Identities, k+"="+v)
+	}
+
+	sort.Slice(secPolicy.Spec.Selector.Identities, func(i, j int) bool {
+		return secPolicy.Spec.Selector.Identities[i] < secPolicy.Spec.Selector.Identities[j]
+	})
+
+	// add severities, tags, messages, and actions
+
+	if len(secPolicy.Spec.Process.MatchPaths) > 0 {
+		for idx, path := range secPolicy.Spec.Process.MatchPaths {
+			if path.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(path.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(path.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(path.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchPaths[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.Process.MatchDirectories) > 0 {
+		for idx, dir := range secPolicy.Spec.Process.MatchDirectories {
+			if dir.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(dir.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(dir.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(dir.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchDirectories[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.Process.MatchPatterns) > 0 {
+		for idx, pat := range secPolicy.Spec.Process.MatchPatterns {
+			if pat.Severity == 0 {
+				if secPolicy.Spec.Process.Severity != 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Process.Severity
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(pat.Tags) == 0 {
+				if len(secPolicy.Spec.Process.Tags) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Process.Tags
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(pat.Message) == 0 {
+				if len(secPolicy.Spec.Process.Message) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Process.Message
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(pat.Action) == 0 {
+				if len(secPolicy.Spec.Process.Action) > 0 {
+					secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Process.Action
+				} else {
+					secPolicy.Spec.Process.MatchPatterns[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.File.MatchPaths) > 0 {
+		for idx, path := range secPolicy.Spec.File.MatchPaths {
+			if path.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(path.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(path.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(path.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchPaths[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.File.MatchDirectories) > 0 {
+		for idx, dir := range secPolicy.Spec.File.MatchDirectories {
+			if dir.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(dir.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(dir.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(dir.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchDirectories[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.File.MatchPatterns) > 0 {
+		for idx, pat := range secPolicy.Spec.File.MatchPatterns {
+			if pat.Severity == 0 {
+				if secPolicy.Spec.File.Severity != 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.File.Severity
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(pat.Tags) == 0 {
+				if len(secPolicy.Spec.File.Tags) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.File.Tags
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(pat.Message) == 0 {
+				if len(secPolicy.Spec.File.Message) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.File.Message
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(pat.Action) == 0 {
+				if len(secPolicy.Spec.File.Action) > 0 {
+					secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.File.Action
+				} else {
+					secPolicy.Spec.File.MatchPatterns[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.Network.MatchProtocols) > 0 {
+		for idx, proto := range secPolicy.Spec.Network.MatchProtocols {
+			if proto.Severity == 0 {
+				if secPolicy.Spec.Network.Severity != 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Network.Severity
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(proto.Tags) == 0 {
+				if len(secPolicy.Spec.Network.Tags) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Network.Tags
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(proto.Message) == 0 {
+				if len(secPolicy.Spec.Network.Message) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Network.Message
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(proto.Action) == 0 {
+				if len(secPolicy.Spec.Network.Action) > 0 {
+					secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Network.Action
+				} else {
+					secPolicy.Spec.Network.MatchProtocols[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.Capabilities.MatchCapabilities) > 0 {
+		for idx, cap := range secPolicy.Spec.Capabilities.MatchCapabilities {
+			if cap.Severity == 0 {
+				if secPolicy.Spec.Capabilities.Severity != 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Capabilities.Severity
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(cap.Tags) == 0 {
+				if len(secPolicy.Spec.Capabilities.Tags) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Capabilities.Tags
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(cap.Message) == 0 {
+				if len(secPolicy.Spec.Capabilities.Message) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Capabilities.Message
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(cap.Action) == 0 {
+				if len(secPolicy.Spec.Capabilities.Action) > 0 {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Capabilities.Action
+				} else {
+					secPolicy.Spec.Capabilities.MatchCapabilities[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.Syscalls.MatchSyscalls) > 0 {
+		for idx, sys := range secPolicy.Spec.Syscalls.MatchSyscalls {
+			if sys.Severity == 0 {
+				if secPolicy.Spec.Syscalls.Severity != 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Severity = secPolicy.Spec.Syscalls.Severity
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(sys.Tags) == 0 {
+				if len(secPolicy.Spec.Syscalls.Tags) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Tags = secPolicy.Spec.Syscalls.Tags
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(sys.Message) == 0 {
+				if len(secPolicy.Spec.Syscalls.Message) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Message = secPolicy.Spec.Syscalls.Message
+				} else {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(sys.Action) == 0 {
+				if len(secPolicy.Spec.Syscalls.Action) > 0 {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = secPolicy.Spec.Syscalls.Action
+				} else if secPolicy.Spec.Action != "Allow" {
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = secPolicy.Spec.Action
+				} else {
+					// syscalls cannot be allowed (whitelisted), so they are audited
+					secPolicy.Spec.Syscalls.MatchSyscalls[idx].Action = "Audit"
+				}
+			}
+		}
+	}
+
+	if len(secPolicy.Spec.SELinux.MatchVolumeMounts) > 0 {
+		for idx, se := range secPolicy.Spec.SELinux.MatchVolumeMounts {
+			if se.Severity == 0 {
+				if secPolicy.Spec.SELinux.Severity != 0 {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Severity = secPolicy.Spec.SELinux.Severity
+				} else {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Severity = secPolicy.Spec.Severity
+				}
+			}
+
+			if len(se.Tags) == 0 {
+				if len(secPolicy.Spec.SELinux.Tags) > 0 {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Tags = secPolicy.Spec.SELinux.Tags
+				} else {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Tags = secPolicy.Spec.Tags
+				}
+			}
+
+			if len(se.Message) == 0 {
+				if len(secPolicy.Spec.SELinux.Message) > 0 {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Message = secPolicy.Spec.SELinux.Message
+				} else {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Message = secPolicy.Spec.Message
+				}
+			}
+
+			if len(se.Action) == 0 {
+				if len(secPolicy.Spec.SELinux.Action) > 0 {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Action = secPolicy.Spec.SELinux.Action
+				} else {
+					secPolicy.Spec.SELinux.MatchVolumeMounts[idx].Action = secPolicy.Spec.Action
+				}
+			}
+		}
+	}
+
+	return secPolicy, nil
+}
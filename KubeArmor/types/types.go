@@ -63,6 +63,8 @@ type EndPoint struct {
 	SELinuxProfiles map[string]string `json:"selinuxProfiles"`
 	HostVolumes     []HostVolumeMount `json:"hostVolumes"`
 
+	SeccompProfiles []string `json:"seccompProfiles"`
+
 	SecurityPolicies []SecurityPolicy `json:"securityPolicies"`
 
 	// == //
@@ -95,6 +97,7 @@ type Node struct {
 
 	EnableKubeArmorPolicy     bool `json:"enableKubeArmorPolicy"`
 	EnableKubeArmorHostPolicy bool `json:"enableKubeArmorHostPolicy"`
+	EnableSeccompEnforcer     bool `json:"enableSeccompEnforcer"`
 
 	// == //
 
@@ -116,6 +119,12 @@ type K8sNodeEvent struct {
 	Object v1.Node `json:"object"`
 }
 
+// K8sConfigMapEvent Structure
+type K8sConfigMapEvent struct {
+	Type   string       `json:"type"`
+	Object v1.ConfigMap `json:"object"`
+}
+
 // K8sPod Structure
 type K8sPod struct {
 	Metadata    map[string]string
@@ -123,6 +132,9 @@ type K8sPod struct {
 	Labels      map[string]string
 	Containers  map[string]string
 	HostVolumes []HostVolumeMount
+
+	// container name -> seccomp profile (e.g., localhost/kubearmor/..., runtime/default)
+	SeccompProfiles map[string]string
 }
 
 // K8sPodEvent Structure
@@ -176,6 +188,42 @@ type K8sKubeArmorHostPolicies struct {
 // == Logging == //
 // ============= //
 
+// FileEvent Structure
+type FileEvent struct {
+	Path  string   `json:"path,omitempty"`
+	Flags []string `json:"flags,omitempty"`
+	FD    int32    `json:"fd,omitempty"`
+}
+
+// NetworkEvent Structure
+type NetworkEvent struct {
+	Family   string `json:"family,omitempty"`
+	Addr     string `json:"addr,omitempty"`
+	Port     int32  `json:"port,omitempty"`
+	Protocol int32  `json:"protocol,omitempty"`
+	SockType string `json:"sockType,omitempty"`
+	FD       int32  `json:"fd,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// ProcessEvent Structure
+type ProcessEvent struct {
+	ExecPath string   `json:"execPath,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	Comm     string   `json:"comm,omitempty"`
+	Flags    []string `json:"flags,omitempty"`
+	FD       int32    `json:"fd,omitempty"`
+}
+
+// ProcessAncestor Structure
+type ProcessAncestor struct {
+	HostPID  int32  `json:"hostPid"`
+	PID      int32  `json:"pid"`
+	Comm     string `json:"comm,omitempty"`
+	ExecPath string `json:"execPath,omitempty"`
+	Exited   bool   `json:"exited,omitempty"`
+}
+
 // Log Structure
 type Log struct {
 	// updated time
@@ -217,6 +265,14 @@ type Log struct {
 	Action    string `json:"action,omitempty"`
 	Result    string `json:"result"`
 
+	// structured fields of Resource and Data
+	File    *FileEvent    `json:"file,omitempty"`
+	Network *NetworkEvent `json:"network,omitempty"`
+	Process *ProcessEvent `json:"process,omitempty"`
+
+	// exec chain from the oldest ancestor to the process itself
+	Ancestors []ProcessAncestor `json:"ancestors,omitempty"`
+
 	// == //
 
 	PolicyEnabled int `json:"policyEnabled,omitempty"`
@@ -245,6 +301,12 @@ type MatchPolicy struct {
 	Regexp *regexp.Regexp
 	Native bool
 
+	// arguments required for a process to match
+	Args []string
+
+	// syscalls to match for the Syscall operation
+	Syscalls []string
+
 	Action string
 }
 
@@ -279,6 +341,7 @@ type MatchSourceType struct {
 type ProcessPathType struct {
 	Path       string            `json:"path"`
 	OwnerOnly  bool              `json:"ownerOnly,omitempty"`
+	Args       []string          `json:"args,omitempty"`
 	FromSource []MatchSourceType `json:"fromSource,omitempty"`
 
 	Severity int      `json:"severity,omitempty"`
@@ -416,6 +479,28 @@ type CapabilitiesType struct {
 	Action   string   `json:"action,omitempty"`
 }
 
+// SyscallMatchType Structure
+type SyscallMatchType struct {
+	Syscalls   []string          `json:"syscall"`
+	Path       string            `json:"path,omitempty"`
+	FromSource []MatchSourceType `json:"fromSource,omitempty"`
+
+	Severity int      `json:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Action   string   `json:"action,omitempty"`
+}
+
+// SyscallsType Structure
+type SyscallsType struct {
+	MatchSyscalls []SyscallMatchType `json:"matchSyscalls,omitempty"`
+
+	Severity int      `json:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Action   string   `json:"action,omitempty"`
+}
+
 // MatchVolumeMountType Structure
 type MatchVolumeMountType struct {
 	Path      string `json:"path,omitempty"`
@@ -446,6 +531,7 @@ type SecuritySpec struct {
 	File         FileType         `json:"file,omitempty"`
 	Network      NetworkType      `json:"network,omitempty"`
 	Capabilities CapabilitiesType `json:"capabilities,omitempty"`
+	Syscalls     SyscallsType     `json:"syscalls,omitempty"`
 
 	AppArmor string      `json:"apparmor,omitempty"`
 	SELinux  SELinuxType `json:"selinux,omitempty"`
@@ -480,6 +566,7 @@ type HostSecuritySpec struct {
 	File         FileType         `json:"file,omitempty"`
 	Network      NetworkType      `json:"network,omitempty"`
 	Capabilities CapabilitiesType `json:"capabilities,omitempty"`
+	Syscalls     SyscallsType     `json:"syscalls,omitempty"`
 
 	AppArmor string `json:"apparmor,omitempty"`
 
@@ -516,7 +603,20 @@ type PidNode struct {
 
 	Comm     string
 	ExecPath string
+	Args     []string
 
 	Exited     bool
 	ExitedTime time.Time
 }
+
+// ============ //
+// == Health == //
+// ============ //
+
+// HealthStatus Structure
+type HealthStatus struct {
+	Name    string            `json:"name"`
+	Healthy bool              `json:"healthy"`
+	Message string            `json:"message,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
@@ -31,6 +31,9 @@ type ProcessPathType struct {
 	// +kubebuilder:validation:Optional
 	OwnerOnly bool `json:"ownerOnly,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	Args []string `json:"args,omitempty"`
+
 	// +kubebuilder:validation:optional
 	FromSource []MatchSourceType `json:"fromSource,omitempty"`
 
@@ -233,6 +236,43 @@ type CapabilitiesType struct {
 	Action ActionType `json:"action,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=unlink;unlinkat;rename;renameat;renameat2;chmod;fchmodat;chown;fchown;lchown;fchownat;mount;umount2;ptrace;setuid;setgid;setns;unshare
+type MatchSyscallStringType string
+
+// +kubebuilder:validation:Enum=Audit;Block
+type SyscallActionType string
+
+type MatchSyscallType struct {
+	Syscalls []MatchSyscallStringType `json:"syscall"`
+
+	// +kubebuilder:validation:optional
+	Path MatchPathType `json:"path,omitempty"`
+	// +kubebuilder:validation:optional
+	FromSource []MatchSourceType `json:"fromSource,omitempty"`
+
+	// +kubebuilder:validation:optional
+	Severity SeverityType `json:"severity,omitempty"`
+	// +kubebuilder:validation:optional
+	Tags []string `json:"tags,omitempty"`
+	// +kubebuilder:validation:optional
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:optional
+	Action SyscallActionType `json:"action,omitempty"`
+}
+
+type SyscallsType struct {
+	MatchSyscalls []MatchSyscallType `json:"matchSyscalls"`
+
+	// +kubebuilder:validation:optional
+	Severity SeverityType `json:"severity,omitempty"`
+	// +kubebuilder:validation:optional
+	Tags []string `json:"tags,omitempty"`
+	// +kubebuilder:validation:optional
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:optional
+	Action SyscallActionType `json:"action,omitempty"`
+}
+
 // +kubebuilder:validation:Enum=Allow;Audit;Block
 type ActionType string
 
@@ -244,6 +284,8 @@ type KubeArmorHostPolicySpec struct {
 	File         FileType         `json:"file,omitempty"`
 	Network      NetworkType      `json:"network,omitempty"`
 	Capabilities CapabilitiesType `json:"capabilities,omitempty"`
+	// +kubebuilder:validation:optional
+	Syscalls SyscallsType `json:"syscalls,omitempty"`
 
 	AppArmor string `json:"apparmor,omitempty"`
 
@@ -217,6 +217,7 @@ func (in *KubeArmorHostPolicySpec) DeepCopyInto(out *KubeArmorHostPolicySpec) {
 	in.File.DeepCopyInto(&out.File)
 	in.Network.DeepCopyInto(&out.Network)
 	in.Capabilities.DeepCopyInto(&out.Capabilities)
+	in.Syscalls.DeepCopyInto(&out.Syscalls)
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]string, len(*in))
@@ -314,6 +315,36 @@ func (in *MatchSourceType) DeepCopy() *MatchSourceType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchSyscallType) DeepCopyInto(out *MatchSyscallType) {
+	*out = *in
+	if in.Syscalls != nil {
+		in, out := &in.Syscalls, &out.Syscalls
+		*out = make([]MatchSyscallStringType, len(*in))
+		copy(*out, *in)
+	}
+	if in.FromSource != nil {
+		in, out := &in.FromSource, &out.FromSource
+		*out = make([]MatchSourceType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchSyscallType.
+func (in *MatchSyscallType) DeepCopy() *MatchSyscallType {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchSyscallType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkType) DeepCopyInto(out *NetworkType) {
 	*out = *in
@@ -391,6 +422,11 @@ func (in *ProcessDirectoryType) DeepCopy() *ProcessDirectoryType {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessPathType) DeepCopyInto(out *ProcessPathType) {
 	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.FromSource != nil {
 		in, out := &in.FromSource, &out.FromSource
 		*out = make([]MatchSourceType, len(*in))
@@ -473,3 +509,30 @@ func (in *ProcessType) DeepCopy() *ProcessType {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyscallsType) DeepCopyInto(out *SyscallsType) {
+	*out = *in
+	if in.MatchSyscalls != nil {
+		in, out := &in.MatchSyscalls, &out.MatchSyscalls
+		*out = make([]MatchSyscallType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyscallsType.
+func (in *SyscallsType) DeepCopy() *SyscallsType {
+	if in == nil {
+		return nil
+	}
+	out := new(SyscallsType)
+	in.DeepCopyInto(out)
+	return out
+}